@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/Jisin0/autofilterbot/internal/catalog"
+	"github.com/Jisin0/autofilterbot/internal/core"
+	"github.com/Jisin0/autofilterbot/internal/database"
+	"go.uber.org/zap"
+)
+
+// catalogFlags are the flags shared by the export and import subcommands.
+type catalogFlags struct {
+	mongodbUri  *string
+	databaseUrl *string
+	format      *string
+}
+
+func newCatalogFlagSet(name string) (*flag.FlagSet, catalogFlags) {
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+
+	return fs, catalogFlags{
+		mongodbUri:  fs.String("mongodb-uri", "", "mongodb uri for database (use env for additional uris)"),
+		databaseUrl: fs.String("database-url", "", "url of the database, use sqlite://path/to/file.db for sqlite (defaults to mongodb-uri)"),
+		format:      fs.String("format", "", "format of the catalog, jsonl or csv (defaults to the file extension or jsonl)"),
+	}
+}
+
+func (c catalogFlags) openDatabase(ctx context.Context) database.Database {
+	db, err := core.OpenDatabase(ctx, core.RunAppOptions{MongodbURI: *c.mongodbUri, DatabaseURL: *c.databaseUrl}, zap.NewNop())
+	if err != nil {
+		fatal("database setup failed:", err)
+	}
+
+	return db
+}
+
+// catalogFormat returns the format set by the -format flag or guesses it from the file name.
+func (c catalogFlags) catalogFormat(fileName string) catalog.Format {
+	if *c.format == "" {
+		return catalog.FormatFromFileName(fileName)
+	}
+
+	f, err := catalog.ParseFormat(*c.format)
+	if err != nil {
+		fatal(err)
+	}
+
+	return f
+}
+
+// runExport handles the export subcommand which writes all saved files to stdout or a file.
+func runExport(args []string) {
+	fs, flags := newCatalogFlagSet("export")
+	output := fs.String("o", "", "file to write to (defaults to stdout)")
+
+	fs.Parse(args)
+
+	ctx := context.Background()
+	db := flags.openDatabase(ctx)
+
+	defer db.Shutdown()
+
+	var w io.Writer = os.Stdout
+
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			fatal(err)
+		}
+
+		defer f.Close()
+
+		w = f
+	}
+
+	cursor, err := db.GetAllFiles()
+	if err != nil {
+		fatal("get all files failed:", err)
+	}
+
+	defer cursor.Close(ctx)
+
+	n, err := catalog.Export(ctx, cursor, w, flags.catalogFormat(*output))
+	if err != nil {
+		fatal("export failed:", err)
+	}
+
+	fmt.Fprintf(os.Stderr, "exported %d files\n", n)
+}
+
+// runImport handles the import subcommand which saves all files from a catalog file or stdin.
+func runImport(args []string) {
+	fs, flags := newCatalogFlagSet("import")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: autofilterbot import [flags] [file]")
+		fs.PrintDefaults()
+	}
+
+	fs.Parse(args)
+
+	var (
+		r        io.Reader = os.Stdin
+		fileName string
+	)
+
+	if fileName = fs.Arg(0); fileName != "" {
+		f, err := os.Open(fileName)
+		if err != nil {
+			fatal(err)
+		}
+
+		defer f.Close()
+
+		r = f
+	}
+
+	ctx := context.Background()
+	db := flags.openDatabase(ctx)
+
+	defer db.Shutdown()
+
+	res, err := catalog.Import(ctx, r, flags.catalogFormat(fileName), db.SaveFile)
+
+	fmt.Fprintf(os.Stderr, "inserted: %d\nduplicate: %d\ninvalid: %d\n", res.Inserted, res.Duplicate, res.Invalid)
+
+	if err != nil {
+		fatal("import failed:", err)
+	}
+}
+
+func fatal(a ...any) {
+	fmt.Fprintln(os.Stderr, a...)
+	os.Exit(1)
+}
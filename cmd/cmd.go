@@ -2,13 +2,30 @@ package cmd
 
 import (
 	"flag"
+	"os"
 
 	"github.com/Jisin0/autofilterbot/internal/core"
 )
 
 // Execute acts as the entry point of the application, it parses command line arguments and then runs the application.
+// The export, import and import-legacy subcommands move files in and out of the database without starting the bot.
 func Execute() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "export":
+			runExport(os.Args[2:])
+			return
+		case "import":
+			runImport(os.Args[2:])
+			return
+		case "import-legacy":
+			runImportLegacy(os.Args[2:])
+			return
+		}
+	}
+
 	mongodbUri := flag.String("mongodb-uri", "", "mongodb uri for database (use env for additional uris)")
+	databaseUrl := flag.String("database-url", "", "url of the database, use sqlite://path/to/file.db for sqlite (defaults to mongodb-uri)")
 	botToken := flag.String("bot-token", "", "bot token obtained from @botfather")
 	logLevel := flag.String("log-level", "info", "level of logs to be shown")
 	noOutput := flag.Bool("no-output", false, "disable console logs (does not affect log file)")
@@ -18,6 +35,7 @@ func Execute() {
 	core.Run(
 		core.RunAppOptions{
 			MongodbURI:         *mongodbUri,
+			DatabaseURL:        *databaseUrl,
 			LogLevel:           *logLevel,
 			BotToken:           *botToken,
 			DisableConsoleLogs: *noOutput,
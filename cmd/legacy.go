@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/Jisin0/autofilterbot/internal/catalog"
+	mongodriver "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/x/mongo/driver/connstring"
+)
+
+// runImportLegacy handles the import-legacy subcommand which copies files from the database of a python autofilter bot.
+func runImportLegacy(args []string) {
+	fs, flags := newCatalogFlagSet("import-legacy")
+	legacyUri := fs.String("legacy-uri", "", "mongodb uri of the python bot's database")
+	legacyDatabase := fs.String("legacy-database", "", "name of the python bot's database (defaults to the one in legacy-uri)")
+	legacyCollection := fs.String("legacy-collection", catalog.DefaultLegacyCollection, "name of the python bot's files collection")
+	batchSize := fs.Int("batch-size", 100, "number of files saved at once")
+	dryRun := fs.Bool("dry-run", false, "only report what would be imported without saving anything")
+
+	fs.Parse(args)
+
+	if *legacyUri == "" {
+		fatal("legacy-uri is required")
+	}
+
+	if *legacyDatabase == "" {
+		cs, err := connstring.Parse(*legacyUri)
+		if err != nil {
+			fatal("invalid legacy-uri:", err)
+		}
+
+		if cs.Database == "" {
+			fatal("legacy-database is required when legacy-uri doesn't contain a database name")
+		}
+
+		*legacyDatabase = cs.Database
+	}
+
+	ctx := context.Background()
+
+	client, err := mongodriver.Connect(ctx, options.Client().ApplyURI(*legacyUri))
+	if err != nil {
+		fatal("connect to legacy database failed:", err)
+	}
+
+	defer client.Disconnect(ctx)
+
+	coll := client.Database(*legacyDatabase).Collection(*legacyCollection)
+
+	total, err := coll.EstimatedDocumentCount(ctx)
+	if err != nil {
+		fatal("count legacy files failed:", err)
+	}
+
+	cursor, err := coll.Find(ctx, map[string]any{})
+	if err != nil {
+		fatal("read legacy files failed:", err)
+	}
+
+	defer cursor.Close(ctx)
+
+	db := flags.openDatabase(ctx)
+
+	defer db.Shutdown()
+
+	if *dryRun {
+		fmt.Fprintln(os.Stderr, "dry run: no files will be saved")
+	}
+
+	start := time.Now()
+
+	res, err := catalog.ImportLegacy(ctx, cursor, db, catalog.LegacyImportOptions{
+		BatchSize: *batchSize,
+		DryRun:    *dryRun,
+		OnProgress: func(r catalog.ImportResult) {
+			fmt.Fprintf(os.Stderr, "\rprocessed %d/%d files (inserted: %d, duplicate: %d, invalid: %d)", r.Total(), total, r.Inserted, r.Duplicate, r.Invalid)
+		},
+	})
+
+	fmt.Fprintf(os.Stderr, "\ninserted: %d\nduplicate: %d\ninvalid: %d\ntook: %s\n", res.Inserted, res.Duplicate, res.Invalid, time.Since(start).Round(time.Second))
+
+	if err != nil {
+		fatal("import failed:", err)
+	}
+}
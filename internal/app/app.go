@@ -8,9 +8,11 @@ import (
 
 	"github.com/Jisin0/autofilterbot/internal/cache"
 	"github.com/Jisin0/autofilterbot/internal/config"
-	"github.com/Jisin0/autofilterbot/internal/database/mongo"
+	"github.com/Jisin0/autofilterbot/internal/database"
 	"github.com/Jisin0/autofilterbot/internal/index"
+	"github.com/Jisin0/autofilterbot/internal/migrate"
 	"github.com/Jisin0/autofilterbot/pkg/autodelete"
+	"github.com/Jisin0/autofilterbot/pkg/fuzzy"
 	"github.com/Jisin0/autofilterbot/pkg/panel"
 	"github.com/Jisin0/autofilterbot/pkg/shortener"
 	"github.com/PaulSonOfLars/gotgbot/v2"
@@ -19,7 +21,7 @@ import (
 
 // App wraps various individual components of the app to orchestrate application processes.
 type App struct {
-	DB          *mongo.Client
+	DB          database.Database
 	Log         *zap.Logger
 	StartTime   time.Time
 	Bot         *gotgbot.Bot
@@ -27,13 +29,19 @@ type App struct {
 	Config      *config.Config
 	Admins      []int64
 	ConfigPanel *panel.Panel
+	// GroupPanel is the config panel opened in groups to override settings for a single group.
+	GroupPanel *panel.Panel
 
 	AutoDelete   *autodelete.Manager
 	Shortener    *shortener.Shortener
 	IndexManager *index.Manager
+	// MigrationManager runs migrations of files between storages.
+	MigrationManager *migrate.Manager
+	// Vocabulary contains words from all file names used to correct misspelled queries.
+	Vocabulary *fuzzy.Vocabulary
 }
 
-func (a *App) GetDB() *mongo.Client {
+func (a *App) GetDB() database.Database {
 	return a.DB
 }
 
@@ -72,3 +80,11 @@ func (a *App) GetShortener() *shortener.Shortener {
 func (a *App) GetIndexManager() *index.Manager {
 	return a.IndexManager
 }
+
+func (a *App) GetVocabulary() *fuzzy.Vocabulary {
+	return a.Vocabulary
+}
+
+func (a *App) GetMigrationManager() *migrate.Manager {
+	return a.MigrationManager
+}
@@ -17,6 +17,7 @@ type FilesFromCursorOptions interface {
 }
 
 // FilesFromCursor loops through a cursor and outputs an array of files.
+// Upto GetMaxPages pages are loaded and loading stops after the page on which GetMaxResults files were reached.
 func FilesFromCursor(ctx context.Context, c database.Cursor, opts FilesFromCursorOptions) ([]Files, error) {
 	var (
 		totalCount int
@@ -41,6 +42,7 @@ func FilesFromCursor(ctx context.Context, c database.Cursor, opts FilesFromCurso
 			}
 
 			row = append(row, File{File: f})
+			totalCount++
 		}
 
 		if len(row) != 0 {
@@ -51,10 +53,21 @@ func FilesFromCursor(ctx context.Context, c database.Cursor, opts FilesFromCurso
 			return totalFiles, nil
 		}
 
-		if totalCount >= opts.GetMaxResults() { // only checks after completing a page. should this behaviour be changed?
+		if totalCount >= opts.GetMaxResults() { // only checks after completing a page so the last page is always full
 			break
 		}
 	}
 
 	return totalFiles, nil
 }
+
+// PrefetchLimit returns the number of files that FilesFromCursor loads at most, used to limit the search query.
+func PrefetchLimit(opts FilesFromCursorOptions) int {
+	var (
+		perPage = opts.GetMaxPerPage()
+		// the page that reaches max results is completed
+		pages = min(opts.GetMaxPages(), (opts.GetMaxResults()+perPage-1)/perPage)
+	)
+
+	return pages * perPage
+}
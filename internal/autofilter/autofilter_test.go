@@ -0,0 +1,99 @@
+package autofilter_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/Jisin0/autofilterbot/internal/autofilter"
+	"github.com/Jisin0/autofilterbot/internal/database"
+	"github.com/Jisin0/autofilterbot/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+type cursorOptions struct {
+	maxResults, maxPages, maxPerPage int
+}
+
+func (o cursorOptions) GetMaxResults() int { return o.maxResults }
+func (o cursorOptions) GetMaxPages() int   { return o.maxPages }
+func (o cursorOptions) GetMaxPerPage() int { return o.maxPerPage }
+
+func testDocs(n int) []interface{} {
+	docs := make([]interface{}, n)
+	for i := range docs {
+		docs[i] = model.File{UniqueId: fmt.Sprint(i), FileName: fmt.Sprintf("File %d.mkv", i)}
+	}
+
+	return docs
+}
+
+func TestFilesFromCursor(t *testing.T) {
+	tests := []struct {
+		name     string
+		docs     int
+		opts     cursorOptions
+		expected []int
+	}{
+		{name: "max results", docs: 100, opts: cursorOptions{maxResults: 25, maxPages: 5, maxPerPage: 10}, expected: []int{10, 10, 10}},
+		{name: "max pages", docs: 100, opts: cursorOptions{maxResults: 50, maxPages: 2, maxPerPage: 10}, expected: []int{10, 10}},
+		{name: "cursor exhausted", docs: 13, opts: cursorOptions{maxResults: 50, maxPages: 5, maxPerPage: 5}, expected: []int{5, 5, 3}},
+		{name: "empty", docs: 0, opts: cursorOptions{maxResults: 50, maxPages: 5, maxPerPage: 5}, expected: nil},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			pages, err := autofilter.FilesFromCursor(context.Background(), database.NewSliceCursor(testDocs(tc.docs)), tc.opts)
+			assert.NoError(t, err)
+
+			var sizes []int
+			for _, p := range pages {
+				sizes = append(sizes, len(p))
+			}
+
+			assert.Equal(t, tc.expected, sizes)
+
+			if tc.docs >= 100 {
+				assert.Equal(t, autofilter.PrefetchLimit(tc.opts), len(pages)*tc.opts.maxPerPage, "prefetch limit should match the files loaded")
+			}
+		})
+	}
+}
+
+func TestSearchResultAddPage(t *testing.T) {
+	assert := assert.New(t)
+
+	files := func(ids ...string) autofilter.Files {
+		var f autofilter.Files
+		for _, id := range ids {
+			f = append(f, autofilter.File{File: model.File{UniqueId: id}})
+		}
+
+		return f
+	}
+
+	r := &autofilter.SearchResult{Files: []autofilter.Files{files("a", "b")}, Fetched: 2}
+	assert.True(r.HasMore())
+	assert.Equal(database.SearchFilesOpts{Skip: 2, Limit: 2}, r.SearchOpts(2))
+
+	r.AddPage(files("c", "d"), 2, false)
+	assert.True(r.HasMore())
+	assert.Equal(4, r.Fetched)
+
+	// views only apply to fetched files
+	assert.True(r.SetView(autofilter.View{Sort: autofilter.SortName}, 2, false))
+	assert.False(r.HasMore())
+	assert.True(r.SetView(autofilter.View{}, 2, false))
+	assert.True(r.HasMore())
+
+	r.AddPage(files("e"), 2, false)
+	assert.False(r.HasMore())
+	assert.Equal(5, r.Fetched)
+	assert.Equal([][]string{{"a", "b"}, {"c", "d"}, {"e"}}, pageIds(r.Files))
+	assert.Len(r.All, 5, "all files should include fetched pages")
+
+	r = &autofilter.SearchResult{Files: []autofilter.Files{files("a", "b")}, Fetched: 2}
+	r.AddPage(nil, 2, false)
+	assert.False(r.HasMore())
+	assert.Len(r.Files, 1)
+}
@@ -20,43 +20,102 @@ type File struct {
 }
 
 // Process returns a slice of buttons to be used in message markup.
-func (files Files) Process(chatId int64, botUsername string, opts ProcessFilesOptions) [][]gotgbot.InlineKeyboardButton {
-	return ProcessFiles(files, chatId, botUsername, opts)
+func (files Files) Process(chatId int64, botUsername, resultId string, pageIndex int, opts ProcessFilesOptions) [][]gotgbot.InlineKeyboardButton {
+	return ProcessFiles(files, chatId, botUsername, resultId, pageIndex, opts)
 }
 
 type ProcessFilesOptions interface {
 	GetButtonTemplate() string
 	GetSizeButton() bool
 	GetShortener() shortener.Shortener
+	GetGroupResults() bool
+	// SignStartData returns signed start data of a file link containing the payload.
+	SignStartData(payload []byte) string
 }
 
 // ProcessFiles changes files into a keboard slice to be used as markup in a message.
-func ProcessFiles(files Files, chatId int64, botUsername string, opts ProcessFilesOptions) [][]gotgbot.InlineKeyboardButton {
+// If results are grouped, files with the same title and season are shown as a single button that opens a group menu.
+func ProcessFiles(files Files, chatId int64, botUsername, resultId string, pageIndex int, opts ProcessFilesOptions) [][]gotgbot.InlineKeyboardButton {
+	if !opts.GetGroupResults() {
+		result := make([][]gotgbot.InlineKeyboardButton, 0, len(files))
+
+		for _, f := range files {
+			result = append(result, fileButton(f, f.FileName, chatId, botUsername, opts))
+		}
+
+		return result
+	}
+
+	var (
+		groups = GroupFiles(files)
+		result = make([][]gotgbot.InlineKeyboardButton, 0, len(groups))
+	)
+
+	for i, g := range groups {
+		if len(g.Files) == 1 {
+			result = append(result, fileButton(g.Files[0], g.Files[0].FileName, chatId, botUsername, opts))
+			continue
+		}
+
+		result = append(result, []gotgbot.InlineKeyboardButton{{
+			Text:         "📁 " + g.Label(),
+			CallbackData: fmt.Sprintf("navg|%s_%d_%d", resultId, pageIndex, i),
+		}})
+	}
+
+	return result
+}
+
+// GroupMenu returns the buttons of each file in a group labelled by their quality or episode.
+func (g Group) GroupMenu(chatId int64, botUsername string, opts ProcessFilesOptions) [][]gotgbot.InlineKeyboardButton {
 	var (
-		hasShortener = opts.GetShortener().ApiKey != ""
-		result       = make([][]gotgbot.InlineKeyboardButton, 0, len(files))
+		files  = g.SortedFiles()
+		result = make([][]gotgbot.InlineKeyboardButton, 0, len(files))
 	)
 
 	for _, f := range files {
-		url := fmt.Sprintf("https://t.me/%s?start=%s", botUsername, URLData{
+		result = append(result, fileButton(f, QualityLabel(f), chatId, botUsername, opts))
+	}
+
+	return result
+}
+
+// fileButton returns the row of buttons for a single file using name as the display name.
+// Files in the bot's private chat are sent directly from a callback instead of a start url.
+func fileButton(f File, name string, chatId int64, botUsername string, opts ProcessFilesOptions) []gotgbot.InlineKeyboardButton {
+	button := gotgbot.InlineKeyboardButton{}
+
+	if IsPrivateChat(chatId) {
+		button.CallbackData = SendFileDataPrefix + f.UniqueId
+	} else {
+		button.Url = fmt.Sprintf("https://t.me/%s?start=%s", botUsername, opts.SignStartData(URLData{
 			FileUniqueId: f.UniqueId,
 			ChatId:       chatId,
-			HasShortener: hasShortener,
-		}.Encode())
-		size := functions.FileSizeToString(f.FileSize)
-
-		if opts.GetSizeButton() {
-			result = append(result, []gotgbot.InlineKeyboardButton{{Text: f.FileName, CallbackData: "fdetails|" + f.UniqueId}, {Text: size, Url: url}})
-		} else {
-			text := format.KeyValueFormat(opts.GetButtonTemplate(), map[string]string{
-				"file_name": f.FileName,
-				"file_size": size,
-			})
-			result = append(result, []gotgbot.InlineKeyboardButton{{Text: text, Url: url}})
-		}
+			HasShortener: opts.GetShortener().ApiKey != "",
+		}.Bytes()))
 	}
 
-	return result
+	size := functions.FileSizeToString(f.FileSize)
+
+	if opts.GetSizeButton() {
+		button.Text = size
+		return []gotgbot.InlineKeyboardButton{{Text: name, CallbackData: "fdetails|" + f.UniqueId}, button}
+	}
+
+	button.Text = format.KeyValueFormat(opts.GetButtonTemplate(), map[string]string{
+		"file_name": name,
+		"file_size": size,
+	})
+
+	return []gotgbot.InlineKeyboardButton{button}
+}
+
+// SendFileDataPrefix is the prefix of callback data that sends a file directly, followed by the unique id of the file.
+const SendFileDataPrefix = "getf|"
+
+// IsPrivateChat reports whether chatId is a private chat with a user, ids of groups and channels are negative.
+func IsPrivateChat(chatId int64) bool {
+	return chatId > 0
 }
 
 // SelectMenu returns a keyboard with to select files from.
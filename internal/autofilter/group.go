@@ -0,0 +1,183 @@
+package autofilter
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Group is a set of files with the same title and season, they usually differ only by quality or episode.
+type Group struct {
+	// Title of the release or the name of the file if it has no release metadata.
+	Title string
+	// Season number of a series.
+	Season int
+	// Year of release of a movie.
+	Year int
+	// Files of the group in the order they were found.
+	Files Files
+}
+
+// groupKey returns the key used to group a file, files without a title get a key of their own.
+func groupKey(f File) string {
+	if !hasTitle(f) {
+		return "\x00" + f.UniqueId
+	}
+
+	return fmt.Sprintf("%s\x00%d", strings.ToLower(f.Release.Title), f.Release.Season)
+}
+
+// GroupFiles groups files by release title and season, groups are ordered by their first file.
+func GroupFiles(files Files) []Group {
+	var (
+		groups  []Group
+		indexes = make(map[string]int)
+	)
+
+	for _, f := range files {
+		key := groupKey(f)
+
+		i, ok := indexes[key]
+		if !ok {
+			g := Group{Title: f.FileName}
+			if hasTitle(f) {
+				g.Title, g.Season = f.Release.Title, f.Release.Season
+			}
+
+			i = len(groups)
+			indexes[key] = i
+			groups = append(groups, g)
+		}
+
+		if groups[i].Year == 0 && f.Release != nil {
+			groups[i].Year = f.Release.Year
+		}
+
+		groups[i].Files = append(groups[i].Files, f)
+	}
+
+	return groups
+}
+
+// GroupPages regroups pages of files so that each page has at most perPage groups and no group is split between pages.
+func GroupPages(pages []Files, perPage int) []Files {
+	var all Files
+	for _, p := range pages {
+		all = append(all, p...)
+	}
+
+	var (
+		result  []Files
+		current Files
+		count   int
+	)
+
+	for _, g := range GroupFiles(all) {
+		if count == perPage {
+			result = append(result, current)
+			current, count = nil, 0
+		}
+
+		current = append(current, g.Files...)
+		count++
+	}
+
+	if len(current) != 0 {
+		result = append(result, current)
+	}
+
+	return result
+}
+
+// Label returns the text of the button that opens the group.
+func (g Group) Label() string {
+	var b strings.Builder
+
+	b.WriteString(g.Title)
+
+	if g.Season != 0 {
+		fmt.Fprintf(&b, " S%02d", g.Season)
+	} else if g.Year != 0 {
+		fmt.Fprintf(&b, " (%d)", g.Year)
+	}
+
+	fmt.Fprintf(&b, " [%d]", len(g.Files))
+
+	return b.String()
+}
+
+// SortedFiles returns the files of the group ordered by episode and then by resolution from highest to lowest.
+func (g Group) SortedFiles() Files {
+	files := append(Files(nil), g.Files...)
+
+	sort.SliceStable(files, func(i, j int) bool {
+		a, b := files[i].Release, files[j].Release
+		if a == nil || b == nil {
+			return a != nil
+		}
+
+		if a.Episode != b.Episode {
+			return a.Episode < b.Episode
+		}
+
+		return resolutionRank(a.Resolution) > resolutionRank(b.Resolution)
+	})
+
+	return files
+}
+
+// resolutionRank orders resolutions from lowest to highest, unknown resolutions are ranked the lowest.
+func resolutionRank(r string) int {
+	switch r {
+	case "2160p":
+		return 4
+	case "1080p":
+		return 3
+	case "720p":
+		return 2
+	case "480p":
+		return 1
+	default:
+		return 0
+	}
+}
+
+// QualityLabel returns a short description of the quality or episode of a file to be used in a group menu.
+// The file name is returned if the file has no release metadata.
+func QualityLabel(f File) string {
+	r := f.Release
+	if r == nil {
+		return f.FileName
+	}
+
+	var parts []string
+
+	if r.Episode != 0 {
+		parts = append(parts, fmt.Sprintf("E%02d", r.Episode))
+	}
+
+	if r.Part != 0 {
+		parts = append(parts, fmt.Sprintf("Part %d", r.Part))
+	}
+
+	for _, s := range []string{r.Resolution, r.Source, r.Codec} {
+		if s != "" {
+			parts = append(parts, s)
+		}
+	}
+
+	if len(r.Languages) != 0 {
+		parts = append(parts, strings.Join(r.Languages, "/"))
+	}
+
+	if len(parts) == 0 {
+		return f.FileName
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// hasTitle reports whether a file has release metadata with a title.
+func hasTitle(f File) bool {
+	return f.Release != nil && f.Release.Title != ""
+}
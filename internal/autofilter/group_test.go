@@ -0,0 +1,74 @@
+package autofilter_test
+
+import (
+	"testing"
+
+	"github.com/Jisin0/autofilterbot/internal/autofilter"
+	"github.com/Jisin0/autofilterbot/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func newFile(uniqueId, name string, r *model.Release) autofilter.File {
+	return autofilter.File{File: model.File{UniqueId: uniqueId, FileName: name, Release: r}}
+}
+
+func TestGroupFiles(t *testing.T) {
+	assert := assert.New(t)
+
+	files := autofilter.Files{
+		newFile("a", "Dark S01E02 720p", &model.Release{Title: "Dark", Season: 1, Episode: 2, Resolution: "720p"}),
+		newFile("b", "Interstellar 2014 1080p", &model.Release{Title: "Interstellar", Year: 2014, Resolution: "1080p"}),
+		newFile("c", "dark S01E01 1080p", &model.Release{Title: "dark", Season: 1, Episode: 1, Resolution: "1080p"}),
+		newFile("d", "Holiday Photos", nil),
+		newFile("e", "Dark S02E01", &model.Release{Title: "Dark", Season: 2, Episode: 1}),
+		newFile("f", "Dark S01E01 2160p", &model.Release{Title: "Dark", Season: 1, Episode: 1, Resolution: "2160p"}),
+		newFile("g", "Holiday Photos", nil),
+	}
+
+	groups := autofilter.GroupFiles(files)
+
+	labels := make([]string, 0, len(groups))
+	for _, g := range groups {
+		labels = append(labels, g.Label())
+	}
+
+	assert.Equal([]string{"Dark S01 [3]", "Interstellar (2014) [1]", "Holiday Photos [1]", "Dark S02 [1]", "Holiday Photos [1]"}, labels)
+
+	var order []string
+	for _, f := range groups[0].SortedFiles() {
+		order = append(order, f.UniqueId)
+	}
+
+	assert.Equal([]string{"f", "c", "a"}, order)
+	assert.Equal("E01 2160p", autofilter.QualityLabel(groups[0].SortedFiles()[0]))
+	assert.Equal("Holiday Photos", autofilter.QualityLabel(files[3]))
+}
+
+func TestGroupPages(t *testing.T) {
+	assert := assert.New(t)
+
+	pages := []autofilter.Files{
+		{
+			newFile("a", "Dark S01E01", &model.Release{Title: "Dark", Season: 1, Episode: 1}),
+			newFile("b", "Lost S01E01", &model.Release{Title: "Lost", Season: 1, Episode: 1}),
+		},
+		{
+			newFile("c", "Dark S01E02", &model.Release{Title: "Dark", Season: 1, Episode: 2}),
+			newFile("d", "Heat 1995", &model.Release{Title: "Heat", Year: 1995}),
+		},
+	}
+
+	result := autofilter.GroupPages(pages, 2)
+
+	var ids [][]string
+	for _, p := range result {
+		var page []string
+		for _, f := range p {
+			page = append(page, f.UniqueId)
+		}
+
+		ids = append(ids, page)
+	}
+
+	assert.Equal([][]string{{"a", "c", "b"}, {"d"}}, ids)
+}
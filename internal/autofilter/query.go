@@ -0,0 +1,210 @@
+package autofilter
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Jisin0/autofilterbot/internal/database"
+	"github.com/Jisin0/autofilterbot/internal/functions"
+	"github.com/Jisin0/autofilterbot/internal/model"
+)
+
+// Query is a search query parsed from the text sent by a user.
+type Query struct {
+	// Sanitized words of the query including words inside phrases, used as the search text.
+	Text string
+	// Additional conditions set using the query syntax.
+	Filter database.FileFilter
+}
+
+// filterToken matches a key:value filter anywhere in the text.
+var filterToken = regexp.MustCompile(`(?i)(^|\s)(type|size|ext|year):\S*`)
+
+// fileTypeAliases maps values accepted by the type filter to a model.FileType.
+var fileTypeAliases = map[string]string{
+	"video":    model.FileTypeVideo,
+	"movie":    model.FileTypeVideo,
+	"document": model.FileTypeDocument,
+	"doc":      model.FileTypeDocument,
+	"file":     model.FileTypeDocument,
+	"audio":    model.FileTypeAudio,
+	"music":    model.FileTypeAudio,
+}
+
+// ParseQuery parses the text of a message using the search syntax:
+//
+//	"exact phrase"  words that should appear next to each other
+//	-word           files containing the word are excluded
+//	type:video      type of the file, one of video, document or audio
+//	size:>1GB       file size, supports >, <, >=, <= or a range like 500MB-2GB
+//	ext:mkv,mp4     any of the comma separated file extensions
+//	year:2023       year that should appear in the file name
+//
+// All other words are sanitized and searched normally. Filters with invalid values are ignored.
+func ParseQuery(text string) Query {
+	var (
+		q     Query
+		words []string
+	)
+
+	for _, token := range tokenize(text) {
+		switch {
+		case strings.HasPrefix(token, `"`):
+			phrase := Sanitize(strings.Trim(token, `"`))
+			if phrase == "" {
+				continue
+			}
+
+			q.Filter.Phrases = append(q.Filter.Phrases, phrase)
+			words = append(words, phrase)
+		case len(token) > 1 && token[0] == '-':
+			if w := Sanitize(token[1:]); w != "" {
+				q.Filter.Exclude = append(q.Filter.Exclude, strings.Fields(w)...)
+			}
+		case filterToken.MatchString(token):
+			key, value, _ := strings.Cut(token, ":")
+			applyFilter(&q.Filter, strings.ToLower(key), strings.ToLower(value))
+		default:
+			if w := Sanitize(token); w != "" {
+				words = append(words, w)
+			}
+		}
+	}
+
+	q.Text = strings.Join(words, " ")
+
+	return q
+}
+
+// String formats the query back into the search syntax so it can be parsed again by ParseQuery.
+func (q Query) String() string {
+	var (
+		f     = q.Filter
+		parts = []string{q.Text}
+	)
+
+	// phrases are already part of the text, only quote them
+	for _, p := range f.Phrases {
+		parts[0] = strings.Replace(parts[0], p, `"`+p+`"`, 1)
+	}
+
+	for _, w := range f.Exclude {
+		parts = append(parts, "-"+w)
+	}
+
+	if f.FileType != "" {
+		parts = append(parts, "type:"+f.FileType)
+	}
+
+	switch {
+	case f.MinSize != 0 && f.MaxSize != 0:
+		parts = append(parts, "size:"+strconv.FormatInt(f.MinSize, 10)+"-"+strconv.FormatInt(f.MaxSize, 10))
+	case f.MinSize != 0:
+		parts = append(parts, "size:>="+strconv.FormatInt(f.MinSize, 10))
+	case f.MaxSize != 0:
+		parts = append(parts, "size:<="+strconv.FormatInt(f.MaxSize, 10))
+	}
+
+	if len(f.Extensions) != 0 {
+		parts = append(parts, "ext:"+strings.Join(f.Extensions, ","))
+	}
+
+	if f.Year != 0 {
+		parts = append(parts, "year:"+strconv.Itoa(f.Year))
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// applyFilter sets the filter named key from it's value, invalid values are ignored.
+func applyFilter(f *database.FileFilter, key, value string) {
+	switch key {
+	case "type":
+		if t, ok := fileTypeAliases[value]; ok {
+			f.FileType = t
+		}
+	case "size":
+		f.MinSize, f.MaxSize = parseSizeRange(value, f.MinSize, f.MaxSize)
+	case "ext":
+		for _, e := range strings.Split(value, ",") {
+			if e = strings.TrimPrefix(strings.TrimSpace(e), "."); e != "" {
+				f.Extensions = append(f.Extensions, e)
+			}
+		}
+	case "year":
+		if y, err := strconv.Atoi(value); err == nil && y >= 1800 && y <= 9999 {
+			f.Year = y
+		}
+	}
+}
+
+// parseSizeRange parses the value of a size filter, min and max are returned unchanged if it is invalid.
+// Sizes are rounded so > and >= as well as < and <= behave the same way.
+func parseSizeRange(value string, minSize, maxSize int64) (int64, int64) {
+	switch {
+	case strings.HasPrefix(value, ">"):
+		if n, err := functions.ParseFileSize(strings.TrimPrefix(value[1:], "=")); err == nil {
+			return n, maxSize
+		}
+	case strings.HasPrefix(value, "<"):
+		if n, err := functions.ParseFileSize(strings.TrimPrefix(value[1:], "=")); err == nil && n > 0 {
+			return minSize, n
+		}
+	case strings.Contains(value, "-"):
+		from, to, _ := strings.Cut(value, "-")
+
+		a, errA := functions.ParseFileSize(from)
+		b, errB := functions.ParseFileSize(to)
+
+		if errA == nil && errB == nil && a <= b {
+			return a, b
+		}
+	default:
+		// a single size matches files of atleast that size
+		if n, err := functions.ParseFileSize(value); err == nil {
+			return n, maxSize
+		}
+	}
+
+	return minSize, maxSize
+}
+
+// tokenize splits text by spaces keeping quoted phrases together, unclosed quotes end at the end of the text.
+func tokenize(text string) []string {
+	var (
+		tokens  []string
+		current strings.Builder
+		quoted  bool
+	)
+
+	flush := func() {
+		if current.Len() != 0 {
+			tokens = append(tokens, current.String())
+			current.Reset()
+		}
+	}
+
+	for _, r := range text {
+		switch {
+		case r == '"':
+			if quoted {
+				current.WriteRune(r)
+				flush()
+			} else {
+				flush()
+				current.WriteRune(r)
+			}
+
+			quoted = !quoted
+		case !quoted && (r == ' ' || r == '\t' || r == '\n'):
+			flush()
+		default:
+			current.WriteRune(r)
+		}
+	}
+
+	flush()
+
+	return tokens
+}
@@ -0,0 +1,82 @@
+package autofilter_test
+
+import (
+	"testing"
+
+	"github.com/Jisin0/autofilterbot/internal/autofilter"
+	"github.com/Jisin0/autofilterbot/internal/database"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseQuery(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected autofilter.Query
+	}{
+		{
+			text:     "Avatar 2009",
+			expected: autofilter.Query{Text: "avatar 2009"},
+		},
+		{
+			text: `"The Dark Knight" -rises`,
+			expected: autofilter.Query{
+				Text:   "the dark knight",
+				Filter: database.FileFilter{Phrases: []string{"the dark knight"}, Exclude: []string{"rises"}},
+			},
+		},
+		{
+			text: "avatar type:video size:>1GB ext:mkv,.MP4 year:2009",
+			expected: autofilter.Query{
+				Text:   "avatar",
+				Filter: database.FileFilter{FileType: "video", MinSize: 1024 * 1024 * 1024, Extensions: []string{"mkv", "mp4"}, Year: 2009},
+			},
+		},
+		{
+			text: "avatar size:500MB-1GB type:doc",
+			expected: autofilter.Query{
+				Text:   "avatar",
+				Filter: database.FileFilter{FileType: "document", MinSize: 500 * 1024 * 1024, MaxSize: 1024 * 1024 * 1024},
+			},
+		},
+		{
+			text: "avatar size:<=700mb",
+			expected: autofilter.Query{
+				Text:   "avatar",
+				Filter: database.FileFilter{MaxSize: 700 * 1024 * 1024},
+			},
+		},
+		{
+			text:     "avatar type:picture size:big year:20 ext:",
+			expected: autofilter.Query{Text: "avatar"},
+		},
+		{
+			text:     `avatar "unclosed phrase`,
+			expected: autofilter.Query{Text: "avatar unclosed phrase", Filter: database.FileFilter{Phrases: []string{"unclosed phrase"}}},
+		},
+		{
+			text:     "type:video",
+			expected: autofilter.Query{Filter: database.FileFilter{FileType: "video"}},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.text, func(t *testing.T) {
+			assert.Equal(t, tc.expected, autofilter.ParseQuery(tc.text))
+		})
+	}
+}
+
+func TestQueryString(t *testing.T) {
+	for _, text := range []string{
+		`"the dark knight" -rises type:video size:>=1073741824 ext:mkv,mp4 year:2008`,
+		"avatar size:524288000-1073741824",
+		"avatar",
+	} {
+		t.Run(text, func(t *testing.T) {
+			q := autofilter.ParseQuery(text)
+
+			assert.Equal(t, text, q.String())
+			assert.Equal(t, q, autofilter.ParseQuery(q.String()))
+		})
+	}
+}
@@ -8,8 +8,9 @@ import (
 )
 
 // IsBadQuery report whether the query is bad based on the message entities and length of the text.
+// Filters in the query syntax are not counted towards the length.
 func IsBadQuery(text string, entities []gotgbot.MessageEntity) bool {
-	if len(text) > 30 || len(text) < 2 { //TODO: might wanna change these hardocded values
+	if l := len(strings.TrimSpace(filterToken.ReplaceAllString(text, ""))); l > 30 || l < 2 { //TODO: might wanna change these hardocded values
 		return true
 	}
 
@@ -32,6 +32,16 @@ func TestIsBadQuery(t *testing.T) {
 			text:   "An Average Sized Query",
 			output: false,
 		},
+		{
+			id:     "long-with-filters",
+			text:   "An Average Sized Query type:video size:>1GB ext:mkv",
+			output: false,
+		},
+		{
+			id:     "only-filters",
+			text:   "type:video ext:mkv",
+			output: true,
+		},
 		{
 			id:       "bad-entity",
 			text:     "Good Text",
@@ -1,6 +1,10 @@
 package autofilter
 
+import "github.com/Jisin0/autofilterbot/internal/database"
+
 // SearchResult holds the result of a search query.
+//
+// Only the first few pages are fetched with the query, later pages are fetched from the database when they're opened.
 type SearchResult struct {
 	// Unique id used to identify the query.
 	UniqueId string
@@ -12,6 +16,52 @@ type SearchResult struct {
 	ChatID int64 `json:"chat_id,omitempty"`
 	// Files are the files fetched from the database.
 	Files []Files `json:"files,omitempty"`
+	// All files fetched from the database in their original order, only set once a view has been applied.
+	All Files `json:"all,omitempty"`
+	// View is the filter and sort order applied to Files.
+	View View `json:"view,omitempty"`
+	// Mode is the search mode used for the query.
+	Mode string `json:"mode,omitempty"`
+	// Filter is the filter parsed from the query.
+	Filter *database.FileFilter `json:"filter,omitempty"`
+	// Channels the files were searched in, all channels if empty.
+	Channels []int64 `json:"channels,omitempty"`
+	// Fetched is the number of files fetched from the database, the next page is fetched after skipping them.
+	Fetched int `json:"fetched,omitempty"`
+	// Complete indicates that all files matching the query have been fetched.
+	Complete bool `json:"complete,omitempty"`
+}
+
+// HasMore reports whether more pages can be fetched from the database.
+// Views only apply to the files already fetched so more pages are not fetched while one is set.
+func (r *SearchResult) HasMore() bool {
+	return !r.Complete && r.View.IsZero()
+}
+
+// SearchOpts returns the options to fetch the next limit files of the result.
+func (r *SearchResult) SearchOpts(limit int) database.SearchFilesOpts {
+	return database.SearchFilesOpts{Mode: r.Mode, Filter: r.Filter, Skip: r.Fetched, Limit: limit, Channels: r.Channels}
+}
+
+// AddPage adds files fetched from the database as a new page, they're expected to be upto limit files fetched using SearchOpts.
+// The result is marked complete if fewer than limit files were fetched.
+func (r *SearchResult) AddPage(files Files, limit int, grouped bool) {
+	r.Fetched += len(files)
+	r.Complete = len(files) < limit
+
+	if len(files) == 0 {
+		return
+	}
+
+	if r.All != nil {
+		r.All = append(r.All, files...)
+	}
+
+	if grouped {
+		r.Files = append(r.Files, GroupPages([]Files{files}, limit)...)
+	} else {
+		r.Files = append(r.Files, files)
+	}
 }
 
 // SelectFile sets the IsSelected field of the file on given page.
@@ -30,3 +80,29 @@ func (r *SearchResult) SelectFile(pageIndex int, fileUniqueId string) bool {
 
 	return false
 }
+
+// Selected returns the selected files from all pages of the result in the order they were found.
+func (r *SearchResult) Selected() Files {
+	var selected Files
+
+	for _, f := range r.allFiles() {
+		if f.IsSelected {
+			selected = append(selected, f)
+		}
+	}
+
+	return selected
+}
+
+// ClearSelection unselects the files on all pages of the result.
+func (r *SearchResult) ClearSelection() {
+	for _, p := range r.Files {
+		for i := range p {
+			p[i].IsSelected = false
+		}
+	}
+
+	for i := range r.All {
+		r.All[i].IsSelected = false
+	}
+}
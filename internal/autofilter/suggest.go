@@ -0,0 +1,89 @@
+package autofilter
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/Jisin0/autofilterbot/pkg/fuzzy"
+)
+
+// Suggestions returns upto n corrected versions of a sanitized query using words from the vocabulary, best suggestion first.
+// Words that are already known, too short or only numbers are never changed. Returns nil if nothing could be corrected.
+func Suggestions(v *fuzzy.Vocabulary, query string, n int) []string {
+	words := strings.Fields(query)
+	if len(words) == 0 || n <= 0 {
+		return nil
+	}
+
+	var (
+		alternatives = make([][]string, len(words))
+		corrected    bool
+	)
+
+	for i, w := range words {
+		alternatives[i] = []string{w}
+
+		if v.Contains(w) || isNumber(w) {
+			continue
+		}
+
+		if c := v.Closest(w, n); len(c) != 0 {
+			alternatives[i] = c
+			corrected = true
+		}
+	}
+
+	if !corrected {
+		return nil
+	}
+
+	var (
+		result = make([]string, 0, n)
+		seen   = map[string]bool{query: true}
+	)
+
+	add := func(s string) {
+		if len(result) < n && !seen[s] {
+			seen[s] = true
+
+			result = append(result, s)
+		}
+	}
+
+	best := make([]string, len(words))
+	for i, a := range alternatives {
+		best[i] = a[0]
+	}
+
+	add(strings.Join(best, " "))
+
+	// vary one word at a time from the best suggestion
+	for i, a := range alternatives {
+		for _, alt := range a[1:] {
+			s := make([]string, len(best))
+			copy(s, best)
+			s[i] = alt
+
+			add(strings.Join(s, " "))
+		}
+	}
+
+	return result
+}
+
+// WordsFromFileName splits a file name into lower case words that can be added to the vocabulary.
+func WordsFromFileName(name string) []string {
+	return strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+func isNumber(s string) bool {
+	for _, r := range s {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,38 @@
+package autofilter_test
+
+import (
+	"testing"
+
+	"github.com/Jisin0/autofilterbot/internal/autofilter"
+	"github.com/Jisin0/autofilterbot/pkg/fuzzy"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSuggestions(t *testing.T) {
+	v := fuzzy.NewVocabulary()
+
+	for _, name := range []string{"Avatar.2009.720p.mkv", "Avatars [2020].mkv", "Interstellar 2014.mkv", "Inception_2010_HDRip.mp4"} {
+		v.Add(autofilter.WordsFromFileName(name)...)
+	}
+
+	tests := []struct {
+		query    string
+		expected []string
+	}{
+		{query: "avatr 2009", expected: []string{"avatar 2009"}},
+		{query: "avtaar 2008", expected: []string{"avatar 2008", "avatars 2008"}},
+		{query: "intersteller incepton", expected: []string{"interstellar inception"}},
+		{query: "avatar 2009", expected: nil},
+		{query: "xyzzy", expected: nil},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.query, func(t *testing.T) {
+			assert.Equal(t, tc.expected, autofilter.Suggestions(v, tc.query, 3))
+		})
+	}
+}
+
+func TestWordsFromFileName(t *testing.T) {
+	assert.Equal(t, []string{"inception", "2010", "hdrip", "mp4"}, autofilter.WordsFromFileName("Inception_2010_HDRip.mp4"))
+}
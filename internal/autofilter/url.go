@@ -2,6 +2,8 @@ package autofilter
 
 import (
 	"encoding/base64"
+	"encoding/binary"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
@@ -17,7 +19,42 @@ type URLData struct {
 	HasShortener bool
 }
 
-// Encode converts data to a base64 string to be used in a start url.
+// DataPrefixFile is the first byte of start data that sends a file.
+const DataPrefixFile = 'f'
+
+// Bytes encodes data compactly to be signed as start data, it is decoded using URLDataFromBytes.
+func (d URLData) Bytes() []byte {
+	var hasShortener byte
+	if d.HasShortener {
+		hasShortener = 1
+	}
+
+	b := []byte{DataPrefixFile, hasShortener}
+	b = binary.AppendVarint(b, d.ChatId)
+
+	return append(b, d.FileUniqueId...)
+}
+
+// URLDataFromBytes decodes data encoded using URLData.Bytes.
+func URLDataFromBytes(b []byte) (*URLData, error) {
+	if len(b) < 2 || b[0] != DataPrefixFile {
+		return nil, errors.New("not file data")
+	}
+
+	chatId, n := binary.Varint(b[2:])
+	if n <= 0 {
+		return nil, errors.New("invalid chat id")
+	}
+
+	return &URLData{
+		FileUniqueId: string(b[2+n:]),
+		ChatId:       chatId,
+		HasShortener: b[1] == 1,
+	}, nil
+}
+
+// Encode converts data to a base64 string used in old unsigned start urls.
+// New links are signed using Bytes, old links are still decoded using URLDataFromString.
 func (d URLData) Encode() string {
 	hasShortener := "0"
 	if d.HasShortener {
@@ -57,3 +94,45 @@ func URLDataFromString(input string) (*URLData, error) {
 		HasShortener: split[3] == "1",
 	}, nil
 }
+
+// SearchDataPrefix is the prefix of start data that opens the results of a query in the bot's private chat.
+const SearchDataPrefix = "s_"
+
+// maxStartDataLength is the maximum length of start data allowed by telegram.
+const maxStartDataLength = 64
+
+// SearchStartData returns start data to search for query in the bot's private chat like s_avatar_2009.
+// Words are joined with underscores and characters not allowed in start data are dropped.
+func SearchStartData(query string) string {
+	words := strings.FieldsFunc(query, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-')
+	})
+
+	s := SearchDataPrefix
+
+	for i, w := range words {
+		if i != 0 {
+			w = "_" + w
+		}
+
+		if len(s)+len(w) > maxStartDataLength {
+			break
+		}
+
+		s += w
+	}
+
+	return s
+}
+
+// QueryFromStartData returns the query from search start data and false if the data is not search data.
+func QueryFromStartData(data string) (string, bool) {
+	query, ok := strings.CutPrefix(data, SearchDataPrefix)
+	if !ok {
+		return "", false
+	}
+
+	query = strings.TrimSpace(strings.ReplaceAll(query, "_", " "))
+
+	return query, query != ""
+}
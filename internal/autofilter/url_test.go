@@ -34,6 +34,40 @@ func TestURLData(t *testing.T) {
 			out, err := autofilter.URLDataFromBase64String(s)
 			assert.NoError(err)
 			assert.Equal(item, out)
+
+			out, err = autofilter.URLDataFromBytes(item.Bytes())
+			assert.NoError(err)
+			assert.Equal(item, out)
+		})
+	}
+}
+
+func TestSearchStartData(t *testing.T) {
+	tests := []struct {
+		query    string
+		data     string
+		expected string
+	}{
+		{query: "avatar 2009", data: "s_avatar_2009", expected: "avatar 2009"},
+		{query: "spider-man: no way home", data: "s_spider-man_no_way_home", expected: "spider-man no way home"},
+		{query: "dune (2021) type:video", data: "s_dune_2021_type_video", expected: "dune 2021 type video"},
+		{query: "the lord of the rings the fellowship of the ring extended edition", data: "s_the_lord_of_the_rings_the_fellowship_of_the_ring_extended", expected: "the lord of the rings the fellowship of the ring extended"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.query, func(t *testing.T) {
+			data := autofilter.SearchStartData(tc.query)
+			assert.Equal(t, tc.data, data)
+
+			query, ok := autofilter.QueryFromStartData(data)
+			assert.True(t, ok)
+			assert.Equal(t, tc.expected, query)
 		})
 	}
+
+	_, ok := autofilter.QueryFromStartData("s_")
+	assert.False(t, ok, "empty query")
+
+	_, ok = autofilter.QueryFromStartData("ZnxhYmN8MTIzfDA=")
+	assert.False(t, ok, "base64 data")
 }
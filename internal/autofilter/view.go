@@ -0,0 +1,167 @@
+package autofilter
+
+import (
+	"sort"
+	"strings"
+)
+
+const (
+	SortDefault = ""
+	SortSize    = "size"
+	SortDate    = "date"
+	SortName    = "name"
+)
+
+// View is the filter and sort order chosen for a search result from the result message.
+type View struct {
+	// Type of file to show, all types are shown if empty.
+	FileType string `json:"type,omitempty"`
+	// Audio language that files must have.
+	Language string `json:"lang,omitempty"`
+	// Resolution of files to show like 720p.
+	Resolution string `json:"res,omitempty"`
+	// Sort order one of the Sort values, files are shown in the order they were found if empty.
+	Sort string `json:"sort,omitempty"`
+}
+
+// IsZero reports whether no filters or sort order are set.
+func (v View) IsZero() bool {
+	return v == View{}
+}
+
+// Match reports whether a file passes the filters of the view.
+func (v View) Match(f File) bool {
+	if v.FileType != "" && f.FileType != v.FileType {
+		return false
+	}
+
+	if v.Resolution != "" && (f.Release == nil || f.Release.Resolution != v.Resolution) {
+		return false
+	}
+
+	if v.Language != "" && (f.Release == nil || !contains(f.Release.Languages, v.Language)) {
+		return false
+	}
+
+	return true
+}
+
+// sortFiles sorts files in place by the sort order of the view, biggest and newest files are shown first.
+func (v View) sortFiles(files Files) {
+	var less func(a, b File) bool
+
+	switch v.Sort {
+	case SortSize:
+		less = func(a, b File) bool { return a.FileSize > b.FileSize }
+	case SortDate:
+		less = func(a, b File) bool { return a.Time > b.Time }
+	case SortName:
+		less = func(a, b File) bool { return strings.ToLower(a.FileName) < strings.ToLower(b.FileName) }
+	default:
+		return
+	}
+
+	sort.SliceStable(files, func(i, j int) bool { return less(files[i], files[j]) })
+}
+
+// Options lists the file types, languages and resolutions found in the files of the result that can be used to filter it.
+func (r *SearchResult) Options() (fileTypes, languages, resolutions []string) {
+	for _, f := range r.allFiles() {
+		if f.FileType != "" && !contains(fileTypes, f.FileType) {
+			fileTypes = append(fileTypes, f.FileType)
+		}
+
+		if f.Release == nil {
+			continue
+		}
+
+		if f.Release.Resolution != "" && !contains(resolutions, f.Release.Resolution) {
+			resolutions = append(resolutions, f.Release.Resolution)
+		}
+
+		for _, l := range f.Release.Languages {
+			if !contains(languages, l) {
+				languages = append(languages, l)
+			}
+		}
+	}
+
+	sort.Strings(fileTypes)
+	sort.Strings(languages)
+	sort.Slice(resolutions, func(i, j int) bool { return resolutionRank(resolutions[i]) > resolutionRank(resolutions[j]) })
+
+	return fileTypes, languages, resolutions
+}
+
+// SetView filters and sorts the files of the result and splits them into pages of perPage files or groups.
+// The result is left unchanged and false is returned if no files match the view.
+func (r *SearchResult) SetView(v View, perPage int, grouped bool) bool {
+	all := r.allFiles()
+
+	files := make(Files, 0, len(all))
+	for _, f := range all {
+		if v.Match(f) {
+			files = append(files, f)
+		}
+	}
+
+	if len(files) == 0 {
+		return false
+	}
+
+	v.sortFiles(files)
+
+	var pages []Files
+
+	if grouped {
+		pages = GroupPages([]Files{files}, perPage)
+	} else {
+		for i := 0; i < len(files); i += perPage {
+			pages = append(pages, files[i:min(i+perPage, len(files))])
+		}
+	}
+
+	r.All, r.Files, r.View = all, pages, v
+
+	return true
+}
+
+// allFiles returns all files of the result in the order they were found, with the selection of files in the current pages.
+func (r *SearchResult) allFiles() Files {
+	if r.All == nil {
+		var all Files
+		for _, p := range r.Files {
+			all = append(all, p...)
+		}
+
+		return all
+	}
+
+	selected := make(map[string]bool)
+	for _, p := range r.Files {
+		for _, f := range p {
+			selected[f.UniqueId] = f.IsSelected
+		}
+	}
+
+	all := make(Files, len(r.All))
+	for i, f := range r.All {
+		if s, ok := selected[f.UniqueId]; ok {
+			f.IsSelected = s
+		}
+
+		all[i] = f
+	}
+
+	return all
+}
+
+func contains(l []string, s string) bool {
+	for _, v := range l {
+		if v == s {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,95 @@
+package autofilter_test
+
+import (
+	"testing"
+
+	"github.com/Jisin0/autofilterbot/internal/autofilter"
+	"github.com/Jisin0/autofilterbot/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func pageIds(pages []autofilter.Files) [][]string {
+	var ids [][]string
+	for _, p := range pages {
+		var page []string
+		for _, f := range p {
+			page = append(page, f.UniqueId)
+		}
+
+		ids = append(ids, page)
+	}
+
+	return ids
+}
+
+func fileIds(files autofilter.Files) []string {
+	var ids []string
+	for _, f := range files {
+		ids = append(ids, f.UniqueId)
+	}
+
+	return ids
+}
+
+func TestSearchResultSetView(t *testing.T) {
+	assert := assert.New(t)
+
+	file := func(uniqueId, fileType string, size, time int64, r *model.Release) autofilter.File {
+		return autofilter.File{File: model.File{UniqueId: uniqueId, FileName: uniqueId, FileType: fileType, FileSize: size, Time: time, Release: r}}
+	}
+
+	r := &autofilter.SearchResult{
+		UniqueId: "abc",
+		Files: []autofilter.Files{
+			{
+				file("d", model.FileTypeVideo, 300, 1, &model.Release{Resolution: "720p", Languages: []string{"Hindi"}}),
+				file("b", model.FileTypeDocument, 100, 3, &model.Release{Resolution: "1080p", Languages: []string{"English", "Hindi"}}),
+			},
+			{
+				file("a", model.FileTypeVideo, 200, 2, &model.Release{Resolution: "1080p"}),
+				file("c", model.FileTypeAudio, 50, 4, nil),
+			},
+		},
+	}
+
+	fileTypes, languages, resolutions := r.Options()
+	assert.Equal([]string{"audio", "document", "video"}, fileTypes)
+	assert.Equal([]string{"English", "Hindi"}, languages)
+	assert.Equal([]string{"1080p", "720p"}, resolutions)
+
+	// selection should be kept across views
+	r.SelectFile(1, "a")
+	r.SelectFile(0, "b")
+	r.SelectFile(0, "b")
+	assert.Equal([]string{"a"}, fileIds(r.Selected()))
+
+	assert.True(r.SetView(autofilter.View{FileType: model.FileTypeVideo}, 2, false))
+	assert.Equal([][]string{{"d", "a"}}, pageIds(r.Files))
+	assert.True(r.Files[0][1].IsSelected)
+
+	assert.True(r.SetView(autofilter.View{Sort: autofilter.SortSize}, 3, false))
+	assert.Equal([][]string{{"d", "a", "b"}, {"c"}}, pageIds(r.Files))
+	assert.True(r.Files[0][1].IsSelected)
+
+	assert.True(r.SetView(autofilter.View{Resolution: "1080p", Sort: autofilter.SortDate}, 2, false))
+	assert.Equal([][]string{{"b", "a"}}, pageIds(r.Files))
+
+	assert.True(r.SetView(autofilter.View{Language: "Hindi", Sort: autofilter.SortName}, 2, false))
+	assert.Equal([][]string{{"b", "d"}}, pageIds(r.Files))
+
+	// an empty view is left unchanged
+	assert.False(r.SetView(autofilter.View{FileType: model.FileTypeAudio, Language: "Hindi"}, 2, false))
+	assert.Equal(autofilter.View{Language: "Hindi", Sort: autofilter.SortName}, r.View)
+
+	assert.True(r.SetView(autofilter.View{}, 2, false))
+	assert.Equal([][]string{{"d", "b"}, {"a", "c"}}, pageIds(r.Files))
+	assert.True(r.Files[1][0].IsSelected)
+
+	r.SelectFile(1, "c")
+	assert.Equal([]string{"a", "c"}, fileIds(r.Selected()), "selection should span all pages")
+
+	assert.True(r.SetView(autofilter.View{FileType: model.FileTypeVideo}, 2, false))
+	r.ClearSelection()
+	assert.True(r.SetView(autofilter.View{}, 2, false))
+	assert.Empty(r.Selected(), "hidden files should be unselected")
+}
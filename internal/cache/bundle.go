@@ -0,0 +1,51 @@
+package cache
+
+import (
+	"time"
+
+	"github.com/Jisin0/autofilterbot/pkg/jsoncache"
+)
+
+// Bundle facilitates caching files selected from a result to share them using a link.
+type Bundle struct {
+	cache *jsoncache.Cache
+}
+
+// BundleData is the files of a bundle and the chat they were selected in.
+type BundleData struct {
+	// Id of the chat of the result the files were selected from, its fsub channels are checked before sending the files.
+	ChatId int64 `json:"chat_id"`
+	// Unique ids of the files in the order they're sent.
+	UniqueIds []string `json:"files"`
+}
+
+// NewBundle creates a new bundle storage cache.
+func NewBundle(timeout time.Duration) *Bundle {
+	return &Bundle{
+		cache: jsoncache.NewCache(".bundle", timeout),
+	}
+}
+
+// Save stores the files of a bundle.
+func (c *Bundle) Save(id string, data *BundleData) error {
+	return c.cache.Save(id, data)
+}
+
+// Get fetches the files of a bundle if available.
+//
+// If the cache file doesnt exist or was expired a nil error with ok set to false will be returned.
+// In case of any other error ok will be true and error will be set.
+func (c *Bundle) Get(id string) (*BundleData, bool, error) {
+	var res *BundleData
+
+	err := c.cache.Load(id, &res)
+	if err != nil {
+		if err == jsoncache.ErrFileNotFound || err == jsoncache.ErrCacheDataExpired {
+			return nil, false, nil
+		}
+
+		return nil, true, err
+	}
+
+	return res, true, nil
+}
@@ -0,0 +1,31 @@
+package cache_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Jisin0/autofilterbot/internal/cache"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBundle(t *testing.T) {
+	assert := assert.New(t)
+
+	c := cache.NewBundle(time.Minute * 1)
+
+	data := &cache.BundleData{
+		ChatId:    -1001234567890,
+		UniqueIds: []string{"AgADxQ4AAm5vMVY", "AgADBQUAAqL0gVQ", "AgADtw0AAhRwUFU"},
+	}
+
+	assert.NoError(c.Save("x7Kp2QaZ", data))
+
+	res, ok, err := c.Get("x7Kp2QaZ")
+	assert.NoError(err)
+	assert.True(ok)
+	assert.Equal(data, res)
+
+	_, ok, err = c.Get("notfound")
+	assert.NoError(err)
+	assert.False(ok)
+}
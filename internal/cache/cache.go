@@ -8,12 +8,14 @@ import "time"
 const (
 	defualtAutofilterTimeout = time.Minute * 15
 	defaultBatchTimeout      = time.Hour * 2
+	defaultBundleTimeout     = time.Hour * 24
 )
 
 // Cache wraps all json cache helper types into a struct.
 type Cache struct {
 	Autofilter *Autofilter
 	Batch      *Batch
+	Bundle     *Bundle
 }
 
 // NewCache initializes and creates a new cache structure.
@@ -21,5 +23,6 @@ func NewCache() *Cache {
 	return &Cache{
 		Autofilter: NewAutofilter(defualtAutofilterTimeout),
 		Batch:      NewBatch(defaultBatchTimeout),
+		Bundle:     NewBundle(defaultBundleTimeout),
 	}
 }
@@ -0,0 +1,285 @@
+/*
+Package catalog exports and imports the files saved in a database as JSONL or CSV so they can be backed up or moved to another bot.
+*/
+package catalog
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/Jisin0/autofilterbot/internal/database"
+	"github.com/Jisin0/autofilterbot/internal/model"
+)
+
+// Format is a file format of an exported catalog.
+type Format string
+
+const (
+	// FormatJSONL writes one json encoded file per line.
+	FormatJSONL Format = "jsonl"
+	// FormatCSV writes a header row followed by one row per file.
+	FormatCSV Format = "csv"
+)
+
+// csvHeader are the columns of a csv catalog, they match the json keys of model.File.
+var csvHeader = []string{"_id", "file_id", "file_name", "file_type", "file_size", "ext", "time", "chat_id", "file_link"}
+
+// ParseFormat parses the name of a format, an error is returned if it is unknown.
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(strings.TrimPrefix(s, "."))); f {
+	case FormatJSONL, FormatCSV:
+		return f, nil
+	case "json":
+		return FormatJSONL, nil
+	default:
+		return "", fmt.Errorf("catalog: unknown format %q", s)
+	}
+}
+
+// FormatFromFileName returns the format of a file from it's extension, defaults to FormatJSONL.
+func FormatFromFileName(name string) Format {
+	if strings.HasSuffix(strings.ToLower(name), ".csv") {
+		return FormatCSV
+	}
+
+	return FormatJSONL
+}
+
+// Export writes every file from the cursor to w in the format, files are written as they are read.
+// Returns the number of files written.
+func Export(ctx context.Context, cursor database.Cursor, w io.Writer, format Format) (int, error) {
+	var (
+		count int
+		write func(f *model.File) error
+		flush func() error
+	)
+
+	switch format {
+	case FormatCSV:
+		cw := csv.NewWriter(w)
+
+		if err := cw.Write(csvHeader); err != nil {
+			return 0, err
+		}
+
+		write = func(f *model.File) error { return cw.Write(toRecord(f)) }
+		flush = func() error {
+			cw.Flush()
+			return cw.Error()
+		}
+	case FormatJSONL:
+		bw := bufio.NewWriter(w)
+		enc := json.NewEncoder(bw)
+
+		write = func(f *model.File) error { return enc.Encode(f) }
+		flush = bw.Flush
+	default:
+		return 0, fmt.Errorf("catalog: unknown format %q", format)
+	}
+
+	for cursor.Next(ctx) {
+		var f model.File
+
+		if err := cursor.Decode(&f); err != nil {
+			return count, err
+		}
+
+		if err := write(&f); err != nil {
+			return count, err
+		}
+
+		count++
+	}
+
+	// a cursor that failed part way would otherwise look like a complete export
+	if err := cursor.Err(); err != nil {
+		return count, err
+	}
+
+	return count, flush()
+}
+
+// ImportResult reports the outcome of an import.
+type ImportResult struct {
+	// Number of files saved.
+	Inserted int
+	// Number of files that already existed.
+	Duplicate int
+	// Number of rows that could not be parsed or saved.
+	Invalid int
+}
+
+// Total returns the number of rows read.
+func (r ImportResult) Total() int {
+	return r.Inserted + r.Duplicate + r.Invalid
+}
+
+// Import reads files in the format from r and saves each of them using save, which is usually database.Database.SaveFile.
+// A database.FileAlreadyExistsError returned by save is counted as a duplicate and any other error as invalid.
+// An error is returned only if reading r fails.
+func Import(ctx context.Context, r io.Reader, format Format, save func(f *model.File) error) (ImportResult, error) {
+	var result ImportResult
+
+	handle := func(f *model.File, err error) {
+		if err == nil {
+			err = validate(f)
+		}
+
+		if err == nil {
+			err = save(f)
+		}
+
+		var existsErr database.FileAlreadyExistsError
+
+		switch {
+		case err == nil:
+			result.Inserted++
+		case errors.As(err, &existsErr):
+			result.Duplicate++
+		default:
+			result.Invalid++
+		}
+	}
+
+	switch format {
+	case FormatCSV:
+		cr := csv.NewReader(r)
+		cr.FieldsPerRecord = -1
+
+		header, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return result, nil
+		} else if err != nil {
+			return result, err
+		}
+
+		columns := make(map[string]int, len(header))
+		for i, h := range header {
+			columns[strings.TrimSpace(h)] = i
+		}
+
+		for ctx.Err() == nil {
+			record, err := cr.Read()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				result.Invalid++
+				continue
+			} else if err != nil {
+				return result, err
+			}
+
+			handle(fromRecord(columns, record))
+		}
+	case FormatJSONL:
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+		for ctx.Err() == nil && scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" {
+				continue
+			}
+
+			var f model.File
+
+			handle(&f, json.Unmarshal([]byte(line), &f))
+		}
+
+		if err := scanner.Err(); err != nil {
+			return result, err
+		}
+	default:
+		return result, fmt.Errorf("catalog: unknown format %q", format)
+	}
+
+	return result, ctx.Err()
+}
+
+// validate checks that the fields needed to send a file are set.
+func validate(f *model.File) error {
+	switch {
+	case f.UniqueId == "":
+		return errors.New("missing _id")
+	case f.FileId == "":
+		return errors.New("missing file_id")
+	case f.FileName == "":
+		return errors.New("missing file_name")
+	}
+
+	switch f.FileType {
+	case model.FileTypeDocument, model.FileTypeVideo, model.FileTypeAudio, model.FileTypeVoice:
+		return nil
+	default:
+		return fmt.Errorf("invalid file_type %q", f.FileType)
+	}
+}
+
+func toRecord(f *model.File) []string {
+	return []string{
+		f.UniqueId,
+		f.FileId,
+		f.FileName,
+		f.FileType,
+		strconv.FormatInt(f.FileSize, 10),
+		f.Extension,
+		strconv.FormatInt(f.Time, 10),
+		strconv.FormatInt(f.ChatId, 10),
+		f.MessageLink,
+	}
+}
+
+// fromRecord parses a csv row using the column indexes from the header, missing columns are left empty.
+func fromRecord(columns map[string]int, record []string) (*model.File, error) {
+	get := func(name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+
+		return ""
+	}
+
+	getInt := func(name string) (int64, error) {
+		s := get(name)
+		if s == "" {
+			return 0, nil
+		}
+
+		return strconv.ParseInt(s, 10, 64)
+	}
+
+	f := model.File{
+		UniqueId:    get("_id"),
+		FileId:      get("file_id"),
+		FileName:    get("file_name"),
+		FileType:    get("file_type"),
+		Extension:   get("ext"),
+		MessageLink: get("file_link"),
+	}
+
+	var err error
+
+	if f.FileSize, err = getInt("file_size"); err != nil {
+		return nil, err
+	}
+
+	if f.Time, err = getInt("time"); err != nil {
+		return nil, err
+	}
+
+	if f.ChatId, err = getInt("chat_id"); err != nil {
+		return nil, err
+	}
+
+	return &f, nil
+}
@@ -0,0 +1,143 @@
+package catalog_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Jisin0/autofilterbot/internal/catalog"
+	"github.com/Jisin0/autofilterbot/internal/database"
+	"github.com/Jisin0/autofilterbot/internal/database/memory"
+	"github.com/Jisin0/autofilterbot/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+var testFiles = []*model.File{
+	{UniqueId: "AgADa", FileId: "BQACAgUAAa", FileName: "Avatar 2009 720p", FileType: model.FileTypeVideo, FileSize: 1000, Extension: "mkv", Time: 1, ChatId: -1001, MessageLink: "https://t.me/c/1/1"},
+	{UniqueId: "AgADb", FileId: "BQACAgUAAb", FileName: "Interstellar, \"2014\"", FileType: model.FileTypeDocument, FileSize: 3000, Time: 2},
+}
+
+func TestExportImport(t *testing.T) {
+	for _, format := range []catalog.Format{catalog.FormatJSONL, catalog.FormatCSV} {
+		t.Run(string(format), func(t *testing.T) {
+			assert := assert.New(t)
+			ctx := context.Background()
+
+			src := memory.NewClient()
+			assert.Empty(src.SaveFiles(testFiles...))
+
+			cursor, err := src.GetAllFiles()
+			if !assert.NoError(err) {
+				return
+			}
+
+			var buf bytes.Buffer
+
+			n, err := catalog.Export(ctx, cursor, &buf, format)
+			assert.NoError(err)
+			assert.Equal(2, n)
+
+			// importing twice should only save the files once
+			dst := memory.NewClient()
+			assert.NoError(dst.SaveFile(testFiles[0]))
+
+			res, err := catalog.Import(ctx, bytes.NewReader(buf.Bytes()), format, dst.SaveFile)
+			assert.NoError(err)
+			assert.Equal(catalog.ImportResult{Inserted: 1, Duplicate: 1}, res)
+
+			for _, want := range testFiles {
+				f, err := dst.GetFile(want.UniqueId)
+				if assert.NoError(err) {
+					assert.Equal(want, f)
+				}
+			}
+		})
+	}
+}
+
+// failingCursor fails after returning the files of a cursor, like a collection that timed out part way.
+type failingCursor struct {
+	database.Cursor
+}
+
+var errCursorFailed = errors.New("cursor failed")
+
+func (c failingCursor) Err() error {
+	return errCursorFailed
+}
+
+func TestExportCursorFails(t *testing.T) {
+	assert := assert.New(t)
+
+	src := memory.NewClient()
+	assert.Empty(src.SaveFiles(testFiles...))
+
+	cursor, err := src.GetAllFiles()
+	if !assert.NoError(err) {
+		return
+	}
+
+	n, err := catalog.Export(context.Background(), failingCursor{cursor}, &bytes.Buffer{}, catalog.FormatJSONL)
+	assert.ErrorIs(err, errCursorFailed, "partial exports should fail")
+	assert.Equal(2, n)
+}
+
+func TestImportInvalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   catalog.Format
+		input    string
+		expected catalog.ImportResult
+	}{
+		{
+			name:   "jsonl",
+			format: catalog.FormatJSONL,
+			input: `{"_id":"AgADa","file_id":"BQACAgUAAa","file_name":"Avatar","file_type":"video","file_size":10}
+not json
+
+{"_id":"AgADb","file_name":"No File Id","file_type":"video"}
+{"_id":"AgADc","file_id":"BQACAgUAAc","file_name":"Bad Type","file_type":"sticker"}
+`,
+			expected: catalog.ImportResult{Inserted: 1, Invalid: 3},
+		},
+		{
+			name:   "csv",
+			format: catalog.FormatCSV,
+			input: `file_name,_id,file_id,file_type,file_size
+Avatar,AgADa,BQACAgUAAa,video,10
+Big,AgADb,BQACAgUAAb,video,ten
+"Broken,AgADc
+`,
+			expected: catalog.ImportResult{Inserted: 1, Invalid: 2},
+		},
+		{
+			name:     "empty csv",
+			format:   catalog.FormatCSV,
+			input:    "",
+			expected: catalog.ImportResult{},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := catalog.Import(context.Background(), strings.NewReader(tc.input), tc.format, memory.NewClient().SaveFile)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, res)
+			assert.Equal(t, tc.expected.Inserted+tc.expected.Invalid, res.Total())
+		})
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	f, err := catalog.ParseFormat("CSV")
+	assert.NoError(t, err)
+	assert.Equal(t, catalog.FormatCSV, f)
+
+	_, err = catalog.ParseFormat("xml")
+	assert.Error(t, err)
+
+	assert.Equal(t, catalog.FormatCSV, catalog.FormatFromFileName("catalog.CSV"))
+	assert.Equal(t, catalog.FormatJSONL, catalog.FormatFromFileName("catalog.jsonl"))
+}
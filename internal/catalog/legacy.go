@@ -0,0 +1,196 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/Jisin0/autofilterbot/internal/database"
+	"github.com/Jisin0/autofilterbot/internal/functions"
+	"github.com/Jisin0/autofilterbot/internal/model"
+	"github.com/Jisin0/autofilterbot/internal/release"
+	"github.com/Jisin0/autofilterbot/pkg/fileid"
+)
+
+// DefaultLegacyCollection is the default name of the files collection used by python autofilter bots.
+const DefaultLegacyCollection = "Telegram_files"
+
+// LegacyFile is a file saved by one of the python autofilter bots.
+type LegacyFile struct {
+	// File id of the file, it is also used as the primary key.
+	ID string `bson:"_id"`
+	// File reference of the file, it expires and is not used by this bot.
+	FileRef string `bson:"file_ref"`
+	// Name of the file with symbols replaced by whitespaces.
+	FileName string `bson:"file_name"`
+	// Size of the file in bytes.
+	FileSize int64 `bson:"file_size"`
+	// Type of the file, usually document, video or audio.
+	FileType string `bson:"file_type"`
+	// Mime type of the file.
+	MimeType string `bson:"mime_type"`
+	// Caption of the message that contained the file.
+	Caption string `bson:"caption"`
+}
+
+// mimeExtensions maps common mime types to extensions for legacy files whose names have been stripped of dots.
+var mimeExtensions = map[string]string{
+	"video/x-matroska":             "mkv",
+	"video/mp4":                    "mp4",
+	"video/x-msvideo":              "avi",
+	"video/webm":                   "webm",
+	"video/quicktime":              "mov",
+	"audio/mpeg":                   "mp3",
+	"audio/mp4":                    "m4a",
+	"audio/flac":                   "flac",
+	"application/zip":              "zip",
+	"application/pdf":              "pdf",
+	"application/x-rar":            "rar",
+	"application/vnd.rar":          "rar",
+	"application/x-rar-compressed": "rar",
+	"application/x-subrip":         "srt",
+}
+
+// ToFile maps a legacy file to a model.File, an error is returned if the file can't be sent by the bot.
+// Python bots don't store the file_unique_id so it is derived from the file id, the legacy id is used if it can't be decoded.
+func (l *LegacyFile) ToFile() (*model.File, error) {
+	if l.ID == "" {
+		return nil, errors.New("missing _id")
+	}
+
+	uniqueID := l.ID
+
+	if id, err := fileid.DecodeFileID(l.ID); err == nil {
+		if s, err := fileid.EncodeUniqueID(id); err == nil {
+			uniqueID = s
+		}
+	}
+
+	name := l.FileName
+	if strings.TrimSpace(name) == "" || name == "None" { // python saves str(None) for files without a name
+		name = l.Caption
+	}
+
+	ext := functions.FileExtension(name)
+	if ext == "" {
+		ext = mimeExtensions[strings.ToLower(l.MimeType)]
+	}
+
+	f := &model.File{
+		UniqueId:  uniqueID,
+		FileId:    l.ID,
+		FileName:  functions.RemoveSymbols(functions.RemoveExtension(name)),
+		FileType:  legacyFileType(l.FileType, l.MimeType),
+		FileSize:  l.FileSize,
+		Extension: ext,
+		Release:   release.Parse(name),
+	}
+
+	return f, validate(f)
+}
+
+// legacyFileType returns the file type using the mime type if the saved type is not supported.
+func legacyFileType(fileType, mimeType string) string {
+	switch fileType = strings.ToLower(fileType); fileType {
+	case model.FileTypeDocument, model.FileTypeVideo, model.FileTypeAudio, model.FileTypeVoice:
+		return fileType
+	}
+
+	switch {
+	case strings.HasPrefix(mimeType, "video/"):
+		return model.FileTypeVideo
+	case strings.HasPrefix(mimeType, "audio/"):
+		return model.FileTypeAudio
+	default:
+		return model.FileTypeDocument
+	}
+}
+
+// LegacyImportOptions configures an import of legacy files.
+type LegacyImportOptions struct {
+	// Number of files saved at once, defaults to 100.
+	BatchSize int
+	// Don't save any files, only report what would be saved.
+	DryRun bool
+	// Called after each batch with the result so far.
+	OnProgress func(ImportResult)
+}
+
+// ImportLegacy reads LegacyFile documents from the cursor and saves them to the database in batches.
+// Files that already exist in the database, including ones with the same name and size, are counted as duplicates.
+// In dry-run mode db is only used to check for duplicates by id.
+func ImportLegacy(ctx context.Context, cursor database.Cursor, db database.Database, opts LegacyImportOptions) (ImportResult, error) {
+	var (
+		result ImportResult
+		batch  []*model.File
+	)
+
+	batchSize := opts.BatchSize
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+
+		if opts.DryRun {
+			for _, f := range batch {
+				if _, err := db.GetFile(f.UniqueId); err == nil {
+					result.Duplicate++
+				} else {
+					result.Inserted++
+				}
+			}
+		} else {
+			errs := db.SaveFiles(batch...)
+
+			result.Inserted += len(batch) - len(errs)
+
+			for _, err := range errs {
+				var existsErr database.FileAlreadyExistsError
+				if errors.As(err, &existsErr) {
+					result.Duplicate++
+				} else {
+					result.Invalid++
+				}
+			}
+		}
+
+		batch = batch[:0]
+
+		if opts.OnProgress != nil {
+			opts.OnProgress(result)
+		}
+	}
+
+	for cursor.Next(ctx) {
+		var l LegacyFile
+
+		if err := cursor.Decode(&l); err != nil {
+			result.Invalid++
+			continue
+		}
+
+		f, err := l.ToFile()
+		if err != nil {
+			result.Invalid++
+			continue
+		}
+
+		batch = append(batch, f)
+
+		if len(batch) >= batchSize {
+			flush()
+		}
+	}
+
+	flush()
+
+	if err := cursor.Err(); err != nil {
+		return result, err
+	}
+
+	return result, ctx.Err()
+}
@@ -0,0 +1,124 @@
+package catalog_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Jisin0/autofilterbot/internal/catalog"
+	"github.com/Jisin0/autofilterbot/internal/database/memory"
+	"github.com/Jisin0/autofilterbot/internal/model"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	mongodriver "go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestLegacyFileToFile(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    catalog.LegacyFile
+		expected *model.File
+		wantErr  bool
+	}{
+		{
+			name:  "video",
+			input: catalog.LegacyFile{ID: "BAADBAADa", FileRef: "ref", FileName: "Avatar 2009 720p mkv", FileSize: 1000, FileType: "video", MimeType: "video/x-matroska"},
+			expected: &model.File{
+				UniqueId: "BAADBAADa", FileId: "BAADBAADa", FileName: "Avatar 2009 720p mkv", FileType: model.FileTypeVideo, FileSize: 1000, Extension: "mkv",
+				Release: &model.Release{Title: "Avatar", Year: 2009, Resolution: "720p"},
+			},
+		},
+		{
+			name:     "decodable id",
+			input:    catalog.LegacyFile{ID: "BAADBQADAQAHAgAHFgQ", FileName: "Avatar", FileSize: 1000, FileType: "video"},
+			expected: &model.File{UniqueId: "AgADAQAH", FileId: "BAADBQADAQAHAgAHFgQ", FileName: "Avatar", FileType: model.FileTypeVideo, FileSize: 1000},
+		},
+		{
+			name:     "extension in name",
+			input:    catalog.LegacyFile{ID: "BQADBAADb", FileName: "Some.Album.mp3", FileSize: 10, MimeType: "audio/mpeg"},
+			expected: &model.File{UniqueId: "BQADBAADb", FileId: "BQADBAADb", FileName: "Some Album", FileType: model.FileTypeAudio, FileSize: 10, Extension: "mp3"},
+		},
+		{
+			name:     "name from caption",
+			input:    catalog.LegacyFile{ID: "BQADBAADc", FileName: "None", FileType: "document", Caption: "Notes.pdf"},
+			expected: &model.File{UniqueId: "BQADBAADc", FileId: "BQADBAADc", FileName: "Notes", FileType: model.FileTypeDocument, Extension: "pdf"},
+		},
+		{
+			name:    "missing id",
+			input:   catalog.LegacyFile{FileName: "Avatar", FileType: "video"},
+			wantErr: true,
+		},
+		{
+			name:    "missing name",
+			input:   catalog.LegacyFile{ID: "BQADBAADd", FileType: "video"},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			f, err := tc.input.ToFile()
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, f)
+		})
+	}
+}
+
+func TestImportLegacy(t *testing.T) {
+	docs := []interface{}{
+		bson.M{"_id": "BAADBAADa", "file_ref": "ref", "file_name": "Avatar 2009", "file_size": int32(1000), "file_type": "video", "mime_type": "video/mp4", "caption": nil},
+		bson.M{"_id": "BAADBAADb", "file_name": "Interstellar", "file_size": int64(2000), "file_type": "video"},
+		bson.M{"_id": "BAADBAADc", "file_name": "Avatar 2009", "file_size": int64(1000), "file_type": "video"}, // same name and size
+		bson.M{"_id": "BAADBAADd", "file_size": int64(1), "file_type": "video"},
+		bson.M{"_id": int32(5), "file_name": "Bad Id"},
+	}
+
+	for _, dryRun := range []bool{true, false} {
+		name := "save"
+		if dryRun {
+			name = "dry run"
+		}
+
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			cursor, err := mongodriver.NewCursorFromDocuments(docs, nil, nil)
+			if !assert.NoError(err) {
+				return
+			}
+
+			db := memory.NewClient()
+			assert.NoError(db.SaveFile(&model.File{UniqueId: "BAADBAADb", FileId: "BAADBAADb", FileName: "Interstellar", FileType: model.FileTypeVideo, FileSize: 2000}))
+
+			var progress []int
+
+			res, err := catalog.ImportLegacy(context.Background(), cursor, db, catalog.LegacyImportOptions{
+				BatchSize:  2,
+				DryRun:     dryRun,
+				OnProgress: func(r catalog.ImportResult) { progress = append(progress, r.Total()) },
+			})
+			assert.NoError(err)
+
+			if dryRun {
+				assert.Equal(catalog.ImportResult{Inserted: 2, Duplicate: 1, Invalid: 2}, res, "dry run only detects duplicate ids")
+				assert.Equal([]int{2, 5}, progress)
+
+				_, err = db.GetFile("BAADBAADa")
+				assert.Error(err, "dry run should not save files")
+
+				return
+			}
+
+			assert.Equal(catalog.ImportResult{Inserted: 1, Duplicate: 2, Invalid: 2}, res)
+
+			f, err := db.GetFile("BAADBAADa")
+			if assert.NoError(err) {
+				assert.Equal("mp4", f.Extension)
+			}
+		})
+	}
+}
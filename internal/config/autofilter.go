@@ -1,9 +1,20 @@
 package config
 
+import "github.com/Jisin0/autofilterbot/internal/model"
+
+const (
+	// SearchModeRegex matches the words of the query in order anywhere in the file name, newest files first.
+	SearchModeRegex = "regex"
+	// SearchModeText ranks files by relevance using the text index, falling back to regex for partial words.
+	SearchModeText = "text"
+)
+
 const (
 	defaultMaxResults = 50
 	defaultMaxPerPage = 10
 	defaultMaxPages   = 5
+
+	defaultPMSearchLimit = 5
 )
 
 func (c *Config) GetMaxResults() int {
@@ -23,8 +34,8 @@ func (c *Config) GetMaxPerPage() int {
 }
 
 func (c *Config) GetMaxPages() int {
-	if c.MaxResults != 0 {
-		return c.MaxResults
+	if c.MaxPages != 0 {
+		return c.MaxPages
 	}
 
 	return defaultMaxPages
@@ -61,3 +72,41 @@ func (c *Config) GetButtonTemplate() string {
 func (c *Config) GetSizeButton() bool {
 	return c.SizeButton
 }
+
+func (c *Config) GetGroupResults() bool {
+	return c.GroupResults
+}
+
+func (c *Config) GetPMSearch() bool {
+	return c.PMSearch
+}
+
+func (c *Config) GetPMSearchLimit() int {
+	if c.PMSearchLimit != 0 {
+		return c.PMSearchLimit
+	}
+
+	return defaultPMSearchLimit
+}
+
+func (c *Config) GetSearchChannels() []model.Channel {
+	return c.SearchChannels
+}
+
+// GetSearchChatIds returns the ids of the search channels to limit results to, nil if files from all channels can be searched.
+func (c *Config) GetSearchChatIds() []int64 {
+	var ids []int64
+	for _, ch := range c.SearchChannels {
+		ids = append(ids, ch.ID)
+	}
+
+	return ids
+}
+
+func (c *Config) GetSearchMode() string {
+	if c.SearchMode != "" {
+		return c.SearchMode
+	}
+
+	return SearchModeRegex
+}
@@ -56,6 +56,25 @@ type Config struct {
 
 	// File size is shown in separate button if set
 	SizeButton bool `json:"size_btn,omitempty" bson:"size_btn,omitempty"`
+	// Files with the same title and season are grouped into a single button if set.
+	GroupResults bool `json:"group_results,omitempty" bson:"group_results,omitempty"`
+	// Method used to search for files, one of the SearchMode values.
+	SearchMode string `json:"search_mode,omitempty" bson:"search_mode,omitempty"`
+	// Channels whose files can be searched, files from all channels are searched if empty.
+	SearchChannels []model.Channel `json:"search_channels,omitempty" bson:"search_channels,omitempty"`
+	// Plain text queries sent in the bot's private chat are answered with results if set.
+	PMSearch bool `json:"pm_search,omitempty" bson:"pm_search,omitempty"`
+	// Maximum number of queries a user can search for in the bot's private chat per minute.
+	PMSearchLimit int `json:"pm_search_limit,omitempty" bson:"pm_search_limit,omitempty"`
+	// New groups must be approved by an admin before the bot works in them if set.
+	GroupApproval bool `json:"group_approval,omitempty" bson:"group_approval,omitempty"`
+
+	// Secret used to sign deep links, generated on first start unless the LINK_SECRET variable is set.
+	LinkSecret string `json:"link_secret,omitempty" bson:"link_secret,omitempty"`
+	// Time in hours after which file links from results stop working, links never expire if zero.
+	LinkExpiry int `json:"link_expiry,omitempty" bson:"link_expiry,omitempty"`
+	// Unsigned file and batch links created before links were signed are rejected if set.
+	BlockLegacyLinks bool `json:"block_legacy_links,omitempty" bson:"block_legacy_links,omitempty"`
 
 	Shortener shortener.Shortener `json:"shortener,omitempty" bson:"shortener,omitempty"`
 
@@ -132,3 +151,15 @@ func (c *Config) GetFileCollectionIndex() int {
 func (c *Config) GetFileCollectiionUpdater() bool {
 	return c.FileCollectionUpdater
 }
+
+func (c *Config) GetGroupApproval() bool {
+	return c.GroupApproval
+}
+
+func (c *Config) GetLinkExpiry() int {
+	return c.LinkExpiry
+}
+
+func (c *Config) GetBlockLegacyLinks() bool {
+	return c.BlockLegacyLinks
+}
@@ -0,0 +1,133 @@
+package config
+
+import "github.com/Jisin0/autofilterbot/internal/model"
+
+// FieldNameAutofilter is the key of the autofilter toggle, it can only be set for groups.
+const FieldNameAutofilter = "autofilter"
+
+// GroupConfig contains settings of a single group that override the bot config, unset fields fall back to the bot config.
+// Fields are saved with the same keys as the bot config so config panel fields can be reused.
+type GroupConfig struct {
+	// Results are not sent in the group if set to false.
+	Autofilter *bool `json:"autofilter,omitempty" bson:"autofilter,omitempty"`
+	// Template to use for autofilter result message
+	ResultTemplate string `json:"af_template,omitempty" bson:"af_template,omitempty"`
+	// Time in minutes after which result message should be deleted.
+	AutodeleteTime int `json:"autodel_time,omitempty" bson:"autodel_time,omitempty"`
+	// Maximum number of files loaded for a query.
+	MaxResults int `json:"max_results,omitempty" bson:"max_results,omitempty"`
+	// File size is shown in separate button if set
+	SizeButton *bool `json:"size_btn,omitempty" bson:"size_btn,omitempty"`
+	// Force Subscribe Channels users must join to get files from the group, an empty list uses the bot's channels.
+	FsubChannels []model.Channel `json:"fsub,omitempty" bson:"fsub,omitempty"`
+	// Channels whose files can be searched from the group, only channels also searched by the bot are used and an empty list uses the bot's channels.
+	SearchChannels []model.Channel `json:"search_channels,omitempty" bson:"search_channels,omitempty"`
+}
+
+// GetAutofilter reports whether results should be sent in the group, it is enabled by default.
+func (g *GroupConfig) GetAutofilter() bool {
+	return g.Autofilter == nil || *g.Autofilter
+}
+
+// IsZero reports whether the group does not override any settings.
+func (g *GroupConfig) IsZero() bool {
+	return g.Autofilter == nil && g.ResultTemplate == "" && g.AutodeleteTime == 0 && g.MaxResults == 0 && g.SizeButton == nil && len(g.FsubChannels) == 0 && len(g.SearchChannels) == 0
+}
+
+// ToMap returns the fields that are set for the group by their keys.
+func (g *GroupConfig) ToMap() map[string]any {
+	vals := make(map[string]any)
+
+	if g.Autofilter != nil {
+		vals[FieldNameAutofilter] = *g.Autofilter
+	}
+
+	if g.ResultTemplate != "" {
+		vals[FieldNameResultTemplate] = g.ResultTemplate
+	}
+
+	if g.AutodeleteTime != 0 {
+		vals[FieldNameAutodeleteTime] = g.AutodeleteTime
+	}
+
+	if g.MaxResults != 0 {
+		vals[FieldNameMaxResults] = g.MaxResults
+	}
+
+	if g.SizeButton != nil {
+		vals[FieldNameSizeButton] = *g.SizeButton
+	}
+
+	if len(g.FsubChannels) != 0 {
+		vals[FieldNameFsub] = g.FsubChannels
+	}
+
+	if len(g.SearchChannels) != 0 {
+		vals[FieldNameSearchChannels] = g.SearchChannels
+	}
+
+	return vals
+}
+
+// ForGroup returns a copy of the config with the settings of the group applied.
+// The config itself is returned if the group does not override any settings.
+func (c *Config) ForGroup(g *GroupConfig) *Config {
+	if g == nil || g.IsZero() {
+		return c
+	}
+
+	r := *c
+	r.cachedMap = nil
+
+	if g.ResultTemplate != "" {
+		r.ResultTemplate = g.ResultTemplate
+	}
+
+	if g.AutodeleteTime != 0 {
+		r.AutodeleteTime = g.AutodeleteTime
+	}
+
+	if g.MaxResults != 0 {
+		r.MaxResults = g.MaxResults
+	}
+
+	if g.SizeButton != nil {
+		r.SizeButton = *g.SizeButton
+	}
+
+	// fsub can only be turned off from the bot config so group admins can't bypass the bot's channels
+	if len(g.FsubChannels) != 0 {
+		r.FsubChannels = g.FsubChannels
+	}
+
+	if len(g.SearchChannels) != 0 {
+		r.SearchChannels = narrowChannels(c.SearchChannels, g.SearchChannels)
+	}
+
+	return &r
+}
+
+// narrowChannels returns the channels of a group that are also in the bot's channels so a group can't search channels the bot excludes.
+// The bot's channels are returned if none of the group's channels are allowed.
+func narrowChannels(bot, group []model.Channel) []model.Channel {
+	if len(bot) == 0 {
+		return group
+	}
+
+	var allowed []model.Channel
+
+	for _, ch := range group {
+		for _, b := range bot {
+			if b.ID == ch.ID {
+				allowed = append(allowed, ch)
+				break
+			}
+		}
+	}
+
+	if len(allowed) == 0 {
+		return bot
+	}
+
+	return allowed
+}
@@ -0,0 +1,67 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/Jisin0/autofilterbot/internal/config"
+	"github.com/Jisin0/autofilterbot/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigForGroup(t *testing.T) {
+	assert := assert.New(t)
+
+	var (
+		enabled  = true
+		disabled = false
+	)
+
+	c := &config.Config{
+		MaxResults:     100,
+		SizeButton:     true,
+		AutodeleteTime: 10,
+		FsubChannels:   []model.Channel{{ID: -1001}},
+		SearchChannels: []model.Channel{{ID: -1002}, {ID: -1003}},
+	}
+
+	assert.Same(c, c.ForGroup(&config.GroupConfig{}), "empty group config")
+	assert.True((&config.GroupConfig{}).GetAutofilter(), "autofilter enabled by default")
+	assert.False((&config.GroupConfig{Autofilter: &disabled}).GetAutofilter())
+
+	g := &config.GroupConfig{
+		Autofilter:     &enabled,
+		ResultTemplate: "{query}",
+		MaxResults:     20,
+		SizeButton:     &disabled,
+		FsubChannels:   []model.Channel{{ID: -1004}},
+		SearchChannels: []model.Channel{{ID: -1003}},
+	}
+
+	r := c.ForGroup(g)
+	assert.Equal("{query}", r.GetResultTemplate())
+	assert.Equal(20, r.GetMaxResults())
+	assert.False(r.GetSizeButton())
+	assert.Equal(10, r.GetAutodeleteTime(), "unset fields fall back to the bot config")
+	assert.Equal([]model.Channel{{ID: -1004}}, r.GetFsubChannels())
+	assert.Same(c, c.ForGroup(&config.GroupConfig{FsubChannels: []model.Channel{}}), "empty fsub list uses the bot's channels")
+	assert.Equal([]int64{-1003}, r.GetSearchChatIds())
+	assert.Equal([]int64{-1002, -1003}, c.ForGroup(&config.GroupConfig{SearchChannels: []model.Channel{}}).GetSearchChatIds(), "empty list uses the bot's channels")
+	assert.Equal([]int64{-1002}, c.ForGroup(&config.GroupConfig{SearchChannels: []model.Channel{{ID: -1002}, {ID: -1009}}}).GetSearchChatIds(), "channels not searched by the bot are dropped")
+	assert.Equal([]int64{-1002, -1003}, c.ForGroup(&config.GroupConfig{SearchChannels: []model.Channel{{ID: -1009}}}).GetSearchChatIds(), "group can't widen the bot's channels")
+	assert.Equal([]int64{-1009}, (&config.Config{}).ForGroup(&config.GroupConfig{SearchChannels: []model.Channel{{ID: -1009}}}).GetSearchChatIds(), "any channel narrows all channels")
+
+	assert.Equal(100, c.GetMaxResults(), "bot config should not change")
+	assert.True(c.GetSizeButton())
+	assert.Len(c.GetFsubChannels(), 1)
+	assert.Equal([]int64{-1002, -1003}, c.GetSearchChatIds())
+
+	assert.Equal(map[string]any{
+		config.FieldNameAutofilter:     true,
+		config.FieldNameResultTemplate: "{query}",
+		config.FieldNameMaxResults:     20,
+		config.FieldNameSizeButton:     false,
+		config.FieldNameFsub:           []model.Channel{{ID: -1004}},
+		config.FieldNameSearchChannels: []model.Channel{{ID: -1003}},
+	}, g.ToMap())
+	assert.Empty((&config.GroupConfig{FsubChannels: []model.Channel{}, SearchChannels: []model.Channel{}}).ToMap(), "empty lists are not set")
+}
@@ -23,6 +23,15 @@ const (
 	FieldNameBatchSize         = "batch_size"
 	FieldNameCollectionIndex   = "collection_index"
 	FieldNameCollectionUpdater = "collection_updater"
+	FieldNameSearchMode        = "search_mode"
+	FieldNameSearchChannels    = "search_channels"
+	FieldNameGroupResults      = "group_results"
+	FieldNamePMSearch          = "pm_search"
+	FieldNamePMSearchLimit     = "pm_search_limit"
+	FieldNameGroupApproval     = "group_approval"
+	FieldNameLinkSecret        = "link_secret"
+	FieldNameLinkExpiry        = "link_expiry"
+	FieldNameBlockLegacyLinks  = "block_legacy_links"
 )
 
 // ToMap converts the contents of the struct into map so fields can be dynamically accessed.
@@ -56,6 +65,14 @@ func (c *Config) toMap() map[string]any {
 	vals[FieldNameFdetailsTemplate] = c.GetFileDetailsTemplate()
 	vals[FieldNameSizeButton] = c.GetSizeButton()
 	vals[FieldNameAutodeleteTime] = c.GetAutodeleteTime()
+	vals[FieldNameSearchMode] = c.GetSearchMode()
+	vals[FieldNameSearchChannels] = c.GetSearchChannels()
+	vals[FieldNameGroupResults] = c.GetGroupResults()
+	vals[FieldNamePMSearch] = c.GetPMSearch()
+	vals[FieldNamePMSearchLimit] = c.GetPMSearchLimit()
+	vals[FieldNameGroupApproval] = c.GetGroupApproval()
+	vals[FieldNameLinkExpiry] = c.GetLinkExpiry()
+	vals[FieldNameBlockLegacyLinks] = c.GetBlockLegacyLinks()
 
 	vals[FieldNameFsubText] = c.GetFsubText()
 	vals[FieldNameFileCaption] = c.GetFileCaption()
@@ -102,6 +102,8 @@ func (c *Config) GetHelpMessage() *message.Message {
 /settings - customize me
 /batch - bunch up messages
 /genlink - link to single file
+/batches - manage batch links
+/collect - curate file collections
 /index - gather up files
 /delete - assassinate a file
 /deleteall - massacre matching files
@@ -134,6 +136,7 @@ func (c *Config) GetStatsMessage() *message.Message {
 		text = `
 ╭ ▸ 𝖴𝗌𝖾𝗋𝗌 : <code>{users}</code>
 ├ ▸ 𝖥𝗂𝗅𝖾𝗌 : <code>{files}</code>
+├ ▸ 𝖣𝗈𝗐𝗇𝗅𝗈𝖺𝖽𝗌 : <code>{downloads}</code>
 ├ ▸ 𝖦𝗋𝗈𝗎𝗉𝗌 : <code>{groups}</code>
 ╰ ▸ 𝖴𝗉𝗍𝗂𝗆𝖾 : <code>{uptime}</code>
 `
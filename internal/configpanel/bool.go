@@ -2,17 +2,22 @@ package configpanel
 
 import (
 	"fmt"
+	"strconv"
 
 	"github.com/Jisin0/autofilterbot/pkg/panel"
 	"github.com/PaulSonOfLars/gotgbot/v2"
 )
 
 // BoolField is a helper for modifying bool fields.
+//
+// Disabling a field of the bot config resets it, while groups save false so that the bot config can be overridden.
+// Groups have an additional Default option to use the value from the bot config.
 func BoolField(app AppPreview, fieldName string, description ...string) panel.CallbackFunc {
 	return func(ctx *panel.Context) (string, [][]gotgbot.InlineKeyboardButton, error) {
 		var (
-			op   string
-			data = ctx.CallbackData
+			op      string
+			data    = ctx.CallbackData
+			isGroup = groupId(ctx) != 0
 		)
 
 		if len(data.Args) != 0 {
@@ -23,14 +28,23 @@ func BoolField(app AppPreview, fieldName string, description ...string) panel.Ca
 
 		switch op {
 		case OperationSet:
-			err := app.GetDB().UpdateConfig(ctx.Bot.Id, fieldName, true)
+			val := true
+			if len(data.Args) > 1 {
+				val, _ = strconv.ParseBool(data.Args[1])
+			}
+
+			err := updateField(app, ctx, fieldName, val)
 			if err != nil {
 				return "", nil, err
 			}
 
-			s = fmt.Sprintf("<i><b>✅ %s has been Enabled !</b></i>", ctx.Page.DisplayName)
+			if val {
+				s = fmt.Sprintf("<i><b>✅ %s has been Enabled !</b></i>", ctx.Page.DisplayName)
+			} else {
+				s = fmt.Sprintf("<i><b>✅ %s has been Disabled !</b></i>", ctx.Page.DisplayName)
+			}
 		case OperationReset:
-			err := app.GetDB().ResetConfig(ctx.Bot.Id, fieldName)
+			err := resetField(app, ctx, fieldName)
 			if err != nil {
 				return "", nil, err
 			}
@@ -42,12 +56,25 @@ func BoolField(app AppPreview, fieldName string, description ...string) panel.Ca
 				s = "ℹ️ " + description[0]
 			}
 
-			return s + fmt.Sprintf("<i>Use The Buttons Below to Enable/Disable %s</i>", ctx.Page.DisplayName),
-				[][]gotgbot.InlineKeyboardButton{{{Text: "Enable", CallbackData: data.RemoveArgs().AddArg(OperationSet).ToString()}, {Text: "Disable", CallbackData: data.RemoveArgs().AddArg(OperationReset).ToString()}}},
+			if !isGroup {
+				return s + fmt.Sprintf("<i>Use The Buttons Below to Enable/Disable %s</i>", ctx.Page.DisplayName),
+					[][]gotgbot.InlineKeyboardButton{{{Text: "Enable", CallbackData: data.RemoveArgs().AddArg(OperationSet).ToString()}, {Text: "Disable", CallbackData: data.RemoveArgs().AddArg(OperationReset).ToString()}}},
+					nil
+			}
+
+			if v, ok := fieldValue(app, ctx, fieldName); ok {
+				s += fmt.Sprintf("<i><b>⭕ Current Value: %v</b></i>\n\n", v)
+			}
+
+			return s + fmt.Sprintf("<i>Use The Buttons Below to Enable/Disable %s in This Group or Use the Bot's Default</i>", ctx.Page.DisplayName),
+				[][]gotgbot.InlineKeyboardButton{
+					{{Text: "Enable", CallbackData: data.RemoveArgs().AddArgs(OperationSet, "1").ToString()}, {Text: "Disable", CallbackData: data.RemoveArgs().AddArgs(OperationSet, "0").ToString()}},
+					{{Text: "Default", CallbackData: data.RemoveArgs().AddArg(OperationReset).ToString()}},
+				},
 				nil
 		}
 
-		go app.RefreshConfig() // is a goroutine a bit overkill here
+		refreshConfig(app, ctx)
 
 		return s, nil, nil
 	}
@@ -6,7 +6,6 @@ import (
 	"strconv"
 	"strings"
 
-	"github.com/Jisin0/autofilterbot/internal/config"
 	"github.com/Jisin0/autofilterbot/internal/model"
 	"github.com/Jisin0/autofilterbot/pkg/conversation"
 	"github.com/Jisin0/autofilterbot/pkg/panel"
@@ -23,6 +22,8 @@ type ChannelFieldOpts struct {
 	MaxAmount int
 	// Indicates whether the user should be asked if a request invite link should be generated.
 	AllowRequestInvite bool
+	// Indicates whether channels are saved without an invite link, for channels that users don't have to join.
+	NoInviteLink bool
 }
 
 func ChannelField(app AppPreview, fieldName string, opts ChannelFieldOpts) panel.CallbackFunc {
@@ -36,7 +37,10 @@ func ChannelField(app AppPreview, fieldName string, opts ChannelFieldOpts) panel
 			op = data.Args[0]
 		}
 
-		currentChannels := app.GetConfig().GetFsubChannels()
+		// the cached config is not modified when channels are changed
+		v, _ := fieldValue(app, ctx, fieldName)
+		currentChannels, _ := v.([]model.Channel)
+		currentChannels = slices.Clone(currentChannels)
 
 		switch op {
 		case OperationDelete:
@@ -53,8 +57,8 @@ func ChannelField(app AppPreview, fieldName string, opts ChannelFieldOpts) panel
 				if c.ID == channelID {
 					currentChannels = slices.Delete(currentChannels, i, i+1)
 
-					app.GetDB().UpdateConfig(ctx.Bot.Id, config.FieldNameFsub, currentChannels)
-					go app.RefreshConfig()
+					updateField(app, ctx, fieldName, currentChannels)
+					refreshConfig(app, ctx)
 
 					return fieldName + " Channel was Deleted Successfully ✅", nil, nil
 				}
@@ -73,8 +77,8 @@ func ChannelField(app AppPreview, fieldName string, opts ChannelFieldOpts) panel
 				return "Reset Operation Cancelled!", nil, nil
 			}
 
-			app.GetDB().ResetConfig(ctx.Bot.Id, config.FieldNameFsub)
-			go app.RefreshConfig()
+			resetField(app, ctx, fieldName)
+			refreshConfig(app, ctx)
 
 			return fieldName + " Channels Have Been Reset Succesfully ✅", nil, nil
 		case OperationSet:
@@ -112,6 +116,11 @@ func ChannelField(app AppPreview, fieldName string, opts ChannelFieldOpts) panel
 				return "", nil, err
 			}
 
+			// group admins could otherwise get invite links of private channels the bot is an admin of
+			if groupId(ctx) != 0 && !isChannelAdmin(ctx.Bot, chat.Id, ctx.CallbackQuery.From.Id) {
+				return "You Must be an Admin of the Channel to Add it!", nil, nil
+			}
+
 			for _, c := range currentChannels {
 				if c.ID == chat.Id {
 					return "New channel is already add!", nil, nil
@@ -125,7 +134,7 @@ func ChannelField(app AppPreview, fieldName string, opts ChannelFieldOpts) panel
 				if err != nil {
 					return "", nil, errors.Wrap(err, "configpanel: channel: failed to parse 'is request' arg")
 				}
-			} else if opts.AllowRequestInvite {
+			} else if opts.AllowRequestInvite && !opts.NoInviteLink {
 				return "<b>Would you like to add the channel as a request invite channel?</b> \n\nThe user will send a join request, requiring admin approval, instead of joining directly.",
 					[][]gotgbot.InlineKeyboardButton{{
 						{Text: "Yes", CallbackData: ctx.CallbackData.AddArg(fmt.Sprint(chatID)).AddArg("1").ToString()},
@@ -134,21 +143,26 @@ func ChannelField(app AppPreview, fieldName string, opts ChannelFieldOpts) panel
 					nil
 			}
 
-			link, err := ctx.Bot.CreateChatInviteLink(chat.Id, &gotgbot.CreateChatInviteLinkOpts{Name: fieldName, CreatesJoinRequest: isRequest})
-			if err != nil {
-				app.GetLog().Debug("configpanel: channel: failed to generate invite link", zap.Int64("id", chat.Id), zap.Error(err))
-				return "Failed to Create Invite Link. Please Make Sure the bot has Permissions to Add Users", nil, nil
-			}
-
-			currentChannels = append(currentChannels, model.Channel{
+			channel := model.Channel{
 				ID:                 chat.Id,
 				Title:              chat.Title,
-				InviteLink:         link.InviteLink,
 				CreatesJoinRequest: isRequest,
-			})
+			}
 
-			app.GetDB().UpdateConfig(ctx.Bot.Id, config.FieldNameFsub, currentChannels)
-			go app.RefreshConfig()
+			if !opts.NoInviteLink {
+				link, err := ctx.Bot.CreateChatInviteLink(chat.Id, &gotgbot.CreateChatInviteLinkOpts{Name: fieldName, CreatesJoinRequest: isRequest})
+				if err != nil {
+					app.GetLog().Debug("configpanel: channel: failed to generate invite link", zap.Int64("id", chat.Id), zap.Error(err))
+					return "Failed to Create Invite Link. Please Make Sure the bot has Permissions to Add Users", nil, nil
+				}
+
+				channel.InviteLink = link.InviteLink
+			}
+
+			currentChannels = append(currentChannels, channel)
+
+			updateField(app, ctx, fieldName, currentChannels)
+			refreshConfig(app, ctx)
 
 			return fmt.Sprintf("%s has been Saved as a %s Channel Successfully ✅", chat.Title, fieldName), nil, nil
 		case OperationRefresh:
@@ -184,17 +198,20 @@ func ChannelField(app AppPreview, fieldName string, opts ChannelFieldOpts) panel
 				return "Channel was not found in saved channels!", nil, nil
 			}
 
-			link, err := ctx.Bot.CreateChatInviteLink(chat.Id, &gotgbot.CreateChatInviteLinkOpts{Name: fieldName, CreatesJoinRequest: channel.CreatesJoinRequest})
-			if err != nil {
-				app.GetLog().Debug("configpanel: channel: failed to generate invite link", zap.Int64("id", chat.Id), zap.Error(err))
-				return "Failed to Create Invite Link. Please Make Sure the bot has Permissions to Add Users", nil, nil
-			}
-
 			currentChannels[*channelIndex].Title = chat.Title
-			currentChannels[*channelIndex].InviteLink = link.InviteLink
 
-			app.GetDB().UpdateConfig(ctx.Bot.Id, config.FieldNameFsub, currentChannels)
-			go app.RefreshConfig()
+			if !opts.NoInviteLink {
+				link, err := ctx.Bot.CreateChatInviteLink(chat.Id, &gotgbot.CreateChatInviteLinkOpts{Name: fieldName, CreatesJoinRequest: channel.CreatesJoinRequest})
+				if err != nil {
+					app.GetLog().Debug("configpanel: channel: failed to generate invite link", zap.Int64("id", chat.Id), zap.Error(err))
+					return "Failed to Create Invite Link. Please Make Sure the bot has Permissions to Add Users", nil, nil
+				}
+
+				currentChannels[*channelIndex].InviteLink = link.InviteLink
+			}
+
+			updateField(app, ctx, fieldName, currentChannels)
+			refreshConfig(app, ctx)
 
 			return "Channel Information has been Updated Successfully ✅", nil, nil
 		default:
@@ -217,7 +234,12 @@ func ChannelField(app AppPreview, fieldName string, opts ChannelFieldOpts) panel
 			var keybaord [][]gotgbot.InlineKeyboardButton
 
 			for _, c := range currentChannels {
-				keybaord = append(keybaord, []gotgbot.InlineKeyboardButton{{Text: c.Title, Url: c.InviteLink}})
+				if c.InviteLink != "" {
+					keybaord = append(keybaord, []gotgbot.InlineKeyboardButton{{Text: c.Title, Url: c.InviteLink}})
+				} else {
+					keybaord = append(keybaord, []gotgbot.InlineKeyboardButton{{Text: c.Title, CallbackData: "ignore"}})
+				}
+
 				keybaord = append(keybaord, []gotgbot.InlineKeyboardButton{
 					{Text: "🗑️ Delete", CallbackData: ctx.CallbackData.AddArgs(OperationDelete, fmt.Sprint(c.ID)).ToString()},
 					{Text: "🔄 Refresh", CallbackData: ctx.CallbackData.AddArgs(OperationRefresh, fmt.Sprint(c.ID)).ToString()},
@@ -233,3 +255,18 @@ func ChannelField(app AppPreview, fieldName string, opts ChannelFieldOpts) panel
 		}
 	}
 }
+
+// isChannelAdmin reports whether the user is an admin of the channel.
+func isChannelAdmin(bot *gotgbot.Bot, channelId, userId int64) bool {
+	member, err := bot.GetChatMember(channelId, userId, nil)
+	if err != nil {
+		return false
+	}
+
+	switch member.(type) {
+	case gotgbot.ChatMemberOwner, gotgbot.ChatMemberAdministrator:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,119 @@
+package configpanel
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Jisin0/autofilterbot/pkg/panel"
+	"github.com/PaulSonOfLars/gotgbot/v2"
+	"go.uber.org/zap"
+)
+
+// Choice is a single possible value of a ChoiceField.
+type Choice struct {
+	// Value saved to the config, must not contain callback data delimiters.
+	Value string
+	// Name shown on the button.
+	Name string
+	// Optional short description of the choice.
+	Description string
+}
+
+// ChoiceFieldOpts wraps optional values to ChoiceField().
+type ChoiceFieldOpts struct {
+	// Description for the field.
+	Description string
+}
+
+// ChoiceField is a helper for configuring string fields that can only be set to one of the given choices.
+func ChoiceField(app AppPreview, fieldName string, choices []Choice, opts ChoiceFieldOpts) panel.CallbackFunc {
+	return func(ctx *panel.Context) (string, [][]gotgbot.InlineKeyboardButton, error) {
+		var (
+			op   string
+			data = ctx.CallbackData
+		)
+
+		if len(data.Args) != 0 {
+			op = data.Args[0]
+		}
+
+		var s string
+
+		switch op {
+		case OperationSet:
+			if len(data.Args) < 2 {
+				app.GetLog().Warn("configpanel: choice: not enough args for set", zap.Strings("args", data.Args))
+				return s, nil, fmt.Errorf("not enough argmuents")
+			}
+
+			c, ok := findChoice(choices, data.Args[1])
+			if !ok {
+				return "", nil, fmt.Errorf("unknown value %s received for field %s", data.Args[1], fieldName)
+			}
+
+			err := updateField(app, ctx, fieldName, c.Value)
+			if err != nil {
+				return "", nil, err
+			}
+
+			s = fmt.Sprintf("<i><b>✅ %s has been set to %s!</b></i>", ctx.Page.DisplayName, c.Name)
+		default:
+			var s strings.Builder
+
+			if opts.Description != "" {
+				s.WriteString(fmt.Sprintf("ℹ️ %s\n\n", opts.Description))
+			}
+
+			for _, c := range choices {
+				if c.Description != "" {
+					s.WriteString(fmt.Sprintf("<b>%s</b>: <i>%s</i>\n", c.Name, c.Description))
+				}
+			}
+
+			if v, ok := fieldValue(app, ctx, fieldName); ok {
+				if c, ok := findChoice(choices, fmt.Sprint(v)); ok {
+					s.WriteString(fmt.Sprintf("\n<i><b>⭕ Current Value: %s</b></i>\n", c.Name))
+				}
+			}
+
+			s.WriteString(fmt.Sprintf("\n<i>Use The Buttons Below to Change %s</i>", ctx.Page.DisplayName))
+
+			keyboard := make([][]gotgbot.InlineKeyboardButton, 0, len(choices)/buttonsPerRow+1)
+
+			for i := 0; i < len(choices); i += buttonsPerRow {
+				end := i + buttonsPerRow
+				if end > len(choices) {
+					end = len(choices)
+				}
+
+				row := make([]gotgbot.InlineKeyboardButton, 0, end-i)
+
+				for _, c := range choices[i:end] {
+					row = append(row, gotgbot.InlineKeyboardButton{
+						Text:         c.Name,
+						CallbackData: ctx.CallbackData.AddArg(OperationSet).AddArg(c.Value).ToString(),
+					})
+				}
+
+				keyboard = append(keyboard, row)
+			}
+
+			return s.String(), keyboard, nil
+		}
+
+		refreshConfig(app, ctx)
+
+		return s, nil, nil
+	}
+}
+
+// findChoice finds the choice with given value.
+func findChoice(choices []Choice, value string) (Choice, bool) {
+	for _, c := range choices {
+		if c.Value == value {
+			return c, true
+		}
+	}
+
+	return Choice{}, false
+}
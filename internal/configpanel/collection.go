@@ -0,0 +1,191 @@
+package configpanel
+
+import (
+	"fmt"
+	"html"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Jisin0/autofilterbot/internal/database"
+	"github.com/Jisin0/autofilterbot/internal/model"
+	"github.com/Jisin0/autofilterbot/pkg/conversation"
+	"github.com/Jisin0/autofilterbot/pkg/panel"
+	"github.com/PaulSonOfLars/gotgbot/v2"
+	"github.com/pkg/errors"
+)
+
+const (
+	collectionOperationView       = "view"
+	collectionOperationRename     = "ren"
+	collectionOperationFiles      = "files"
+	collectionOperationRemoveFile = "rmf"
+	collectionOperationMoveUp     = "up"
+)
+
+// CollectionField is a page to view, rename, reorder and delete file collections created using /collect.
+func CollectionField(app AppPreview) panel.CallbackFunc {
+	return func(ctx *panel.Context) (string, [][]gotgbot.InlineKeyboardButton, error) {
+		var (
+			op   string
+			data = ctx.CallbackData.RemoveArgs()
+			db   = app.GetDB()
+		)
+
+		if len(ctx.CallbackData.Args) != 0 {
+			op = ctx.CallbackData.Args[0]
+		}
+
+		if op == "" {
+			collections, err := db.GetCollections()
+			if err != nil {
+				return "", nil, errors.Wrap(err, "configpanel: collection: get collections failed")
+			}
+
+			if len(collections) == 0 {
+				return "<i>📂 No Collections Have Been Created Yet, Use /collect or the Select Menu of Results to Create One.</i>", nil, nil
+			}
+
+			btns := make([][]gotgbot.InlineKeyboardButton, 0, len(collections))
+			for _, c := range collections {
+				btns = append(btns, []gotgbot.InlineKeyboardButton{{
+					Text:         fmt.Sprintf("%s (%d)", c.Name, len(c.Files)),
+					CallbackData: data.AddArgs(collectionOperationView, c.ID).ToString(),
+				}})
+			}
+
+			return "<b>📂 Collections</b>\n\n<i>Select a Collection to Manage it 👇</i>", btns, nil
+		}
+
+		id, ok := ctx.CallbackData.GetArg(1)
+		if !ok {
+			return "", nil, errors.New("configpanel: collection: insufficient data for operation")
+		}
+
+		c, err := db.GetCollection(id)
+		if err != nil {
+			if database.IsNoDocumentsError(err) {
+				return "Collection was not found, it may have been deleted!", nil, nil
+			}
+
+			return "", nil, errors.Wrap(err, "configpanel: collection: get collection failed")
+		}
+
+		switch op {
+		case collectionOperationView:
+			link := fmt.Sprintf("https://t.me/%s?start=%s", ctx.Bot.Username, c.StartData())
+
+			var s strings.Builder
+
+			s.WriteString(fmt.Sprintf("<b>📂 %s</b>\n\n<b>Files</b>: %d/%d\n<b>Link</b>: <code>%s</code>\n\n", html.EscapeString(c.Name), len(c.Files), model.CollectionMaxFiles, link))
+
+			for i, name := range collectionFileNames(db, c) {
+				s.WriteString(fmt.Sprintf("%d. %s\n", i+1, html.EscapeString(name)))
+			}
+
+			return s.String(),
+				[][]gotgbot.InlineKeyboardButton{
+					{
+						{Text: "✏️ Rename", CallbackData: data.AddArgs(collectionOperationRename, c.ID).ToString()},
+						{Text: "🗂 Files", CallbackData: data.AddArgs(collectionOperationFiles, c.ID).ToString()},
+					},
+					{{Text: "🗑 Delete", CallbackData: data.AddArgs(OperationDelete, c.ID).ToString()}},
+				},
+				nil
+		case collectionOperationRename:
+			conv := conversation.NewConversatorFromUpdate(ctx.Bot, ctx.Update.Update)
+
+			m, err := conv.Ask(app.GetContext(), fmt.Sprintf("Please Send the New Name of <b>%s</b>. Send /cancel to Cancel:", html.EscapeString(c.Name)), nil)
+			if err != nil {
+				return "", nil, errors.Wrap(err, "configpanel: collection: send name request message failed")
+			}
+
+			name := strings.TrimSpace(m.Text)
+			if name == "" || strings.HasPrefix(name, "/cancel") {
+				return "Operation Cancelled!", nil, nil
+			}
+
+			c.Name = name
+
+			err = db.SaveCollection(c)
+			if err != nil {
+				return "", nil, err
+			}
+
+			return fmt.Sprintf("<i><b>✅ Collection was Renamed to %s !</b></i>", html.EscapeString(name)), nil, nil
+		case collectionOperationRemoveFile, collectionOperationMoveUp:
+			s, _ := ctx.CallbackData.GetArg(2)
+
+			i, err := strconv.Atoi(s)
+			if err != nil || i < 0 || i >= len(c.Files) {
+				return "File to Change Was not Found 🫤", nil, nil
+			}
+
+			if op == collectionOperationRemoveFile {
+				c.Files = slices.Delete(c.Files, i, i+1)
+			} else if i > 0 {
+				c.Files[i-1], c.Files[i] = c.Files[i], c.Files[i-1]
+			}
+
+			err = db.SaveCollection(c)
+			if err != nil {
+				return "", nil, err
+			}
+
+			fallthrough
+		case collectionOperationFiles:
+			if len(c.Files) == 0 {
+				return "<i>The Collection is Empty, Use /collect to Add Files.</i>", nil, nil
+			}
+
+			names := collectionFileNames(db, c)
+			btns := make([][]gotgbot.InlineKeyboardButton, 0, len(names))
+
+			for i, name := range names {
+				btns = append(btns, []gotgbot.InlineKeyboardButton{
+					{Text: "🗑 " + name, CallbackData: data.AddArgs(collectionOperationRemoveFile, c.ID, strconv.Itoa(i)).ToString()},
+					{Text: "⬆️", CallbackData: data.AddArgs(collectionOperationMoveUp, c.ID, strconv.Itoa(i)).ToString()},
+				})
+			}
+
+			return fmt.Sprintf("<b>🗂 Files in %s</b>\n\n<i>Tap a File to Remove it or ⬆️ to Move it Up 👇</i>", html.EscapeString(c.Name)), btns, nil
+		case OperationDelete:
+			conv := conversation.NewConversatorFromUpdate(ctx.Bot, ctx.Update.Update)
+
+			m, err := conv.Ask(app.GetContext(), fmt.Sprintf("Are you sure you want to delete <b>%s</b>? Its link will stop working. (y/N)", html.EscapeString(c.Name)), nil)
+			if err != nil {
+				return "", nil, errors.Wrap(err, "configpanel: collection: send delete confirmation message failed")
+			}
+
+			if strings.ToLower(m.Text) != "y" {
+				return "Delete Operation Cancelled!", nil, nil
+			}
+
+			err = db.DeleteCollection(c.ID)
+			if err != nil {
+				return "", nil, err
+			}
+
+			return fmt.Sprintf("%s Collection was Deleted Successfully ✅", html.EscapeString(c.Name)), nil, nil
+		default:
+			return "", nil, fmt.Errorf("configpanel: collection: unknown operation %s", op)
+		}
+	}
+}
+
+// collectionFileNames returns the names of the files in a collection, the unique id is used for files that were deleted.
+func collectionFileNames(db database.Database, c *model.Collection) []string {
+	names := make([]string, 0, len(c.Files))
+
+	for _, uid := range c.Files {
+		f, err := db.GetFile(uid)
+		if err != nil {
+			names = append(names, uid)
+			continue
+		}
+
+		names = append(names, f.FileName)
+	}
+
+	return names
+}
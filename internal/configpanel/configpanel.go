@@ -7,7 +7,7 @@ import (
 	"context"
 
 	"github.com/Jisin0/autofilterbot/internal/config"
-	"github.com/Jisin0/autofilterbot/internal/database/mongo"
+	"github.com/Jisin0/autofilterbot/internal/database"
 	"github.com/Jisin0/autofilterbot/pkg/panel"
 	"go.uber.org/zap"
 )
@@ -21,10 +21,12 @@ const (
 
 type AppPreview interface {
 	GetContext() context.Context
-	GetDB() *mongo.Client
+	GetDB() database.Database
 	GetConfig() *config.Config
 	GetLog() *zap.Logger
 	RefreshConfig()
+	GetGroupConfig(groupId int64) *config.GroupConfig
+	RefreshGroupConfig(groupId int64)
 	GetAdditionalCollectionCount() int
 	SetCollectionIndex(index int)
 }
@@ -34,9 +36,41 @@ func CreatePanel(app AppPreview) *panel.Panel {
 	p := panel.NewPanel()
 
 	p.AddPage(panel.NewPage("sizebtn", "Size Button").WithCallbackFunc(BoolField(app, config.FieldNameSizeButton)))
+	p.AddPage(panel.NewPage("group", "Group Results").WithCallbackFunc(BoolField(app, config.FieldNameGroupResults, "Files of the Same Movie or Season are Shown as a Single Button that Opens a Menu of Qualities or Episodes.\n\n")))
 	p.AddPage(panel.NewPage("autodel", "Auto Delete").WithCallbackFunc(TimeField(app, config.FieldNameAutodeleteTime, []int{5, 10, 15, 20, 30, 45})))
 	p.AddPage(panel.NewPage("filedel", "File AutoDelete").WithCallbackFunc(TimeField(app, config.FieldNameFileAutoDelete, []int{5, 10, 15, 20, 30, 45})))
 
+	p.AddPage(panel.NewPage("search", "Search Mode").WithCallbackFunc(ChoiceField(app, config.FieldNameSearchMode, []Choice{
+		{Value: config.SearchModeRegex, Name: "Regex", Description: "Matches all words of the query in order, newest files are shown first."},
+		{Value: config.SearchModeText, Name: "Ranked", Description: "Uses the text index to show the most relevant files first, partial words are matched after whole words."},
+	}, ChoiceFieldOpts{Description: "Choose How Files are Searched for Autofilter Results."})))
+	p.AddPage(panel.NewPage("schan", "Search Channels").WithCallbackFunc(ChannelField(app, config.FieldNameSearchChannels, ChannelFieldOpts{
+		Description:  "Only Files from These Channels are Shown in Results, Inline and PM Search. Files from All Channels are Searched if None are Added.",
+		NoInviteLink: true,
+	})))
+
+	pmPage := panel.NewPage("pm", "PM Search").WithContent("🔎 Configure Searching for Files in the Bot's Private Chat from the Options Below.")
+	pmPage.NewSubPage("toggle", "PM Search").WithCallbackFunc(BoolField(app, config.FieldNamePMSearch, "Queries Sent in the Bot's Private Chat are Answered with Results and Files are Sent Directly.\n\nSearch Links like t.me/YourBot?start=s_avatar_2009 also Open Results in PM when Enabled.\n\n"))
+	pmPage.NewSubPage("limit", "Rate Limit").WithCallbackFunc(IntField(app, config.FieldNamePMSearchLimit, IntFieldOpts{
+		PossibleValues: []int{1, 2, 3, 5, 10, 15, 20, 30},
+		Description:    "Maximum Number of Queries a User can Search for in PM Every Minute.",
+	}))
+
+	p.AddPage(pmPage)
+
+	p.AddPage(panel.NewPage("gapprove", "Group Approval").WithCallbackFunc(BoolField(app, config.FieldNameGroupApproval, "New Groups Must be Approved by an Admin from the Notification Sent when the Bot is Added, the Bot Stays Silent in Groups Waiting for Approval.\n\nUse /groups to Approve, Ban or Leave Groups.\n\n")))
+
+	linksPage := panel.NewPage("links", "Deep Links").WithContent("🔗 Configure Links that Send Files and Batches from the Options Below.")
+	linksPage.NewSubPage("expiry", "Link Expiry").WithCallbackFunc(IntField(app, config.FieldNameLinkExpiry, IntFieldOpts{
+		PossibleValues: []int{1, 6, 12, 24, 72, 168},
+		Description:    "Time in Hours After Which File Links from Results Stop Working. Links Never Expire After a Reset.",
+	}))
+	linksPage.NewSubPage("legacy", "Block Old Links").WithCallbackFunc(BoolField(app, config.FieldNameBlockLegacyLinks, "File and Batch Links Created Before Links Were Signed are Rejected When Enabled.\n\nOld Links can be Crafted by Anyone to Get Any File or Channel Post, Enable This Once They've Been Replaced.\n\n"))
+
+	p.AddPage(linksPage)
+
+	p.AddPage(panel.NewPage("coll", "Collections").WithCallbackFunc(CollectionField(app)))
+
 	p.NewPage("fsub", "Force Sub").WithCallbackFunc(ChannelField(app, config.FieldNameFsub, ChannelFieldOpts{Description: "Force Subcribe Channels are Channels that the User Must Join to get Files.", AllowRequestInvite: true}))
 
 	dbPage := panel.NewPage("db", "Database").WithContent("📂 Configure Database Settings from the Options Below.")
@@ -55,3 +89,29 @@ func CreatePanel(app AppPreview) *panel.Panel {
 
 	return p
 }
+
+// CreateGroupPanel creates the config panel of groups with the fields that can be overridden for a single group.
+// Callback data of the panel starts with GroupPanelPath so fields update the settings of the group.
+func CreateGroupPanel(app AppPreview) *panel.Panel {
+	p := panel.NewPanel().WithHomepageGenerator(func() string {
+		return "<b>⚙️ Group Settings</b>\n\nSettings changed here only apply to this group, settings that are not changed use the bot's default values 👇"
+	})
+
+	p.AddPage(panel.NewPage("af", "Autofilter").WithCallbackFunc(BoolField(app, config.FieldNameAutofilter, "Results are Sent for Queries in This Group When Enabled.\n\n")))
+	p.AddPage(panel.NewPage("sizebtn", "Size Button").WithCallbackFunc(BoolField(app, config.FieldNameSizeButton)))
+	p.AddPage(panel.NewPage("autodel", "Auto Delete").WithCallbackFunc(TimeField(app, config.FieldNameAutodeleteTime, []int{5, 10, 15, 20, 30, 45})))
+	p.AddPage(panel.NewPage("maxres", "Max Results").WithCallbackFunc(IntField(app, config.FieldNameMaxResults, IntFieldOpts{
+		PossibleValues: []int{10, 20, 30, 50, 75, 100},
+		Description:    "Maximum Number of Files Loaded at Once for a Query.",
+	})))
+	p.AddPage(panel.NewPage("template", "Result Template").WithCallbackFunc(TextField(app, config.FieldNameResultTemplate, TextFieldOpts{
+		Description: "Message Sent with Results. {mention}, {query} and {warn} are Replaced with the User, Query and Autodelete Warning.",
+	})))
+	p.AddPage(panel.NewPage("schan", "Search Channels").WithCallbackFunc(ChannelField(app, config.FieldNameSearchChannels, ChannelFieldOpts{
+		Description:  "Only Files from These Channels are Shown in This Group. Channels can Only Narrow the Bot's Search Channels, Others are Ignored. The Bot's Channels are Used Until a Channel is Added or After All are Deleted.",
+		NoInviteLink: true,
+	})))
+	p.NewPage("fsub", "Force Sub").WithCallbackFunc(ChannelField(app, config.FieldNameFsub, ChannelFieldOpts{Description: "Channels that Users Must Join to get Files from This Group. The Bot's Channels are Used Until a Channel is Added or After All are Deleted.", AllowRequestInvite: true}))
+
+	return p
+}
@@ -64,7 +64,7 @@ func IntField(app AppPreview, fieldName string, opts IntFieldOpts) panel.Callbac
 				return "", nil, fmt.Errorf("unknown value %d received for field %s", val, fieldName)
 			}
 
-			err = app.GetDB().UpdateConfig(ctx.Bot.Id, fieldName, val)
+			err = updateField(app, ctx, fieldName, val)
 			if err != nil {
 				return "", nil, err
 			}
@@ -75,7 +75,7 @@ func IntField(app AppPreview, fieldName string, opts IntFieldOpts) panel.Callbac
 
 			s = fmt.Sprintf("<i><b>✅ %s has been set to %d!</b></i>", ctx.Page.DisplayName, val)
 		case OperationReset:
-			err := app.GetDB().ResetConfig(ctx.Bot.Id, fieldName)
+			err := resetField(app, ctx, fieldName)
 			if err != nil {
 				return "", nil, err
 			}
@@ -94,7 +94,7 @@ func IntField(app AppPreview, fieldName string, opts IntFieldOpts) panel.Callbac
 
 			s.WriteString(fmt.Sprintf("<i><b>🧮 Select One of the Values Below to Update %s to Given Number\n\n", ctx.Page.DisplayName))
 
-			if v, ok := app.GetConfig().ToMap()[fieldName]; ok {
+			if v, ok := fieldValue(app, ctx, fieldName); ok {
 				if i, ok := v.(int); ok && i != 0 {
 					s.WriteString(fmt.Sprintf("⭕ Current Value: %d\n\n", i))
 				}
@@ -133,7 +133,7 @@ func IntField(app AppPreview, fieldName string, opts IntFieldOpts) panel.Callbac
 			return s.String(), keyboard, nil
 		}
 
-		go app.RefreshConfig()
+		refreshConfig(app, ctx)
 
 		return s, nil, nil
 	}
@@ -0,0 +1,58 @@
+package configpanel
+
+import (
+	"github.com/Jisin0/autofilterbot/pkg/panel"
+)
+
+// GroupPanelPath is the root path of the config panel of a group.
+// Fields opened from the group panel update the settings of the group in which the panel was opened instead of the bot config.
+const GroupPanelPath = "gconfig"
+
+// groupId returns the id of the group whose settings are being configured or 0 if the bot config is being configured.
+func groupId(ctx *panel.Context) int64 {
+	if len(ctx.CallbackData.Path) == 0 || ctx.CallbackData.Path[0] != GroupPanelPath {
+		return 0
+	}
+
+	return ctx.CallbackQuery.Message.GetChat().Id
+}
+
+// updateField sets the value of a field in the bot config or group settings.
+func updateField(app AppPreview, ctx *panel.Context, fieldName string, value interface{}) error {
+	if id := groupId(ctx); id != 0 {
+		return app.GetDB().UpdateGroupConfig(id, fieldName, value)
+	}
+
+	return app.GetDB().UpdateConfig(ctx.Bot.Id, fieldName, value)
+}
+
+// resetField removes a field from the bot config or group settings.
+func resetField(app AppPreview, ctx *panel.Context, fieldName string) error {
+	if id := groupId(ctx); id != 0 {
+		return app.GetDB().ResetGroupConfig(id, fieldName)
+	}
+
+	return app.GetDB().ResetConfig(ctx.Bot.Id, fieldName)
+}
+
+// fieldValue returns the current value of a field, fields not set for a group are not found.
+func fieldValue(app AppPreview, ctx *panel.Context, fieldName string) (any, bool) {
+	if id := groupId(ctx); id != 0 {
+		v, ok := app.GetGroupConfig(id).ToMap()[fieldName]
+		return v, ok
+	}
+
+	v, ok := app.GetConfig().ToMap()[fieldName]
+
+	return v, ok
+}
+
+// refreshConfig refreshes the bot config or group settings after a field was updated.
+func refreshConfig(app AppPreview, ctx *panel.Context) {
+	if id := groupId(ctx); id != 0 {
+		go app.RefreshGroupConfig(id)
+		return
+	}
+
+	go app.RefreshConfig()
+}
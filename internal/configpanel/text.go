@@ -0,0 +1,85 @@
+package configpanel
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/Jisin0/autofilterbot/pkg/conversation"
+	"github.com/Jisin0/autofilterbot/pkg/panel"
+	"github.com/PaulSonOfLars/gotgbot/v2"
+	"github.com/pkg/errors"
+)
+
+// TextFieldOpts wraps optional values to TextField().
+type TextFieldOpts struct {
+	// Description for the field.
+	Description string
+}
+
+// TextField is a helper for configuring html formatted text fields like templates, the new value is asked from the user.
+func TextField(app AppPreview, fieldName string, opts TextFieldOpts) panel.CallbackFunc {
+	return func(ctx *panel.Context) (string, [][]gotgbot.InlineKeyboardButton, error) {
+		var (
+			op   string
+			data = ctx.CallbackData
+		)
+
+		if len(data.Args) != 0 {
+			op = data.Args[0]
+		}
+
+		var s string
+
+		switch op {
+		case OperationSet:
+			conv := conversation.NewConversatorFromUpdate(ctx.Bot, ctx.Update.Update)
+
+			m, err := conv.Ask(app.GetContext(), fmt.Sprintf("Please Send the New %s, Formatting is Kept. Send /cancel to Cancel:", ctx.Page.DisplayName), nil)
+			if err != nil {
+				return "", nil, errors.Wrap(err, "configpanel: text: send value request message failed")
+			}
+
+			if m.Text == "" || strings.HasPrefix(m.Text, "/cancel") {
+				return "Operation Cancelled!", nil, nil
+			}
+
+			err = updateField(app, ctx, fieldName, m.OriginalHTML())
+			if err != nil {
+				return "", nil, err
+			}
+
+			s = fmt.Sprintf("<i><b>✅ %s has been Updated !</b></i>", ctx.Page.DisplayName)
+		case OperationReset:
+			err := resetField(app, ctx, fieldName)
+			if err != nil {
+				return "", nil, err
+			}
+
+			s = fmt.Sprintf("<i><b>✅ %s has been Reset !</b></i>", ctx.Page.DisplayName)
+		default:
+			var s strings.Builder
+
+			if opts.Description != "" {
+				s.WriteString(fmt.Sprintf("ℹ️ %s\n\n", opts.Description))
+			}
+
+			if v, ok := fieldValue(app, ctx, fieldName); ok {
+				s.WriteString(fmt.Sprintf("<b>⭕ Current Value:</b>\n<pre>%s</pre>\n\n", html.EscapeString(fmt.Sprint(v))))
+			}
+
+			s.WriteString(fmt.Sprintf("<i>Use The Buttons Below to Change %s or Reset it to The Default Value</i>", ctx.Page.DisplayName))
+
+			return s.String(),
+				[][]gotgbot.InlineKeyboardButton{{
+					{Text: "⏪ Reset", CallbackData: data.RemoveArgs().AddArg(OperationReset).ToString()},
+					{Text: "✏️ Set", CallbackData: data.RemoveArgs().AddArg(OperationSet).ToString()},
+				}},
+				nil
+		}
+
+		refreshConfig(app, ctx)
+
+		return s, nil, nil
+	}
+}
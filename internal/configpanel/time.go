@@ -44,14 +44,14 @@ func TimeField(app AppPreview, fieldName string, possibleValues []int) panel.Cal
 				return "", nil, fmt.Errorf("unknown value %d received for field %s", val, fieldName)
 			}
 
-			err = app.GetDB().UpdateConfig(ctx.Bot.Id, fieldName, val)
+			err = updateField(app, ctx, fieldName, val)
 			if err != nil {
 				return "", nil, err
 			}
 
 			s = fmt.Sprintf("<i><b>✅ %s has been set to %d minutes !</b></i>", ctx.Page.DisplayName, val)
 		case OperationReset:
-			err := app.GetDB().ResetConfig(ctx.Bot.Id, fieldName)
+			err := resetField(app, ctx, fieldName)
 			if err != nil {
 				return "", nil, err
 			}
@@ -62,7 +62,7 @@ func TimeField(app AppPreview, fieldName string, possibleValues []int) panel.Cal
 
 			s.WriteString(fmt.Sprintf("<i><b>🧮 Select One of the Values Below to Update %s to Given Number in Minutes\n\n", ctx.Page.DisplayName))
 
-			if v, ok := app.GetConfig().ToMap()[fieldName]; ok {
+			if v, ok := fieldValue(app, ctx, fieldName); ok {
 				if i, ok := v.(int); ok && i != 0 {
 					s.WriteString(fmt.Sprintf("⭕ Current Value: %dMins\n\n", i))
 				}
@@ -96,7 +96,7 @@ func TimeField(app AppPreview, fieldName string, possibleValues []int) panel.Cal
 			return s.String(), keyboard, nil
 		}
 
-		go app.RefreshConfig()
+		refreshConfig(app, ctx)
 
 		return s, nil, nil
 	}
@@ -64,7 +64,7 @@ func All(bot *gotgbot.Bot, ctx *ext.Context) error {
 		return nil
 	}
 
-	ok, err = fsub.CheckFsub(_app, bot, ctx)
+	ok, err = fsub.CheckChannels(_app, bot, ctx, _app.ChatConfig(c.Message.GetChat().Id).GetFsubChannels())
 	if err != nil {
 		if functions.IsChatNotFoundErr(err) { // user has not started bot or blocked
 			// redirect to dm for a retry msg
@@ -3,17 +3,29 @@ package core
 import (
 	"context"
 	"fmt"
+	"html"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/Jisin0/autofilterbot/internal/app"
 	"github.com/Jisin0/autofilterbot/internal/cache"
+	"github.com/Jisin0/autofilterbot/internal/config"
 	"github.com/Jisin0/autofilterbot/internal/configpanel"
+	"github.com/Jisin0/autofilterbot/internal/database"
+	"github.com/Jisin0/autofilterbot/internal/database/memory"
 	"github.com/Jisin0/autofilterbot/internal/database/mongo"
+	"github.com/Jisin0/autofilterbot/internal/database/sqlite"
 	"github.com/Jisin0/autofilterbot/internal/index"
+	"github.com/Jisin0/autofilterbot/internal/migrate"
+	"github.com/Jisin0/autofilterbot/internal/model"
 	"github.com/Jisin0/autofilterbot/pkg/autodelete"
+	"github.com/Jisin0/autofilterbot/pkg/deeplink"
 	"github.com/Jisin0/autofilterbot/pkg/env"
+	"github.com/Jisin0/autofilterbot/pkg/fuzzy"
 	"github.com/Jisin0/autofilterbot/pkg/log"
 	"github.com/PaulSonOfLars/gotgbot/v2"
 	"github.com/PaulSonOfLars/gotgbot/v2/ext"
@@ -29,6 +41,17 @@ type Core struct {
 	Ctx context.Context
 
 	additionalURLsCount int
+
+	// Links signs and verifies the start data of deep links.
+	Links *deeplink.Signer
+
+	groupConfigsMu sync.RWMutex
+	// groupConfigs caches the settings of groups by their id.
+	groupConfigs map[int64]*config.GroupConfig
+
+	groupsMu sync.RWMutex
+	// groups caches the details of groups by their id.
+	groups map[int64]*model.Group
 }
 
 // extendedHandler returns a handlers.Response that calls
@@ -36,6 +59,7 @@ type Core struct {
 // RunAppOptions wraps command-line arguments for app startup.
 type RunAppOptions struct {
 	MongodbURI         string
+	DatabaseURL        string
 	LogLevel           string
 	BotToken           string
 	DisableConsoleLogs bool
@@ -72,34 +96,7 @@ func Run(opts RunAppOptions) {
 
 	ctx, cancel := context.WithCancel(context.Background()) // all background jobs (tickers) must use this ctx
 
-	mongodbUri := opts.MongodbURI
-	if s := os.Getenv("MONGODB_URI"); s != "" {
-		mongodbUri = s
-	}
-
-	if mongodbUri == "" {
-		logger.Fatal("MONGODB_URI not provided. Set it as an environment variable or provide as a command line argument.")
-	}
-
-	databaseName := os.Getenv("DATABASE_NAME")
-	collectionName := os.Getenv("COLLECTION_NAME")
-
-	var additionalUri []string
-
-	for i := 1; i <= 5; i++ { // attempts to fetch MONGODB_URI1 to MONGODB_URI5. //TODO: remove hardcoded limit after testing
-		if s := os.Getenv(fmt.Sprintf("MONGODB_URI%d", i)); s != "" {
-			additionalUri = append(additionalUri, s)
-		}
-	}
-
-	mongoOpts := mongo.NewClientOpts{
-		DatabaseName:        databaseName,
-		FilesCollectionName: collectionName,
-		AdditionalURLs:      additionalUri,
-	}
-
-	db, err := mongo.NewClient(ctx, mongodbUri, logger, mongoOpts)
-
+	db, additionalURLsCount, err := openDatabase(ctx, opts, logger)
 	if err != nil {
 		logger.Fatal("database setup failed", zap.Error(err))
 	}
@@ -109,8 +106,8 @@ func Run(opts RunAppOptions) {
 		logger.Error("failed to load configs from db", zap.Error(err))
 	}
 
-	if appConfig.FileCollectionIndex != 0 {
-		err = db.UpdateStorageCollection(appConfig.FileCollectionIndex)
+	if m, ok := db.(database.MultiStorage); ok && appConfig.FileCollectionIndex != 0 {
+		err = m.UpdateStorageCollection(appConfig.FileCollectionIndex)
 		if err != nil {
 			logger.Warn("setting custom storage collection failed, using default database", zap.Error(err))
 		}
@@ -134,12 +131,22 @@ func Run(opts RunAppOptions) {
 			Cache:        cache.NewCache(),
 			Admins:       env.Int64s("ADMINS"),
 			IndexManager: index.NewManager(),
+			Vocabulary:   fuzzy.NewVocabulary(),
+
+			MigrationManager: migrate.NewManager(),
 		},
 		Ctx: ctx,
 	}
 
-	_app.additionalURLsCount = len(additionalUri)
+	_app.additionalURLsCount = additionalURLsCount
+
+	_app.Links, err = newLinkSigner(db, appConfig)
+	if err != nil {
+		logger.Fatal("link signer setup failed", zap.Error(err))
+	}
+
 	_app.ConfigPanel = configpanel.CreatePanel(_app)
+	_app.GroupPanel = configpanel.CreateGroupPanel(_app)
 
 	dispatcher := SetupDispatcher(logger)
 	updater := ext.NewUpdater(dispatcher, &ext.UpdaterOpts{
@@ -151,7 +158,7 @@ func Run(opts RunAppOptions) {
 	err = updater.StartPolling(bot, &ext.PollingOpts{
 		DropPendingUpdates: true,
 		GetUpdatesOpts: &gotgbot.GetUpdatesOpts{
-			AllowedUpdates: []string{"message", "channel_post", "inline_query", "chosen_inline_result", "callback_query", "chat_join_request"},
+			AllowedUpdates: []string{"message", "channel_post", "inline_query", "chosen_inline_result", "callback_query", "chat_join_request", "my_chat_member"},
 		},
 	})
 	if err != nil {
@@ -164,9 +171,17 @@ func Run(opts RunAppOptions) {
 	logger.Info(fmt.Sprintf("@%s started successfully !", bot.Username))
 
 	go _app.RestartActiveIndexOperations(ctx)
+	go _app.RestartActiveMigrations(ctx)
+	go _app.RunVocabularyUpdater(ctx)
+	go pmSearchLimiter.Run(ctx, time.Hour)
+	go batchPasswordLimiter.Run(ctx, time.Hour)
+
+	if h, ok := _app.DB.(database.HealthMonitor); ok {
+		h.RunHealthChecker(ctx, logger, _app.AlertStorageHealth)
+	}
 
-	if appConfig.FileCollectionUpdater {
-		_app.DB.RunCollectionUpdater(ctx, logger)
+	if m, ok := _app.DB.(database.MultiStorage); ok && appConfig.FileCollectionUpdater {
+		m.RunCollectionUpdater(ctx, logger)
 	}
 
 	c := make(chan os.Signal, 1)
@@ -181,6 +196,77 @@ func Run(opts RunAppOptions) {
 	_app.DB.Shutdown()
 }
 
+// OpenDatabase loads variables from the .env file and connects to the database, it is used by cli commands that don't start the bot.
+func OpenDatabase(ctx context.Context, opts RunAppOptions, logger *zap.Logger) (database.Database, error) {
+	godotenv.Load(".env")
+
+	db, _, err := openDatabase(ctx, opts, logger)
+
+	return db, err
+}
+
+// openDatabase connects to the database selected by the scheme of DATABASE_URL, mongodb is used by default.
+// Returns the database client and the number of additional mongodb urls provided.
+func openDatabase(ctx context.Context, opts RunAppOptions, logger *zap.Logger) (database.Database, int, error) {
+	databaseUrl := opts.DatabaseURL
+	if s := os.Getenv("DATABASE_URL"); s != "" {
+		databaseUrl = s
+	}
+
+	if strings.HasPrefix(databaseUrl, sqlite.URLScheme) {
+		db, err := sqlite.NewClient(databaseUrl, logger)
+		return db, 0, err
+	}
+
+	if strings.HasPrefix(databaseUrl, memory.URLScheme) {
+		logger.Warn("using in-memory database, all data will be lost when the bot stops")
+		return memory.NewClient(), 0, nil
+	}
+
+	mongodbUri := opts.MongodbURI
+	if s := os.Getenv("MONGODB_URI"); s != "" {
+		mongodbUri = s
+	}
+
+	if strings.HasPrefix(databaseUrl, "mongodb") { // mongodb:// and mongodb+srv://
+		mongodbUri = databaseUrl
+	}
+
+	if mongodbUri == "" {
+		return nil, 0, fmt.Errorf("MONGODB_URI or DATABASE_URL not provided. Set it as an environment variable or provide as a command line argument")
+	}
+
+	var additionalUri []string
+
+	for i := 1; i <= 5; i++ { // attempts to fetch MONGODB_URI1 to MONGODB_URI5. //TODO: remove hardcoded limit after testing
+		if s := os.Getenv(fmt.Sprintf("MONGODB_URI%d", i)); s != "" {
+			additionalUri = append(additionalUri, s)
+		}
+	}
+
+	mongoOpts := mongo.NewClientOpts{
+		DatabaseName:        os.Getenv("DATABASE_NAME"),
+		FilesCollectionName: os.Getenv("COLLECTION_NAME"),
+		AdditionalURLs:      additionalUri,
+	}
+
+	if s := os.Getenv("MONGODB_TIMEOUT"); s != "" {
+		seconds, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, 0, fmt.Errorf("invalid MONGODB_TIMEOUT %q: %w", s, err)
+		}
+
+		mongoOpts.QueryTimeout = time.Duration(seconds) * time.Second
+	}
+
+	db, err := mongo.NewClient(ctx, mongodbUri, logger, mongoOpts)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return db, len(additionalUri), nil
+}
+
 // AuthAdmin reports whether the user who sent the message is an admin or otherwise sends a warn message.
 func (core *Core) AuthAdmin(ctx *ext.Context) bool {
 	switch {
@@ -232,13 +318,37 @@ func (c *Core) RestartActiveIndexOperations(ctx context.Context) {
 	}
 }
 
+// AlertStorageHealth notifies all admins when a file storage goes down or recovers.
+func (c *Core) AlertStorageHealth(index int, h database.StorageHealth) {
+	var text string
+
+	if h.Healthy {
+		text = fmt.Sprintf("✅ <b>Database %d is back online.</b>", index)
+	} else {
+		c.Log.Warn("core: file storage is unhealthy", zap.Int("index", index), zap.String("error", h.Error))
+		text = fmt.Sprintf("⚠️ <b>Database %d is unreachable!</b>\n\n<code>%s</code>\n\nNew files will be saved to the next healthy database until it recovers.", index, html.EscapeString(h.Error))
+	}
+
+	for _, id := range c.Admins {
+		if _, err := c.Bot.SendMessage(id, text, &gotgbot.SendMessageOpts{ParseMode: gotgbot.ParseModeHTML}); err != nil {
+			c.Log.Debug("core: failed to send storage health alert", zap.Error(err), zap.Int64("admin", id))
+		}
+	}
+}
+
 // GetAdditionalCollectionCount returns the number of additional db urls provided.
 func (c *Core) GetAdditionalCollectionCount() int {
 	return c.additionalURLsCount
 }
 
 func (c *Core) SetCollectionIndex(index int) {
-	err := c.DB.UpdateStorageCollection(index)
+	m, ok := c.DB.(database.MultiStorage)
+	if !ok {
+		c.Log.Warn("core: database does not support multiple collections", zap.String("database", c.DB.GetName()))
+		return
+	}
+
+	err := m.UpdateStorageCollection(index)
 	if err != nil {
 		c.Log.Warn("core: failed to update collection index", zap.Error(err))
 	}
@@ -4,13 +4,18 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/Jisin0/autofilterbot/internal/autofilter"
 	"github.com/Jisin0/autofilterbot/internal/button"
+	"github.com/Jisin0/autofilterbot/internal/config"
+	"github.com/Jisin0/autofilterbot/internal/database"
 	"github.com/Jisin0/autofilterbot/internal/format"
 	"github.com/Jisin0/autofilterbot/internal/functions"
+	"github.com/Jisin0/autofilterbot/internal/model"
 	"github.com/Jisin0/autofilterbot/pkg/callbackdata"
+	"github.com/Jisin0/autofilterbot/pkg/conversation"
 	"github.com/PaulSonOfLars/gotgbot/v2"
 	"github.com/PaulSonOfLars/gotgbot/v2/ext"
 	"go.uber.org/zap"
@@ -22,20 +27,41 @@ func Autofilter(bot *gotgbot.Bot, ctx *ext.Context) error {
 		_app.Log.Warn("autofilter error", zap.Error(err))
 	}
 
-	if msg != nil && _app.Config.GetAutodeleteTime() != 0 {
-		err := _app.AutoDelete.SaveMessage(msg, time.Minute*time.Duration(_app.Config.AutodeleteTime))
+	autodeleteResult(msg)
+
+	return nil
+}
+
+// autodeleteResult schedules the deletion of a result message if autodelete is enabled.
+func autodeleteResult(msg *gotgbot.Message) {
+	if msg == nil {
+		return
+	}
+
+	if t := _app.ChatConfig(msg.Chat.Id).GetAutodeleteTime(); t != 0 {
+		err := _app.AutoDelete.SaveMessage(msg, time.Minute*time.Duration(t))
 		if err != nil {
 			_app.Log.Warn("autofilter: save autodelete failed", zap.Error(err))
 		}
 	}
+}
 
-	return nil
+// maxSuggestions is the maximum number of corrected queries suggested when no results are found.
+const maxSuggestions = 3
+
+// maxCallbackDataLength is the maximum length of callback data allowed by telegram.
+const maxCallbackDataLength = 64
+
+// suggestionData returns the callback data of a button that searches for a suggested query.
+func suggestionData(userId int64, query string) string {
+	return fmt.Sprintf("af|%d_%s", userId, query)
 }
 
 // autofilter runs the autofilter task and returns the sent message.
 func _autofilter(bot *gotgbot.Bot, ctx *ext.Context) (*gotgbot.Message, error) {
 	var (
 		query        string
+		filter       database.FileFilter
 		inputMessage gotgbot.MaybeInaccessibleMessage
 		fromUser     *gotgbot.User
 	)
@@ -44,7 +70,7 @@ func _autofilter(bot *gotgbot.Bot, ctx *ext.Context) (*gotgbot.Message, error) {
 	case ctx.CallbackQuery != nil:
 		c := ctx.CallbackQuery
 
-		// callback data structure: af:<query>_<user_id>
+		// callback data structure: af|<user_id>_<query>, the query may contain the delimiter
 		callbackData := callbackdata.FromString(c.Data)
 		if len(callbackData.Args) < 2 {
 			_, err := c.Answer(bot, &gotgbot.AnswerCallbackQueryOpts{
@@ -57,7 +83,7 @@ func _autofilter(bot *gotgbot.Bot, ctx *ext.Context) (*gotgbot.Message, error) {
 			return nil, err
 		}
 
-		userId, err := strconv.ParseInt(callbackData.Args[1], 10, 64)
+		userId, err := strconv.ParseInt(callbackData.Args[0], 10, 64)
 		if err != nil {
 			_, err := c.Answer(bot, &gotgbot.AnswerCallbackQueryOpts{
 				Text:      "Sorry An Error occurred :{",
@@ -77,12 +103,19 @@ func _autofilter(bot *gotgbot.Bot, ctx *ext.Context) (*gotgbot.Message, error) {
 			return nil, err
 		}
 
+		c.Answer(bot, nil)
+
 		inputMessage = c.Message
-		if m, ok := c.Message.(*gotgbot.Message); ok {
+		if m, ok := c.Message.(*gotgbot.Message); ok && m.ReplyToMessage != nil {
 			inputMessage = m.ReplyToMessage
+
+			// the suggestion message is replaced by the new results
+			m.Delete(bot, nil)
 		}
 
-		query = callbackData.Args[0]
+		q := autofilter.ParseQuery(strings.Join(callbackData.Args[1:], string(callbackdata.ArgDelimiter)))
+
+		query, filter = q.Text, q.Filter
 		fromUser = &c.From
 	case ctx.Message != nil:
 		m := ctx.Message
@@ -92,22 +125,43 @@ func _autofilter(bot *gotgbot.Bot, ctx *ext.Context) (*gotgbot.Message, error) {
 			return nil, nil
 		}
 
+		// the message is an answer to a question asked by the bot
+		if conversation.ActiveListeners.HasMatch(m) {
+			return nil, nil
+		}
+
+		if m.Chat.Type == gotgbot.ChatTypeSupergroup && (!_app.GetGroupConfig(m.Chat.Id).GetAutofilter() || !groupAllowed(bot, &m.Chat)) {
+			return nil, nil
+		}
+
 		if autofilter.IsBadQuery(text, m.Entities) {
 			_app.Log.Debug("autofilter: bad query", zap.String("text", text), zap.Any("entities", m.Entities))
 			return nil, nil
 		}
 
-		text = autofilter.Sanitize(text)
+		q := autofilter.ParseQuery(text)
+		if q.Text == "" {
+			_app.Log.Debug("autofilter: query has no search terms", zap.String("text", text))
+			return nil, nil
+		}
 
 		inputMessage = m
-		query = text
+		query, filter = q.Text, q.Filter
 		fromUser = m.From
 	default:
 		_app.Log.Warn("autofilter: unsupported update type", zap.Int64("update_id", ctx.UpdateId))
 		return nil, nil
 	}
 
-	cursor, err := _app.DB.SearchFiles(query)
+	return sendResults(bot, ctx, inputMessage, fromUser, query, filter)
+}
+
+// sendResults searches for files matching query and filter and replies to inputMessage with the results or suggestions.
+func sendResults(bot *gotgbot.Bot, ctx *ext.Context, inputMessage gotgbot.MaybeInaccessibleMessage, fromUser *gotgbot.User, query string, filter database.FileFilter) (*gotgbot.Message, error) {
+	// settings of groups override the bot config
+	cfg := _app.ChatConfig(inputMessage.GetChat().Id)
+
+	cursor, err := _app.DB.SearchFiles(query, database.SearchFilesOpts{Mode: cfg.GetSearchMode(), Filter: &filter, Limit: autofilter.PrefetchLimit(cfg), Channels: cfg.GetSearchChatIds()})
 	if err != nil {
 		_app.Log.Warn("autofilter: search files failed", zap.Error(err))
 		return bot.SendMessage(inputMessage.GetChat().Id, "<i>I'm Having Some Database Issues Right Now 😓\nPlease Try Again Later!</i>", &gotgbot.SendMessageOpts{
@@ -118,7 +172,9 @@ func _autofilter(bot *gotgbot.Bot, ctx *ext.Context) (*gotgbot.Message, error) {
 		})
 	}
 
-	files, err := autofilter.FilesFromCursor(context.Background(), cursor, _app.Config)
+	defer cursor.Close(context.Background())
+
+	files, err := autofilter.FilesFromCursor(context.Background(), cursor, cfg)
 	if err != nil {
 		_app.Log.Warn("autofilter: files from cursor failed", zap.Error(err))
 		return bot.SendMessage(inputMessage.GetChat().Id, "<i>Processing Results Failed 🤖</i>", &gotgbot.SendMessageOpts{
@@ -129,35 +185,98 @@ func _autofilter(bot *gotgbot.Bot, ctx *ext.Context) (*gotgbot.Message, error) {
 		})
 	}
 
+	var suggestions []string
+
+	if len(files) == 0 {
+		suggestions = autofilter.Suggestions(_app.Vocabulary, query, maxSuggestions)
+
+		// misspelled queries are retried with the best suggestion before giving up
+		if len(suggestions) != 0 {
+			f, err := searchFiles(cfg, suggestions[0], &filter)
+			if err != nil {
+				_app.Log.Warn("autofilter: search suggestion failed", zap.Error(err), zap.String("suggestion", suggestions[0]))
+			}
+
+			if len(f) != 0 {
+				files, query = f, suggestions[0]
+			}
+
+			suggestions = suggestions[1:]
+		}
+	}
+
 	if len(files) == 0 {
+		keyboard := make([][]gotgbot.InlineKeyboardButton, 0, len(suggestions)+2)
+
+		for _, s := range suggestions {
+			// filters are kept in the suggestion if they fit in the callback data
+			data := suggestionData(fromUser.Id, autofilter.Query{Text: s, Filter: filter}.String())
+			if len(data) > maxCallbackDataLength {
+				data = suggestionData(fromUser.Id, s)
+			}
+
+			// telegram rejects the whole keyboard if a button's data is too long
+			if len(data) > maxCallbackDataLength {
+				continue
+			}
+
+			keyboard = append(keyboard, []gotgbot.InlineKeyboardButton{{Text: "🔎 " + s, CallbackData: data}})
+		}
+
+		hasSuggestions := len(keyboard) != 0
+
+		keyboard = append(keyboard,
+			[]gotgbot.InlineKeyboardButton{{Text: "Sᴇᴀʀᴄʜ Oɴ Gᴏᴏɢʟᴇ 🔎", Url: fmt.Sprintf("https://google.com/?q=%s", query)}},
+			[]gotgbot.InlineKeyboardButton{{Text: "Cᴏᴘʏ", CopyText: &gotgbot.CopyTextButton{Text: query}}, button.Close(fromUser.Id)},
+		)
+
+		text := cfg.GetNoResultText()
+		if hasSuggestions {
+			text += "\n\n<b><i>🤔 Dɪᴅ ʏᴏᴜ ᴍᴇᴀɴ ?</i></b>"
+		}
+
 		vals := _app.BasicMessageValues(ctx, map[string]any{"query": query})
-		return bot.SendMessage(inputMessage.GetChat().Id, format.KeyValueFormat(_app.Config.GetNoResultText(), vals), &gotgbot.SendMessageOpts{
+
+		return bot.SendMessage(inputMessage.GetChat().Id, format.KeyValueFormat(text, vals), &gotgbot.SendMessageOpts{
 			ReplyParameters: &gotgbot.ReplyParameters{
 				MessageId: inputMessage.GetMessageId(),
 			},
-			ReplyMarkup: gotgbot.InlineKeyboardMarkup{InlineKeyboard: [][]gotgbot.InlineKeyboardButton{
-				{{Text: "Sᴇᴀʀᴄʜ Oɴ Gᴏᴏɢʟᴇ 🔎", Url: fmt.Sprintf("https://google.com/?q=%s", query)}},
-				{{Text: "Cᴏᴘʏ", CopyText: &gotgbot.CopyTextButton{Text: query}}, button.Close(fromUser.Id)},
-			}},
-			ParseMode: gotgbot.ParseModeHTML,
+			ReplyMarkup: gotgbot.InlineKeyboardMarkup{InlineKeyboard: keyboard},
+			ParseMode:   gotgbot.ParseModeHTML,
 		})
 	}
 
 	var warn string
-	if _app.Config.GetAutodeleteTime() != 0 {
-		warn = fmt.Sprintf("<blockquote><b>⚠️ 𝖳𝗁𝗂𝗌 𝖬𝖾𝗌𝗌𝖺𝗀𝖾 𝖶𝗂𝗅𝗅 𝖡𝖾 𝖠𝗎𝗍𝗈𝗆𝖺𝗍𝗂𝖼𝖺𝗅𝗅𝗒 𝖣𝖾𝗅𝖾𝗍𝖾𝖽 𝖨𝗇 %d 𝖬𝗂𝗇𝗎𝗍𝖾𝗌</b></blockquote>", _app.Config.AutodeleteTime)
+	if cfg.GetAutodeleteTime() != 0 {
+		warn = fmt.Sprintf("<blockquote><b>⚠️ 𝖳𝗁𝗂𝗌 𝖬𝖾𝗌𝗌𝖺𝗀𝖾 𝖶𝗂𝗅𝗅 𝖡𝖾 𝖠𝗎𝗍𝗈𝗆𝖺𝗍𝗂𝖼𝖺𝗅𝗅𝗒 𝖣𝖾𝗅𝖾𝗍𝖾𝖽 𝖨𝗇 %d 𝖬𝗂𝗇𝗎𝗍𝖾𝗌</b></blockquote>", cfg.GetAutodeleteTime())
 	}
 
-	var (
-		buttons  = make([][]gotgbot.InlineKeyboardButton, 0, len(files[0])+2)
-		uniqueId = functions.RandString(15)
-	)
+	var fetched int
+	for _, p := range files {
+		fetched += len(p)
+	}
+
+	// pages are rebuilt so that a group is never split between pages
+	if cfg.GetGroupResults() {
+		files = autofilter.GroupPages(files, cfg.GetMaxPerPage())
+	}
 
-	buttons = append(buttons, headerRow(uniqueId, 0))
-	buttons = append(buttons, files[0].Process(inputMessage.GetChat().Id, bot.Username, _app.Config)...)
-	buttons = append(buttons, footerRow(uniqueId, 0, len(files)))
+	result := &autofilter.SearchResult{
+		UniqueId: functions.RandString(15),
+		Query:    query,
+		FromUser: fromUser.Id,
+		ChatID:   ctx.EffectiveChat.Id,
+		Files:    files,
+		Mode:     cfg.GetSearchMode(),
+		Filter:   &filter,
+		Channels: cfg.GetSearchChatIds(),
+		Fetched:  fetched,
+		Complete: fetched < autofilter.PrefetchLimit(cfg),
+	}
+
+	buttons := resultButtons(result, 0, inputMessage.GetChat().Id, bot.Username)
 
-	text := format.KeyValueFormat(_app.Config.GetResultTemplate(), _app.BasicMessageValues(ctx, map[string]any{"query": query, "warn": warn}))
+	text := format.KeyValueFormat(cfg.GetResultTemplate(), _app.BasicMessageValues(ctx, map[string]any{"query": query, "warn": warn}))
 
 	msg, err := bot.SendMessage(inputMessage.GetChat().Id, text, &gotgbot.SendMessageOpts{
 		ReplyParameters: &gotgbot.ReplyParameters{
@@ -170,13 +289,7 @@ func _autofilter(bot *gotgbot.Bot, ctx *ext.Context) (*gotgbot.Message, error) {
 		_app.Log.Warn("autofilter: send result failed", zap.Error(err))
 	}
 
-	err = _app.Cache.Autofilter.Save(&autofilter.SearchResult{
-		UniqueId: uniqueId,
-		Query:    query,
-		FromUser: fromUser.Id,
-		ChatID:   ctx.EffectiveChat.Id,
-		Files:    files,
-	})
+	err = _app.Cache.Autofilter.Save(result)
 	if err != nil {
 		_app.Log.Warn("autfilter: save cache failed", zap.Error(err), zap.String("query", query))
 	}
@@ -184,8 +297,88 @@ func _autofilter(bot *gotgbot.Bot, ctx *ext.Context) (*gotgbot.Message, error) {
 	return msg, nil
 }
 
-func headerRow(uniqueId string, pageIndex int) []gotgbot.InlineKeyboardButton {
-	return []gotgbot.InlineKeyboardButton{allButton(uniqueId, pageIndex), selectButton(uniqueId, pageIndex)}
+// searchFiles searches for files matching query and filter and splits the first few into pages.
+func searchFiles(cfg *config.Config, query string, filter *database.FileFilter) ([]autofilter.Files, error) {
+	cursor, err := _app.DB.SearchFiles(query, database.SearchFilesOpts{Mode: cfg.GetSearchMode(), Filter: filter, Limit: autofilter.PrefetchLimit(cfg), Channels: cfg.GetSearchChatIds()})
+	if err != nil {
+		return nil, err
+	}
+
+	defer cursor.Close(context.Background())
+
+	return autofilter.FilesFromCursor(context.Background(), cursor, cfg)
+}
+
+// loadPages fetches the next pages of the result from the database until pageIndex is loaded or all files are fetched.
+// It reports whether any page was fetched so the result can be saved.
+func loadPages(r *autofilter.SearchResult, pageIndex int) (bool, error) {
+	// the settings of the chat are used so later pages match the first
+	cfg := _app.ChatConfig(r.ChatID)
+
+	var (
+		ctx     = context.Background()
+		perPage = cfg.GetMaxPerPage()
+		loaded  bool
+	)
+
+	for pageIndex >= len(r.Files) && r.HasMore() {
+		cursor, err := _app.DB.SearchFiles(r.Query, r.SearchOpts(perPage))
+		if err != nil {
+			return loaded, err
+		}
+
+		files := make(autofilter.Files, 0, perPage)
+
+		for cursor.Next(ctx) {
+			var f model.File
+
+			if err := cursor.Decode(&f); err != nil {
+				cursor.Close(ctx)
+				return loaded, err
+			}
+
+			files = append(files, autofilter.File{File: f})
+		}
+
+		cursor.Close(ctx)
+
+		r.AddPage(files, perPage, cfg.GetGroupResults())
+
+		loaded = true
+	}
+
+	return loaded, nil
+}
+
+func headerRow(uniqueId string, pageIndex int, view autofilter.View) []gotgbot.InlineKeyboardButton {
+	filterText, sortText := "ғɪʟᴛᴇʀ", "sᴏʀᴛ"
+	if view.FileType != "" || view.Language != "" || view.Resolution != "" {
+		filterText = "✅ " + filterText
+	}
+
+	if view.Sort != autofilter.SortDefault {
+		sortText = "✅ " + sortText
+	}
+
+	return []gotgbot.InlineKeyboardButton{
+		allButton(uniqueId, pageIndex),
+		selectButton(uniqueId, pageIndex),
+		{Text: filterText, CallbackData: fmt.Sprintf("view|%s_%d_%s", uniqueId, pageIndex, viewMenuFilter)},
+		{Text: sortText, CallbackData: fmt.Sprintf("view|%s_%d_%s", uniqueId, pageIndex, viewMenuSort)},
+	}
+}
+
+// resultButtons returns the keyboard of a page of the search result.
+func resultButtons(r *autofilter.SearchResult, pageIndex int, chatId int64, botUsername string) [][]gotgbot.InlineKeyboardButton {
+	pageFiles := r.Files[pageIndex]
+
+	buttons := make([][]gotgbot.InlineKeyboardButton, 0, len(pageFiles)+2)
+
+	buttons = append(buttons, headerRow(r.UniqueId, pageIndex, r.View))
+	buttons = append(buttons, pageFiles.Process(chatId, botUsername, r.UniqueId, pageIndex, resultOptions(chatId))...)
+	buttons = append(buttons, footerRow(r.UniqueId, pageIndex, len(r.Files), r.HasMore()))
+
+	return buttons
 }
 
 func allButton(uniqueId string, pageIndex int) gotgbot.InlineKeyboardButton {
@@ -196,21 +389,31 @@ func selectButton(uniqueId string, pageIndex int) gotgbot.InlineKeyboardButton {
 	return gotgbot.InlineKeyboardButton{Text: "sᴇʟᴇᴄᴛ", CallbackData: fmt.Sprintf("sel|%s_%d", uniqueId, pageIndex)}
 }
 
-func footerRow(uniqueId string, pageIndex, totalPages int) []gotgbot.InlineKeyboardButton {
+// footerRow returns the navigation buttons of a page, hasMore indicates that more pages can be fetched after totalPages.
+func footerRow(uniqueId string, pageIndex, totalPages int, hasMore bool) []gotgbot.InlineKeyboardButton {
 	btns := make([]gotgbot.InlineKeyboardButton, 0, 3)
 	if pageIndex != 0 {
 		btns = append(btns, backButton(uniqueId, pageIndex-1))
 	}
 
-	btns = append(btns, gotgbot.InlineKeyboardButton{Text: fmt.Sprintf("📑 𝗣𝗔𝗚𝗘 %d/%d", pageIndex+1, totalPages), CallbackData: "ignore"})
+	btns = append(btns, gotgbot.InlineKeyboardButton{Text: fmt.Sprintf("📑 𝗣𝗔𝗚𝗘 %d/%s", pageIndex+1, pageCount(totalPages, hasMore)), CallbackData: "ignore"})
 
-	if pageIndex+1 != totalPages {
+	if pageIndex+1 < totalPages || hasMore {
 		btns = append(btns, nextButton(uniqueId, pageIndex+1))
 	}
 
 	return btns
 }
 
+// pageCount returns the number of pages with a plus if more can be fetched.
+func pageCount(totalPages int, hasMore bool) string {
+	if hasMore {
+		return fmt.Sprintf("%d+", totalPages)
+	}
+
+	return strconv.Itoa(totalPages)
+}
+
 func backButton(uniqueId string, pageIndex int) gotgbot.InlineKeyboardButton {
 	return gotgbot.InlineKeyboardButton{Text: "« ʙᴀᴄᴋ", CallbackData: fmt.Sprintf("navg|%s_%d", uniqueId, pageIndex)}
 }
@@ -3,13 +3,15 @@
 package core
 
 import (
-	"encoding/base64"
+	"encoding/binary"
 	"errors"
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/Jisin0/autofilterbot/internal/functions"
+	"github.com/Jisin0/autofilterbot/internal/model"
 	"github.com/Jisin0/autofilterbot/pkg/conversation"
 	"github.com/PaulSonOfLars/gotgbot/v2"
 	"github.com/PaulSonOfLars/gotgbot/v2/ext"
@@ -17,12 +19,7 @@ import (
 )
 
 // SendBatch sends a message batch to the target chat.
-func SendBatch(bot *gotgbot.Bot, chatID int64, rawData string) error {
-	data, err := BatchURLDataFromString(rawData)
-	if err != nil {
-		return err
-	}
-
+func SendBatch(bot *gotgbot.Bot, chatID int64, data *BatchURLData) error {
 	for i := data.StartMessageId; i <= data.EndMessageId; i++ {
 		_, err := bot.CopyMessage(chatID, data.ChatId, i, nil)
 		if err != nil {
@@ -157,30 +154,11 @@ func NewBatch(bot *gotgbot.Bot, ctx *ext.Context) error {
 		return nil
 	}
 
-	data := &BatchURLData{
+	saveBatch(bot, m, &model.Batch{
 		ChatId:         channelId,
 		StartMessageId: startId,
 		EndMessageId:   endId,
-	}
-	url := fmt.Sprintf("https://t.me/%s?start=%s", bot.Username, data.Encode())
-
-	text := fmt.Sprintf(`
-<b>𝖬𝖾𝗌𝗌𝖺𝗀𝖾 𝖡𝖺𝗍𝖼𝗁 𝖧𝖺𝗌 𝖡𝖾𝖾𝗇 𝖢𝗋𝖾𝖺𝗍𝖾𝖽 𝖲𝗎𝖼𝖼𝖾𝗌𝗌𝖿𝗎𝗅𝗅𝗒 🎉</b>
-<b>𝖳𝗋𝗒 𝖭𝗈𝗐:</b> <a href='%s'>ᴄʟɪᴄᴋ ʜᴇʀᴇ</a>
-<b>𝖢𝗈𝗉𝗒:</b> <code>%s</code>
-`, url, url)
-	btn := [][]gotgbot.InlineKeyboardButton{
-		{{Text: "𝖳𝗋𝗒 𝖭𝗈𝗐", Url: url}},
-		{{Text: "𝖳𝖺𝗉 𝗍𝗈 𝖢𝗈𝗉𝗒", CopyText: &gotgbot.CopyTextButton{Text: url}}},
-	}
-
-	_, err := bot.SendMessage(m.Chat.Id, text, &gotgbot.SendMessageOpts{
-		ReplyMarkup: gotgbot.InlineKeyboardMarkup{InlineKeyboard: btn},
-		ParseMode:   gotgbot.ParseModeHTML,
-	})
-	if err != nil {
-		_app.Log.Warn("batch: send success msg failed", zap.Error(err))
-	}
+	}, "<b>𝖬𝖾𝗌𝗌𝖺𝗀𝖾 𝖡𝖺𝗍𝖼𝗁 𝖧𝖺𝗌 𝖡𝖾𝖾𝗇 𝖢𝗋𝖾𝖺𝗍𝖾𝖽 𝖲𝗎𝖼𝖼𝖾𝗌𝗌𝖿𝗎𝗅𝗅𝗒 🎉</b>")
 
 	return nil
 }
@@ -254,46 +232,102 @@ func GenLink(bot *gotgbot.Bot, ctx *ext.Context) error {
 		}
 	}
 
-	data := &BatchURLData{
+	saveBatch(bot, m, &model.Batch{
 		ChatId:         channelId,
 		StartMessageId: messageId,
 		EndMessageId:   messageId,
+	}, "<b>𝖬𝖾𝗌𝗌𝖺𝗀𝖾 𝖫𝗂𝗇𝗄 𝖧𝖺𝗌 𝖡𝖾𝖾𝗇 𝖢𝗋𝖾𝖺𝗍𝖾𝖽 𝖲𝗎𝖼𝖼𝖾𝗌𝗌𝖿𝗎𝗅𝗅𝗒 🎉</b>")
+
+	return nil
+}
+
+// saveBatch saves a new batch created by the sender of the message and replies with its link, title is the first line of the reply.
+func saveBatch(bot *gotgbot.Bot, m *gotgbot.Message, b *model.Batch, title string) {
+	id, err := newShortId()
+	if err != nil {
+		_app.Log.Warn("batch: generate id failed", zap.Error(err))
+		return
 	}
-	url := fmt.Sprintf("https://t.me/%s?start=%s", bot.Username, data.Encode())
+
+	b.ID = id
+	b.CreatedBy = m.From.Id
+	b.CreatedAt = time.Now().Unix()
+
+	err = _app.DB.SaveBatch(b)
+	if err != nil {
+		_app.Log.Warn("batch: save batch failed", zap.Error(err))
+		m.Reply(bot, "Failed to save batch: "+err.Error(), nil)
+
+		return
+	}
+
+	url := batchLink(bot, b.ID)
 
 	text := fmt.Sprintf(`
-<b>𝖬𝖾𝗌𝗌𝖺𝗀𝖾 𝖫𝗂𝗇𝗄 𝖧𝖺𝗌 𝖡𝖾𝖾𝗇 𝖢𝗋𝖾𝖺𝗍𝖾𝖽 𝖲𝗎𝖼𝖼𝖾𝗌𝗌𝖿𝗎𝗅𝗅𝗒 🎉</b>
+%s
 <b>𝖳𝗋𝗒 𝖭𝗈𝗐:</b> <a href='%s'>ᴄʟɪᴄᴋ ʜᴇʀᴇ</a>
 <b>𝖢𝗈𝗉𝗒:</b> <code>%s</code>
-`, url, url)
+
+<i>Use /batches to Set an Expiry or Password, Edit or Revoke the Link.</i>
+`, title, url, url)
 	btn := [][]gotgbot.InlineKeyboardButton{
 		{{Text: "𝖳𝗋𝗒 𝖭𝗈𝗐", Url: url}},
 		{{Text: "𝖳𝖺𝗉 𝗍𝗈 𝖢𝗈𝗉𝗒", CopyText: &gotgbot.CopyTextButton{Text: url}}},
 	}
 
-	_, err := bot.SendMessage(m.Chat.Id, text, &gotgbot.SendMessageOpts{
+	_, err = bot.SendMessage(m.Chat.Id, text, &gotgbot.SendMessageOpts{
 		ReplyMarkup: gotgbot.InlineKeyboardMarkup{InlineKeyboard: btn},
 		ParseMode:   gotgbot.ParseModeHTML,
 	})
 	if err != nil {
-		_app.Log.Warn("genlink: send success msg failed", zap.Error(err))
+		_app.Log.Warn("batch: send success msg failed", zap.Error(err))
 	}
-
-	return nil
 }
 
-// BatchURLData is the url data from the start command.
+// BatchURLData is the url data from old batch links that contain the channel and message range.
 type BatchURLData struct {
 	ChatId         int64
 	StartMessageId int64
 	EndMessageId   int64
 }
 
-func (d *BatchURLData) Encode() string {
-	return base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("b_%d_%d_%d", d.ChatId, d.StartMessageId, d.EndMessageId)))
+// Bytes encodes data compactly to be signed as start data, it is decoded using BatchURLDataFromBytes.
+func (d *BatchURLData) Bytes() []byte {
+	b := []byte{DataPrefixBatch}
+	b = binary.AppendVarint(b, d.ChatId)
+	b = binary.AppendVarint(b, d.StartMessageId)
+
+	return binary.AppendVarint(b, d.EndMessageId)
+}
+
+// BatchURLDataFromBytes decodes data encoded using BatchURLData.Bytes.
+func BatchURLDataFromBytes(b []byte) (*BatchURLData, error) {
+	if len(b) == 0 || b[0] != DataPrefixBatch {
+		return nil, errors.New("not batch data")
+	}
+
+	b = b[1:]
+
+	var ids [3]int64
+
+	for i := range ids {
+		v, n := binary.Varint(b)
+		if n <= 0 {
+			return nil, errors.New("invalid batch data")
+		}
+
+		ids[i] = v
+		b = b[n:]
+	}
+
+	return &BatchURLData{
+		ChatId:         ids[0],
+		StartMessageId: ids[1],
+		EndMessageId:   ids[2],
+	}, nil
 }
 
-// BatchURLDataFromString converts a string, usually the start data, to batch url data.
+// BatchURLDataFromString converts the decoded start data of an old unsigned link to batch url data.
 func BatchURLDataFromString(s string) (*BatchURLData, error) {
 	split := strings.Split(s, "_")
 	if len(split) < 4 {
@@ -0,0 +1,499 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Jisin0/autofilterbot/internal/database"
+	"github.com/Jisin0/autofilterbot/internal/fsub"
+	"github.com/Jisin0/autofilterbot/internal/functions"
+	"github.com/Jisin0/autofilterbot/internal/model"
+	"github.com/Jisin0/autofilterbot/pkg/callbackdata"
+	"github.com/Jisin0/autofilterbot/pkg/conversation"
+	"github.com/Jisin0/autofilterbot/pkg/deeplink"
+	"github.com/Jisin0/autofilterbot/pkg/ratelimit"
+	"github.com/PaulSonOfLars/gotgbot/v2"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext"
+	"go.uber.org/zap"
+)
+
+const (
+	// BatchDataPrefix is the prefix of start data that sends a saved batch, followed by the id of the batch.
+	BatchDataPrefix = "b_"
+
+	// batchesPerPage is the number of batches listed in a single page of /batches.
+	batchesPerPage = 10
+
+	batchActionList      = "list"
+	batchActionView      = "view"
+	batchActionExpiry    = "exp"
+	batchActionPassword  = "pass"
+	batchActionNoPass    = "nopass"
+	batchActionRange     = "range"
+	batchActionRevoke    = "revoke"
+	batchActionConfirmRv = "rvyes"
+
+	// batchPasswordAttempts is the number of passwords a user can send for protected batches every hour.
+	batchPasswordAttempts = 5
+)
+
+// batchPasswordLimiter limits the number of passwords a user can send for protected batches every hour.
+var batchPasswordLimiter = ratelimit.New(time.Hour)
+
+// batchExpiryHours are the expiry times in hours that can be set for a batch from /batches.
+var batchExpiryHours = []int{24, 72, 168, 720}
+
+// batchLink returns the link that sends a batch.
+func batchLink(bot *gotgbot.Bot, id string) string {
+	return fmt.Sprintf("https://t.me/%s?start=%s%s", bot.Username, BatchDataPrefix, id)
+}
+
+// batchStart sends a saved batch to the user from its start link.
+func batchStart(bot *gotgbot.Bot, ctx *ext.Context, id string) error {
+	m := ctx.Message
+
+	b, err := _app.DB.GetBatch(id)
+	if err != nil {
+		if !database.IsNoDocumentsError(err) {
+			_app.Log.Warn("start: get batch failed", zap.Error(err), zap.String("id", id))
+		}
+
+		m.Reply(bot, "<i>📛 This Link Was Revoked or Does Not Exist, Please Get a New Link from the Chat Where You Found It :/</i>", &gotgbot.SendMessageOpts{ParseMode: gotgbot.ParseModeHTML})
+
+		return nil
+	}
+
+	if b.IsExpired() {
+		invalidLink(bot, ctx, deeplink.ErrExpired)
+		return nil
+	}
+
+	if b.Password != "" {
+		if ok, wait := batchPasswordLimiter.Allow(m.From.Id, batchPasswordAttempts); !ok {
+			m.Reply(bot, fmt.Sprintf("<i>⏳ Too Many Password Attempts! Please Try Again in %d Minutes.</i>", int(math.Ceil(wait.Minutes()))), &gotgbot.SendMessageOpts{ParseMode: gotgbot.ParseModeHTML})
+			return nil
+		}
+
+		conv := conversation.NewConversatorFromUpdate(bot, ctx.Update)
+
+		askM, err := conv.Ask(_app.Ctx, "<b>🔒 This Batch is Protected, Please Send the Password:</b>", &gotgbot.SendMessageOpts{ParseMode: gotgbot.ParseModeHTML})
+		if err != nil {
+			_app.Log.Debug("start: batch password conv exited with error", zap.Error(err))
+			return nil
+		}
+
+		if !b.CheckPassword(strings.TrimSpace(askM.Text)) {
+			askM.Reply(bot, "<i>❌ Wrong Password, Please Open the Link Again to Retry.</i>", &gotgbot.SendMessageOpts{ParseMode: gotgbot.ParseModeHTML})
+			return nil
+		}
+	}
+
+	if !deliverBatch(bot, ctx, &BatchURLData{ChatId: b.ChatId, StartMessageId: b.StartMessageId, EndMessageId: b.EndMessageId}) {
+		return nil
+	}
+
+	err = _app.DB.IncrementBatchAccesses(b.ID)
+	if err != nil {
+		_app.Log.Warn("start: increment batch accesses failed", zap.Error(err), zap.String("id", b.ID))
+	}
+
+	return nil
+}
+
+// deliverBatch sends the messages of a batch to the user after checking force subscribe channels.
+// Returns false if the user hasn't joined the channels.
+func deliverBatch(bot *gotgbot.Bot, ctx *ext.Context, d *BatchURLData) bool {
+	m := ctx.Message
+
+	ok, err := fsub.CheckFsub(_app, bot, ctx)
+	if err != nil {
+		_app.Log.Warn("start: check fsub failed", zap.Error(err))
+	}
+
+	if !ok {
+		return false
+	}
+
+	pm, _ := m.Reply(bot, "<b>Fetching Media 📥</b>", &gotgbot.SendMessageOpts{ParseMode: gotgbot.ParseModeHTML})
+
+	SendBatch(bot, m.Chat.Id, d)
+
+	if pm != nil {
+		pm.Delete(bot, nil)
+	}
+
+	return true
+}
+
+// batchDetails returns the details of a batch as html.
+func batchDetails(bot *gotgbot.Bot, b *model.Batch) string {
+	expires := "Never"
+	if b.ExpiresAt != 0 {
+		expires = time.Unix(b.ExpiresAt, 0).UTC().Format("02 Jan 2006 15:04 MST")
+		if b.IsExpired() {
+			expires += " (Expired)"
+		}
+	}
+
+	password := "No"
+	if b.Password != "" {
+		password = "Yes"
+	}
+
+	return fmt.Sprintf(
+		"<b>ID</b>: <code>%s</code>\n<b>Link</b>: <code>%s</code>\n<b>Messages</b>: <a href='https://t.me/c/%d/%d'>%d</a> to <a href='https://t.me/c/%d/%d'>%d</a> (%d)\n<b>Created By</b>: <a href='tg://user?id=%d'>%d</a>\n<b>Created On</b>: %s\n<b>Expires</b>: %s\n<b>Password</b>: %s\n<b>Accesses</b>: %d",
+		b.ID,
+		batchLink(bot, b.ID),
+		functions.ChatIdToMtproto(b.ChatId), b.StartMessageId, b.StartMessageId,
+		functions.ChatIdToMtproto(b.ChatId), b.EndMessageId, b.EndMessageId,
+		b.EndMessageId-b.StartMessageId+1,
+		b.CreatedBy, b.CreatedBy,
+		time.Unix(b.CreatedAt, 0).UTC().Format("02 Jan 2006 15:04 MST"),
+		expires,
+		password,
+		b.Accesses,
+	)
+}
+
+// batchButtons returns the buttons to manage a batch.
+func batchButtons(b *model.Batch) [][]gotgbot.InlineKeyboardButton {
+	button := func(text, action string, args ...string) gotgbot.InlineKeyboardButton {
+		return gotgbot.InlineKeyboardButton{Text: text, CallbackData: "batches|" + strings.Join(append([]string{action, b.ID}, args...), "_")}
+	}
+
+	passwordButton := button("🔑 Set Password", batchActionPassword)
+	if b.Password != "" {
+		passwordButton = button("🔓 Remove Password", batchActionNoPass)
+	}
+
+	expiryRow := make([]gotgbot.InlineKeyboardButton, 0, len(batchExpiryHours)+1)
+	for _, h := range batchExpiryHours {
+		text := fmt.Sprintf("%dh", h)
+		if h%24 == 0 {
+			text = fmt.Sprintf("%dd", h/24)
+		}
+
+		expiryRow = append(expiryRow, button("⏳ "+text, batchActionExpiry, strconv.Itoa(h)))
+	}
+
+	expiryRow = append(expiryRow, button("♾️", batchActionExpiry, "0"))
+
+	return [][]gotgbot.InlineKeyboardButton{
+		{button("✏️ Edit Range", batchActionRange), passwordButton},
+		expiryRow,
+		{button("🗑️ Revoke", batchActionRevoke)},
+		{{Text: "« Batches", CallbackData: "batches|" + batchActionList + "_0"}},
+	}
+}
+
+// CmdBatches handles the /batches command which lists all saved batches.
+func CmdBatches(bot *gotgbot.Bot, ctx *ext.Context) error {
+	if !_app.AuthAdmin(ctx) {
+		return nil
+	}
+
+	text, buttons, err := batchesPage(0)
+	if err != nil {
+		_app.Log.Warn("cmdbatches: get batches failed", zap.Error(err))
+		ctx.Message.Reply(bot, "Failed to fetch batches: "+err.Error(), nil)
+
+		return nil
+	}
+
+	_, err = ctx.Message.Reply(bot, text, &gotgbot.SendMessageOpts{
+		ParseMode:   gotgbot.ParseModeHTML,
+		ReplyMarkup: gotgbot.InlineKeyboardMarkup{InlineKeyboard: buttons},
+	})
+	if err != nil {
+		_app.Log.Warn("cmdbatches: send batches failed", zap.Error(err))
+	}
+
+	return nil
+}
+
+// batchesPage returns the text and buttons of a page of the list of batches.
+func batchesPage(page int) (string, [][]gotgbot.InlineKeyboardButton, error) {
+	batches, err := _app.DB.GetBatches()
+	if err != nil {
+		return "", nil, err
+	}
+
+	var accesses int64
+	for _, b := range batches {
+		accesses += b.Accesses
+	}
+
+	text := fmt.Sprintf("<b><u>Batches</u></b>\n\n📦 Batches: %d\n📥 Accesses: %d\n\n<i>Select a batch to manage it.</i>", len(batches), accesses)
+
+	totalPages := max((len(batches)+batchesPerPage-1)/batchesPerPage, 1)
+	page = min(max(page, 0), totalPages-1)
+
+	var buttons [][]gotgbot.InlineKeyboardButton
+
+	for _, b := range batches[page*batchesPerPage : min((page+1)*batchesPerPage, len(batches))] {
+		emoji := "📦"
+		switch {
+		case b.IsExpired():
+			emoji = "⌛"
+		case b.Password != "":
+			emoji = "🔒"
+		}
+
+		buttons = append(buttons, []gotgbot.InlineKeyboardButton{{
+			Text:         fmt.Sprintf("%s %s · %d messages · %d 📥", emoji, b.ID, b.EndMessageId-b.StartMessageId+1, b.Accesses),
+			CallbackData: fmt.Sprintf("batches|%s_%s", batchActionView, b.ID),
+		}})
+	}
+
+	if totalPages > 1 {
+		var row []gotgbot.InlineKeyboardButton
+
+		if page > 0 {
+			row = append(row, gotgbot.InlineKeyboardButton{Text: "« Prev", CallbackData: fmt.Sprintf("batches|%s_%d", batchActionList, page-1)})
+		}
+
+		row = append(row, gotgbot.InlineKeyboardButton{Text: fmt.Sprintf("%d/%d", page+1, totalPages), CallbackData: "ignore"})
+
+		if page < totalPages-1 {
+			row = append(row, gotgbot.InlineKeyboardButton{Text: "Next »", CallbackData: fmt.Sprintf("batches|%s_%d", batchActionList, page+1)})
+		}
+
+		buttons = append(buttons, row)
+	}
+
+	return text, append(buttons, []gotgbot.InlineKeyboardButton{{Text: "✖️ Close", CallbackData: "close"}}), nil
+}
+
+// CbBatches handles the callback from the batch list and management buttons.
+// Structure: batches|<action>_<batch id or page>_<optional value>
+func CbBatches(bot *gotgbot.Bot, ctx *ext.Context) error {
+	if !_app.AuthAdmin(ctx) {
+		return nil
+	}
+
+	c := ctx.CallbackQuery
+
+	d := callbackdata.FromString(c.Data)
+	if d.LenArgs() < 2 {
+		c.Answer(bot, &gotgbot.AnswerCallbackQueryOpts{Text: "Not enough arguments in callback button", ShowAlert: true})
+		_app.Log.Warn("cbbatches: no arguments in callback", zap.String("data", c.Data), zap.Strings("args", d.Args))
+
+		return nil
+	}
+
+	action := d.Args[0]
+
+	var (
+		text    string
+		buttons [][]gotgbot.InlineKeyboardButton
+	)
+
+	if action == batchActionList {
+		page, _ := strconv.Atoi(d.Args[1])
+
+		var err error
+
+		text, buttons, err = batchesPage(page)
+		if err != nil {
+			_app.Log.Warn("cbbatches: get batches failed", zap.Error(err))
+			c.Answer(bot, &gotgbot.AnswerCallbackQueryOpts{Text: "Failed to fetch batches: " + err.Error(), ShowAlert: true})
+
+			return nil
+		}
+
+		editBatchMessage(bot, c, text, buttons)
+
+		return nil
+	}
+
+	b, err := _app.DB.GetBatch(d.Args[1])
+	if err != nil {
+		c.Answer(bot, &gotgbot.AnswerCallbackQueryOpts{Text: "Batch Not Found!", ShowAlert: true})
+		return nil
+	}
+
+	var (
+		update = make(map[string]interface{})
+		alert  string
+	)
+
+	switch action {
+	case batchActionExpiry:
+		if d.LenArgs() < 3 {
+			c.Answer(bot, &gotgbot.AnswerCallbackQueryOpts{Text: "Not enough arguments in callback button", ShowAlert: true})
+			return nil
+		}
+
+		hours, err := strconv.Atoi(d.Args[2])
+		if err != nil {
+			c.Answer(bot, &gotgbot.AnswerCallbackQueryOpts{Text: "Bad callback data", ShowAlert: true})
+			return nil
+		}
+
+		b.ExpiresAt = 0
+		alert = "Link Will Never Expire"
+
+		if hours != 0 {
+			b.ExpiresAt = time.Now().Add(time.Duration(hours) * time.Hour).Unix()
+			alert = fmt.Sprintf("Link Will Expire in %d Hours", hours)
+		}
+
+		update["expires_at"] = b.ExpiresAt
+	case batchActionPassword:
+		c.Answer(bot, nil)
+
+		conv := conversation.NewConversatorFromUpdate(bot, ctx.Update)
+
+		askM, err := conv.Ask(_app.Ctx, "Please send the password users must enter to get this batch:", nil)
+		if err != nil {
+			_app.Log.Debug("cbbatches: conv exited with error", zap.Error(err))
+			return nil
+		}
+
+		password := strings.TrimSpace(askM.Text)
+		if password == "" {
+			askM.Reply(bot, "Password Cannot be Empty!", nil)
+			return nil
+		}
+
+		err = b.SetPassword(password)
+		if err != nil {
+			if errors.Is(err, model.ErrPasswordTooLong) {
+				askM.Reply(bot, fmt.Sprintf("Password Cannot be Longer Than %d Characters!", model.BatchMaxPasswordLength), nil)
+				return nil
+			}
+
+			_app.Log.Warn("cbbatches: hash password failed", zap.Error(err))
+			askM.Reply(bot, "Failed to set password: "+err.Error(), nil)
+
+			return nil
+		}
+
+		update["password"] = b.Password
+		alert = "Password Set"
+	case batchActionNoPass:
+		b.Password = ""
+		update["password"] = b.Password
+		alert = "Password Removed"
+	case batchActionRange:
+		c.Answer(bot, nil)
+
+		conv := conversation.NewConversatorFromUpdate(bot, ctx.Update)
+
+		chatId, startId, ok := askBatchPost(bot, conv, "Please forward or send the post link of the new first message in the batch:")
+		if !ok {
+			return nil
+		}
+
+		endChatId, endId, ok := askBatchPost(bot, conv, "Please forward or send the post link of the new last message in the batch:")
+		if !ok {
+			return nil
+		}
+
+		if endChatId != chatId {
+			bot.SendMessage(c.From.Id, "Both Messages Must be From the Same Channel :/", nil)
+			return nil
+		}
+
+		if startId > endId {
+			bot.SendMessage(c.From.Id, "First Message Cannot be After The Last :/", nil)
+			return nil
+		}
+
+		if endId-startId > _app.Config.GetBatchSizeLimit() {
+			bot.SendMessage(c.From.Id, "Batch Too Large :/\n\nUse a Smaller Range or Update The Batch Size Limit From the Config Panel!", nil)
+			return nil
+		}
+
+		b.ChatId, b.StartMessageId, b.EndMessageId = chatId, startId, endId
+		update["chat_id"], update["start"], update["end"] = chatId, startId, endId
+		alert = "Range Updated"
+	case batchActionRevoke:
+		editBatchMessage(bot, c, "<b>🗑️ Revoke Batch</b>\n\n"+batchDetails(bot, b)+"\n\n<i>The link will stop working permanently, are you sure?</i>", [][]gotgbot.InlineKeyboardButton{{
+			{Text: "✅ Yes, Revoke", CallbackData: fmt.Sprintf("batches|%s_%s", batchActionConfirmRv, b.ID)},
+			{Text: "« Back", CallbackData: fmt.Sprintf("batches|%s_%s", batchActionView, b.ID)},
+		}})
+
+		return nil
+	case batchActionConfirmRv:
+		err := _app.DB.DeleteBatch(b.ID)
+		if err != nil {
+			_app.Log.Warn("cbbatches: delete batch failed", zap.Error(err), zap.String("id", b.ID))
+			c.Answer(bot, &gotgbot.AnswerCallbackQueryOpts{Text: "Failed to revoke batch: " + err.Error(), ShowAlert: true})
+
+			return nil
+		}
+
+		c.Answer(bot, &gotgbot.AnswerCallbackQueryOpts{Text: "Batch Revoked"})
+
+		text, buttons, err = batchesPage(0)
+		if err != nil {
+			_app.Log.Warn("cbbatches: get batches failed", zap.Error(err))
+			return nil
+		}
+
+		editBatchMessage(bot, c, text, buttons)
+
+		return nil
+	}
+
+	if len(update) != 0 {
+		err := _app.DB.UpdateBatch(b.ID, update)
+		if err != nil {
+			_app.Log.Warn("cbbatches: update batch failed", zap.Error(err), zap.String("id", b.ID))
+			c.Answer(bot, &gotgbot.AnswerCallbackQueryOpts{Text: "Failed to update batch: " + err.Error(), ShowAlert: true})
+
+			return nil
+		}
+
+		c.Answer(bot, &gotgbot.AnswerCallbackQueryOpts{Text: alert})
+	}
+
+	editBatchMessage(bot, c, "<b>📦 Batch Details</b>\n\n"+batchDetails(bot, b), batchButtons(b))
+
+	return nil
+}
+
+// editBatchMessage edits the message of a /batches callback.
+func editBatchMessage(bot *gotgbot.Bot, c *gotgbot.CallbackQuery, text string, buttons [][]gotgbot.InlineKeyboardButton) {
+	_, _, err := c.Message.EditText(bot, text, &gotgbot.EditMessageTextOpts{
+		ParseMode:          gotgbot.ParseModeHTML,
+		ReplyMarkup:        gotgbot.InlineKeyboardMarkup{InlineKeyboard: buttons},
+		LinkPreviewOptions: &gotgbot.LinkPreviewOptions{IsDisabled: true},
+	})
+	if err != nil {
+		_app.Log.Debug("cbbatches: edit message failed", zap.Error(err))
+	}
+}
+
+// askBatchPost asks the admin to forward a channel post or send its link and returns the chat and message id.
+// Returns false if the conversation ended or the reply was not a channel post.
+func askBatchPost(bot *gotgbot.Bot, conv *conversation.Conversator, text string) (int64, int64, bool) {
+	askM, err := conv.Ask(_app.Ctx, text, nil)
+	if err != nil {
+		_app.Log.Debug("cbbatches: conv exited with error", zap.Error(err))
+		return 0, 0, false
+	}
+
+	if origin, ok := askM.ForwardOrigin.(gotgbot.MessageOriginChannel); ok {
+		return origin.Chat.Id, origin.MessageId, true
+	}
+
+	link, err := functions.ParseMessageLink(askM.Text)
+	if err != nil {
+		askM.Reply(bot, "Message Is Not a Forwarded Channel Post or Message Link!", nil)
+		return 0, 0, false
+	}
+
+	c, err := link.GetChat(bot)
+	if err != nil {
+		sendChatErr(bot, askM.Chat.Id, err)
+		return 0, 0, false
+	}
+
+	return c.Id, link.MessageId, true
+}
@@ -0,0 +1,159 @@
+package core
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/Jisin0/autofilterbot/internal/autofilter"
+	"github.com/Jisin0/autofilterbot/internal/button"
+	"github.com/Jisin0/autofilterbot/internal/catalog"
+	"github.com/Jisin0/autofilterbot/internal/model"
+	"github.com/PaulSonOfLars/gotgbot/v2"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext"
+	"go.uber.org/zap"
+)
+
+const importUsage = `<b><u>Import Files</u></b>
+
+Reply to a <code>.jsonl</code> or <code>.csv</code> file created using /export with <code>/import</code> to save all files in it.
+Files that are already saved are skipped.`
+
+// CmdExport handles the /export command which sends all saved files as a jsonl or csv document.
+func CmdExport(bot *gotgbot.Bot, ctx *ext.Context) error {
+	if !_app.AuthAdmin(ctx) {
+		return nil
+	}
+
+	m := ctx.EffectiveMessage
+
+	format := catalog.FormatJSONL
+
+	if args := strings.Fields(m.Text); len(args) > 1 {
+		f, err := catalog.ParseFormat(args[1])
+		if err != nil {
+			m.Reply(bot, "Unknown format, please use csv or jsonl.", nil)
+			return nil
+		}
+
+		format = f
+	}
+
+	prg, _ := m.Reply(bot, "⏳ 𝖤𝗑𝗉𝗈𝗋𝗍𝗂𝗇𝗀 . . .", nil)
+
+	cursor, err := _app.DB.GetAllFiles()
+	if err != nil {
+		_app.Log.Warn("cmdexport: get all files failed", zap.Error(err))
+		m.Reply(bot, "Failed to fetch files: "+err.Error(), nil)
+
+		return nil
+	}
+
+	defer cursor.Close(_app.Ctx)
+
+	// files are written to the pipe while the document is being uploaded
+	pr, pw := io.Pipe()
+
+	var count int
+
+	go func() {
+		n, err := catalog.Export(_app.Ctx, cursor, pw, format)
+		count = n
+		pw.CloseWithError(err)
+	}()
+
+	_, err = bot.SendDocument(
+		ctx.EffectiveChat.Id,
+		gotgbot.InputFileByReader("catalog."+string(format), pr),
+		&gotgbot.SendDocumentOpts{
+			ReplyParameters: &gotgbot.ReplyParameters{
+				MessageId: m.MessageId,
+			},
+			ReplyMarkup: gotgbot.InlineKeyboardMarkup{InlineKeyboard: [][]gotgbot.InlineKeyboardButton{{button.Close(m.From.Id)}}},
+		},
+	)
+
+	pr.Close() // stops the export if the upload failed midway
+
+	if err != nil {
+		_app.Log.Warn("cmdexport: send document failed", zap.Error(err))
+		m.Reply(bot, "Failed to export files: "+err.Error(), nil)
+	} else {
+		_app.Log.Info("cmdexport: exported files", zap.Int("count", count))
+	}
+
+	if prg != nil {
+		prg.Delete(bot, nil)
+	}
+
+	return nil
+}
+
+// CmdImport handles the /import command which saves all files from a document created by /export.
+func CmdImport(bot *gotgbot.Bot, ctx *ext.Context) error {
+	if !_app.AuthAdmin(ctx) {
+		return nil
+	}
+
+	m := ctx.EffectiveMessage
+
+	if m.ReplyToMessage == nil || m.ReplyToMessage.Document == nil {
+		m.Reply(bot, importUsage, &gotgbot.SendMessageOpts{ParseMode: gotgbot.ParseModeHTML})
+		return nil
+	}
+
+	doc := m.ReplyToMessage.Document
+
+	prg, _ := m.Reply(bot, "⏳ 𝖨𝗆𝗉𝗈𝗋𝗍𝗂𝗇𝗀 . . .", nil)
+
+	f, err := bot.GetFile(doc.FileId, nil)
+	if err != nil {
+		_app.Log.Warn("cmdimport: get file failed", zap.Error(err))
+		m.Reply(bot, "Failed to download file: "+err.Error(), nil)
+
+		return nil
+	}
+
+	resp, err := http.Get(f.URL(bot, nil))
+	if err != nil {
+		_app.Log.Warn("cmdimport: download file failed", zap.Error(err))
+		m.Reply(bot, "Failed to download file: "+err.Error(), nil)
+
+		return nil
+	}
+
+	defer resp.Body.Close()
+
+	result, err := catalog.Import(_app.Ctx, resp.Body, catalog.FormatFromFileName(doc.FileName), func(f *model.File) error {
+		if err := _app.DB.SaveFile(f); err != nil {
+			return err
+		}
+
+		_app.Vocabulary.Add(autofilter.WordsFromFileName(f.FileName)...)
+
+		return nil
+	})
+	if err != nil {
+		_app.Log.Warn("cmdimport: import failed", zap.Error(err))
+	}
+
+	text := fmt.Sprintf(`<b><u>Import Complete</u></b>
+
+<b>Inserted</b>: <code>%d</code>
+<b>Duplicate</b>: <code>%d</code>
+<b>Invalid</b>: <code>%d</code>`, result.Inserted, result.Duplicate, result.Invalid)
+
+	if err != nil {
+		text += "\n\n<b>Stopped Early</b>: " + html.EscapeString(err.Error())
+	}
+
+	if prg != nil {
+		prg.EditText(bot, text, &gotgbot.EditMessageTextOpts{ParseMode: gotgbot.ParseModeHTML})
+	} else {
+		m.Reply(bot, text, &gotgbot.SendMessageOpts{ParseMode: gotgbot.ParseModeHTML})
+	}
+
+	return nil
+}
@@ -0,0 +1,277 @@
+package core
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"github.com/Jisin0/autofilterbot/internal/database"
+	"github.com/Jisin0/autofilterbot/internal/fsub"
+	"github.com/Jisin0/autofilterbot/internal/functions"
+	"github.com/Jisin0/autofilterbot/internal/model"
+	"github.com/Jisin0/autofilterbot/pkg/callbackdata"
+	"github.com/Jisin0/autofilterbot/pkg/conversation"
+	"github.com/PaulSonOfLars/gotgbot/v2"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext"
+	"go.uber.org/zap"
+)
+
+// collectionLink returns the link that sends a collection.
+func collectionLink(bot *gotgbot.Bot, c *model.Collection) string {
+	return fmt.Sprintf("https://t.me/%s?start=%s", bot.Username, c.StartData())
+}
+
+// collectionStart sends the files of a collection in order from its start link.
+func collectionStart(bot *gotgbot.Bot, ctx *ext.Context, id string) error {
+	m := ctx.Message
+
+	c, err := _app.DB.GetCollection(id)
+	if err != nil {
+		if !database.IsNoDocumentsError(err) {
+			_app.Log.Warn("start: get collection failed", zap.Error(err), zap.String("id", id))
+		}
+
+		m.Reply(bot, "<i>📛 This Collection Was Deleted or Does Not Exist, Please Get a New Link from the Chat Where You Found It :/</i>", &gotgbot.SendMessageOpts{ParseMode: gotgbot.ParseModeHTML})
+
+		return nil
+	}
+
+	ok, err := fsub.CheckFsub(_app, bot, ctx)
+	if err != nil {
+		_app.Log.Warn("start: check fsub failed", zap.Error(err))
+	}
+
+	if !ok {
+		return nil
+	}
+
+	pm, _ := m.Reply(bot, fmt.Sprintf("<b>Fetching %s 📥</b>", html.EscapeString(c.Name)), &gotgbot.SendMessageOpts{ParseMode: gotgbot.ParseModeHTML})
+
+	sendFiles(bot, ctx, m.Chat.Id, c.Files)
+
+	if pm != nil {
+		pm.Delete(bot, nil)
+	}
+
+	return nil
+}
+
+// collectionByName returns the collection with the name ignoring case, a new unsaved collection is returned if none has the name.
+func collectionByName(name string, userId int64) (*model.Collection, error) {
+	collections, err := _app.DB.GetCollections()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, c := range collections {
+		if strings.EqualFold(c.Name, name) {
+			return c, nil
+		}
+	}
+
+	id, err := newShortId()
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.Collection{
+		ID:        id,
+		Name:      name,
+		Files:     model.CollectionFiles{},
+		CreatedBy: userId,
+		CreatedAt: time.Now().Unix(),
+	}, nil
+}
+
+// collectionSavedText returns the message sent after files are added to a collection.
+func collectionSavedText(bot *gotgbot.Bot, c *model.Collection, added int) string {
+	url := collectionLink(bot, c)
+
+	text := fmt.Sprintf("<b>✅ Added %d Files to %s</b>\n\n<b>Files</b>: %d/%d\n<b>Link</b>: <code>%s</code>", added, html.EscapeString(c.Name), len(c.Files), model.CollectionMaxFiles, url)
+	if len(c.Files) >= model.CollectionMaxFiles {
+		text += "\n\n<i>⚠️ The Collection is Full.</i>"
+	}
+
+	return text
+}
+
+// CmdCollect handles the /collect command which adds forwarded files or files by their unique id to a collection.
+// Usage: /collect <name>
+func CmdCollect(bot *gotgbot.Bot, ctx *ext.Context) error {
+	if !_app.AuthAdmin(ctx) {
+		return nil
+	}
+
+	m := ctx.Message
+	conv := conversation.NewConversatorFromUpdate(bot, ctx.Update)
+
+	name := strings.TrimSpace(strings.TrimPrefix(m.Text, strings.Fields(m.Text)[0]))
+	if name == "" {
+		askM, err := conv.Ask(_app.Ctx, "Please send the name of the collection, a new collection is created if none has this name:", nil)
+		if err != nil {
+			_app.Log.Debug("collect: conv exited with error", zap.Error(err))
+			return nil
+		}
+
+		name = strings.TrimSpace(askM.Text)
+		if name == "" {
+			askM.Reply(bot, "Name Cannot be Empty!", nil)
+			return nil
+		}
+	}
+
+	c, err := collectionByName(name, m.From.Id)
+	if err != nil {
+		_app.Log.Warn("collect: get collection failed", zap.Error(err))
+		m.Reply(bot, "Failed to fetch collections: "+err.Error(), nil)
+
+		return nil
+	}
+
+	_, err = conv.Ask(_app.Ctx, fmt.Sprintf("Forward files or send their unique ids to add them to <b>%s</b> in order. Send /done when you're finished.", html.EscapeString(c.Name)), nil)
+	if err != nil {
+		_app.Log.Debug("collect: conv exited with error", zap.Error(err))
+		return nil
+	}
+
+	for len(c.Files) < model.CollectionMaxFiles {
+		reply, err := conv.Listen(_app.Ctx)
+		if err != nil {
+			_app.Log.Debug("collect: conv exited with error", zap.Error(err))
+			break
+		}
+
+		if strings.TrimSpace(reply.Text) == "/done" {
+			break
+		}
+
+		var (
+			uniqueIds []string
+			missing   int
+		)
+
+		if f := functions.FileFromMessage(reply); f != nil {
+			// forwarded files that were never indexed are saved so they can be sent
+			if _, err := _app.DB.GetFile(f.UniqueId); database.IsNoDocumentsError(err) {
+				if err := _app.DB.SaveFile(f); err != nil {
+					_app.Log.Warn("collect: save file failed", zap.Error(err), zap.String("file", f.UniqueId))
+				}
+			}
+
+			uniqueIds = append(uniqueIds, f.UniqueId)
+		} else {
+			for _, uid := range strings.Fields(reply.Text) {
+				if _, err := _app.DB.GetFile(uid); err != nil {
+					missing++
+					continue
+				}
+
+				uniqueIds = append(uniqueIds, uid)
+			}
+		}
+
+		added := c.AddFiles(uniqueIds...)
+
+		if added != 0 {
+			err = _app.DB.SaveCollection(c)
+			if err != nil {
+				_app.Log.Warn("collect: save collection failed", zap.Error(err))
+				reply.Reply(bot, "Failed to save collection: "+err.Error(), nil)
+
+				return nil
+			}
+		}
+
+		text := fmt.Sprintf("<i>➕ Added %d Files, %d/%d in Collection.</i>", added, len(c.Files), model.CollectionMaxFiles)
+		if missing != 0 {
+			text += fmt.Sprintf("\n<i>⚠️ %d Files Were Not Found.</i>", missing)
+		}
+
+		reply.Reply(bot, text, &gotgbot.SendMessageOpts{ParseMode: gotgbot.ParseModeHTML})
+	}
+
+	_, err = bot.SendMessage(m.Chat.Id, collectionSavedText(bot, c, len(c.Files)), &gotgbot.SendMessageOpts{
+		ParseMode:   gotgbot.ParseModeHTML,
+		ReplyMarkup: gotgbot.InlineKeyboardMarkup{InlineKeyboard: [][]gotgbot.InlineKeyboardButton{{{Text: "𝖳𝗋𝗒 𝖭𝗈𝗐", Url: collectionLink(bot, c)}}}},
+	})
+	if err != nil {
+		_app.Log.Warn("collect: send collection msg failed", zap.Error(err))
+	}
+
+	return nil
+}
+
+// CbCollectionAdd handles the button in the select menu that adds the selected files to a collection.
+// Structure: coladd|<result unique id>
+func CbCollectionAdd(bot *gotgbot.Bot, ctx *ext.Context) error {
+	if !_app.AuthAdmin(ctx) {
+		return nil
+	}
+
+	c := ctx.CallbackQuery
+
+	d := callbackdata.FromString(c.Data)
+	if d.LenArgs() < 1 {
+		c.Answer(bot, &gotgbot.AnswerCallbackQueryOpts{Text: "Not enough arguments in callback button", ShowAlert: true})
+		return nil
+	}
+
+	r, ok, err := _app.Cache.Autofilter.Get(d.Args[0])
+	if !ok || err != nil {
+		c.Answer(bot, &gotgbot.AnswerCallbackQueryOpts{Text: "Search Result Has Expired!\nPlease Try Again...", ShowAlert: true})
+		return nil
+	}
+
+	selected := r.Selected()
+	if len(selected) == 0 {
+		c.Answer(bot, &gotgbot.AnswerCallbackQueryOpts{Text: "Select Files to Add First!", ShowAlert: true})
+		return nil
+	}
+
+	c.Answer(bot, nil)
+
+	conv := conversation.NewConversatorFromUpdate(bot, ctx.Update)
+
+	askM, err := conv.Ask(_app.Ctx, fmt.Sprintf("Please send the name of the collection to add %d selected files to, a new collection is created if none has this name:", len(selected)), nil)
+	if err != nil {
+		_app.Log.Debug("coladd: conv exited with error", zap.Error(err))
+		return nil
+	}
+
+	name := strings.TrimSpace(askM.Text)
+	if name == "" {
+		askM.Reply(bot, "Name Cannot be Empty!", nil)
+		return nil
+	}
+
+	coll, err := collectionByName(name, c.From.Id)
+	if err != nil {
+		_app.Log.Warn("coladd: get collection failed", zap.Error(err))
+		askM.Reply(bot, "Failed to fetch collections: "+err.Error(), nil)
+
+		return nil
+	}
+
+	uniqueIds := make([]string, 0, len(selected))
+	for _, f := range selected {
+		uniqueIds = append(uniqueIds, f.UniqueId)
+	}
+
+	added := coll.AddFiles(uniqueIds...)
+
+	err = _app.DB.SaveCollection(coll)
+	if err != nil {
+		_app.Log.Warn("coladd: save collection failed", zap.Error(err))
+		askM.Reply(bot, "Failed to save collection: "+err.Error(), nil)
+
+		return nil
+	}
+
+	_, err = askM.Reply(bot, collectionSavedText(bot, coll, added), &gotgbot.SendMessageOpts{ParseMode: gotgbot.ParseModeHTML})
+	if err != nil {
+		_app.Log.Warn("coladd: send collection msg failed", zap.Error(err))
+	}
+
+	return nil
+}
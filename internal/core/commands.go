@@ -148,9 +148,10 @@ func Stats(bot *gotgbot.Bot, ctx *ext.Context) error {
 
 	m := _app.Config.GetStatsMessage().Format(_app.BasicMessageValues(ctx, map[string]any{
 		"users":  s.Users,
-		"files":  s.Files,
-		"groups": s.Groups,
-		"uptime": time.Since(_app.StartTime).Truncate(time.Second),
+		"files":     s.Files,
+		"groups":    s.Groups,
+		"downloads": s.Downloads,
+		"uptime":    time.Since(_app.StartTime).Truncate(time.Second),
 	}))
 
 	switch {
@@ -1,22 +1,33 @@
 package core
 
 import (
+	"github.com/Jisin0/autofilterbot/internal/configpanel"
 	"github.com/PaulSonOfLars/gotgbot/v2"
 	"github.com/PaulSonOfLars/gotgbot/v2/ext"
 	"go.uber.org/zap"
 )
 
 // Settings handles the /settings command which acts as the entrypoint into the config panel.
+// In groups the group panel is opened to change settings of the group.
 func Settings(bot *gotgbot.Bot, ctx *ext.Context) error {
-	if !_app.AuthAdmin(ctx) {
+	m := ctx.Message
+
+	callbackData := "config"
+
+	if m.Chat.Type == gotgbot.ChatTypeSupergroup || m.Chat.Type == gotgbot.ChatTypeGroup {
+		if m.From == nil || !isGroupAdmin(bot, m.Chat.Id, m.From.Id) {
+			m.Reply(bot, "<b>𝖮𝗇𝗅𝗒 𝖺 𝗀𝗋𝗈𝗎𝗉 𝖺𝖽𝗆𝗂𝗇 𝖼𝖺𝗇 𝖼𝗁𝖺𝗇𝗀𝖾 𝗌𝖾𝗍𝗍𝗂𝗇𝗀𝗌 ❗</b>", &gotgbot.SendMessageOpts{ParseMode: gotgbot.ParseModeHTML})
+			return nil
+		}
+
+		callbackData = configpanel.GroupPanelPath
+	} else if !_app.AuthAdmin(ctx) {
 		return nil
 	}
 
-	m := ctx.Message
-
 	_, err := m.Reply(bot, "<b>⚙️ Cʟɪᴄᴋ Tʜᴇ Bᴜᴛᴛᴏɴ Bᴇʟᴏᴡ Tᴏ Oᴘᴇɴ Tʜᴇ Cᴏɴғɪɢ Pᴀɴᴇʟ 👇</b>", &gotgbot.SendMessageOpts{
 		ReplyMarkup: gotgbot.InlineKeyboardMarkup{
-			InlineKeyboard: [][]gotgbot.InlineKeyboardButton{{{Text: "𝖮𝖯𝖤𝖭", CallbackData: "config"}}},
+			InlineKeyboard: [][]gotgbot.InlineKeyboardButton{{{Text: "𝖮𝖯𝖤𝖭", CallbackData: callbackData}}},
 		},
 		ParseMode: gotgbot.ParseModeHTML,
 	})
@@ -40,3 +51,20 @@ func ConfigPanel(bot *gotgbot.Bot, ctx *ext.Context) error {
 
 	return nil
 }
+
+// GroupConfigPanel handles callback queries for the config panel of a group.
+func GroupConfigPanel(bot *gotgbot.Bot, ctx *ext.Context) error {
+	c := ctx.CallbackQuery
+
+	if !isGroupAdmin(bot, c.Message.GetChat().Id, c.From.Id) {
+		c.Answer(bot, &gotgbot.AnswerCallbackQueryOpts{Text: "Only a Group Admin Can Change Settings ❗", ShowAlert: true})
+		return nil
+	}
+
+	err := _app.GroupPanel.HandleUpdate(ctx, bot)
+	if err != nil {
+		_app.Log.Warn("handle group config panel failed", zap.Error(err))
+	}
+
+	return nil
+}
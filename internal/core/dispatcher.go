@@ -5,6 +5,7 @@ import (
 	"runtime/debug"
 	"strings"
 
+	"github.com/Jisin0/autofilterbot/internal/configpanel"
 	"github.com/Jisin0/autofilterbot/pkg/conversation"
 	"github.com/Jisin0/autofilterbot/pkg/env"
 	exthandlers "github.com/Jisin0/autofilterbot/pkg/filters"
@@ -12,6 +13,8 @@ import (
 	"github.com/PaulSonOfLars/gotgbot/v2/ext"
 	"github.com/PaulSonOfLars/gotgbot/v2/ext/handlers"
 	"github.com/PaulSonOfLars/gotgbot/v2/ext/handlers/filters/callbackquery"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext/handlers/filters/choseninlineresult"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext/handlers/filters/inlinequery"
 	"github.com/PaulSonOfLars/gotgbot/v2/ext/handlers/filters/message"
 	"go.uber.org/zap"
 )
@@ -51,6 +54,7 @@ func SetupDispatcher(log *zap.Logger) *ext.Dispatcher {
 	})
 
 	d.AddHandlerToGroup(handlers.NewMessage(message.Supergroup, Autofilter), autofilterHandlerGroup)
+	d.AddHandlerToGroup(handlers.NewMessage(pmSearchFilter, PMAutofilter), autofilterHandlerGroup)
 
 	d.AddHandlerToGroup(handlers.NewCommand("start", StartCommand), commandHandlerGroup)
 	d.AddHandlerToGroup(exthandlers.NewCommands([]string{"about", "help", "privacy"}, StaticCommands), commandHandlerGroup)
@@ -63,20 +67,41 @@ func SetupDispatcher(log *zap.Logger) *ext.Dispatcher {
 	d.AddHandlerToGroup(handlers.NewCommand("genlink", GenLink), commandHandlerGroup)
 	d.AddHandlerToGroup(handlers.NewCommand("broadcast", Broadcast), commandHandlerGroup)
 	d.AddHandlerToGroup(handlers.NewCommand("index", CmdIndex), commandHandlerGroup)
-
+	d.AddHandlerToGroup(handlers.NewCommand("migrate", CmdMigrate), commandHandlerGroup)
+	d.AddHandlerToGroup(handlers.NewCommand("export", CmdExport), commandHandlerGroup)
+	d.AddHandlerToGroup(handlers.NewCommand("import", CmdImport), commandHandlerGroup)
+	d.AddHandlerToGroup(handlers.NewCommand("groups", CmdGroups), commandHandlerGroup)
+	d.AddHandlerToGroup(handlers.NewCommand("batches", CmdBatches), commandHandlerGroup)
+	d.AddHandlerToGroup(handlers.NewCommand("collect", CmdCollect), commandHandlerGroup)
+
+	d.AddHandlerToGroup(handlers.NewCallback(callbackquery.Prefix("af|"), Autofilter), callbackQueryGroup)
 	d.AddHandlerToGroup(handlers.NewCallback(callbackquery.Prefix("cmd"), StaticCommands), callbackQueryGroup)
 	d.AddHandlerToGroup(handlers.NewCallback(callbackquery.Prefix("close"), Close), callbackQueryGroup)
 	d.AddHandlerToGroup(handlers.NewCallback(callbackquery.Prefix("navg"), Navigate), callbackQueryGroup)
 	d.AddHandlerToGroup(handlers.NewCallback(callbackquery.Prefix("fdetails"), FileDetails), callbackQueryGroup)
+	d.AddHandlerToGroup(handlers.NewCallback(callbackquery.Prefix("getf|"), SendFile), callbackQueryGroup)
 	d.AddHandlerToGroup(handlers.NewCallback(callbackquery.Prefix("sel"), Select), callbackQueryGroup)
+	d.AddHandlerToGroup(handlers.NewCallback(callbackquery.Prefix("sendsel|"), SendSelected), callbackQueryGroup)
+	d.AddHandlerToGroup(handlers.NewCallback(callbackquery.Prefix("clrsel|"), ClearSelected), callbackQueryGroup)
+	d.AddHandlerToGroup(handlers.NewCallback(callbackquery.Prefix("linksel|"), LinkSelected), callbackQueryGroup)
 	d.AddHandlerToGroup(handlers.NewCallback(callbackquery.Prefix("all"), All), callbackQueryGroup)
+	d.AddHandlerToGroup(handlers.NewCallback(callbackquery.Prefix("view|"), View), callbackQueryGroup)
 	d.AddHandlerToGroup(handlers.NewCallback(callbackquery.Prefix("ignore"), Ignore), callbackQueryGroup)
 	d.AddHandlerToGroup(handlers.NewCallback(callbackquery.Prefix("config"), ConfigPanel), callbackQueryGroup)
+	d.AddHandlerToGroup(handlers.NewCallback(callbackquery.Prefix(configpanel.GroupPanelPath), GroupConfigPanel), callbackQueryGroup)
 	d.AddHandlerToGroup(handlers.NewCallback(callbackquery.Equal("stats"), Stats), callbackQueryGroup)
 	d.AddHandlerToGroup(handlers.NewCallback(callbackquery.Prefix("index"), CbIndex), callbackQueryGroup)
+	d.AddHandlerToGroup(handlers.NewCallback(callbackquery.Prefix("migrate"), CbMigrate), callbackQueryGroup)
+	d.AddHandlerToGroup(handlers.NewCallback(callbackquery.Prefix("groups|"), CbGroups), callbackQueryGroup)
+	d.AddHandlerToGroup(handlers.NewCallback(callbackquery.Prefix("batches|"), CbBatches), callbackQueryGroup)
+	d.AddHandlerToGroup(handlers.NewCallback(callbackquery.Prefix("coladd|"), CbCollectionAdd), callbackQueryGroup)
+
+	d.AddHandlerToGroup(handlers.NewInlineQuery(inlinequery.All, InlineSearch), autofilterHandlerGroup)
+	d.AddHandlerToGroup(handlers.NewChosenInlineResult(choseninlineresult.All, ChosenInlineResult), autofilterHandlerGroup)
 
 	d.AddHandlerToGroup(handlers.NewMessage(exthandlers.ChatIds(env.Int64s("FILE_CHANNELS")), NewFile), miscHandlerGroup)
 	d.AddHandlerToGroup(handlers.NewChatJoinRequest(func(cjr *gotgbot.ChatJoinRequest) bool { return true }, HandleJoinRequest), joinRequestGroup)
+	d.AddHandlerToGroup(handlers.NewMyChatMember(groupMemberFilter, MyChatMember), miscHandlerGroup)
 
 	d.AddHandlerToGroup(handlers.NewMessage(message.All, conversation.MessageHandler), middleWareGroup)
 	d.AddHandlerToGroup(exthandlers.NewAllUpdates(LogUpdate), miscHandlerGroup)
@@ -41,8 +41,12 @@ func NewFile(bot *gotgbot.Bot, ctx *ext.Context) error {
 		}
 
 		_app.Log.Warn("newfile: failed to save file", zap.Error(err))
+
+		return nil
 	}
 
+	_app.Vocabulary.Add(autofilter.WordsFromFileName(file.FileName)...)
+
 	return nil
 }
 
@@ -112,13 +116,15 @@ func DeleteAllFiles(bot *gotgbot.Bot, ctx *ext.Context) error {
 		return nil
 	}
 
-	cursor, err := _app.DB.SearchFiles(keyword)
+	cursor, err := _app.DB.SearchFiles(keyword, database.SearchFilesOpts{Limit: DeleteAllCursorOptions{}.GetMaxResults()})
 	if err != nil {
 		m.Reply(bot, fmt.Sprintf("An Error occurred: %v", err), nil)
 		_app.Log.Warn("delall: search files failed", zap.Error(err), zap.String("keyword", keyword))
 		return nil
 	}
 
+	defer cursor.Close(context.Background())
+
 	f, err := autofilter.FilesFromCursor(context.Background(), cursor, DeleteAllCursorOptions{})
 	if err != nil {
 		m.Reply(bot, fmt.Sprintf("An Error occurred: %v", err), nil)
@@ -0,0 +1,74 @@
+package core
+
+import (
+	"github.com/Jisin0/autofilterbot/internal/config"
+	"github.com/PaulSonOfLars/gotgbot/v2"
+	"go.uber.org/zap"
+)
+
+// GetGroupConfig returns the settings of a group, they are fetched from the database once and cached.
+func (c *Core) GetGroupConfig(groupId int64) *config.GroupConfig {
+	c.groupConfigsMu.RLock()
+	g, ok := c.groupConfigs[groupId]
+	c.groupConfigsMu.RUnlock()
+
+	if ok {
+		return g
+	}
+
+	return c.loadGroupConfig(groupId)
+}
+
+// RefreshGroupConfig refetches the settings of a group from the database.
+func (c *Core) RefreshGroupConfig(groupId int64) {
+	c.loadGroupConfig(groupId)
+}
+
+// loadGroupConfig fetches the settings of a group and caches them, empty settings are returned without caching if an error occurs.
+func (c *Core) loadGroupConfig(groupId int64) *config.GroupConfig {
+	g, err := c.DB.GetGroupConfig(groupId)
+	if err != nil {
+		c.Log.Warn("core: get group config failed", zap.Error(err), zap.Int64("group", groupId))
+		return &config.GroupConfig{}
+	}
+
+	c.groupConfigsMu.Lock()
+	defer c.groupConfigsMu.Unlock()
+
+	if c.groupConfigs == nil {
+		c.groupConfigs = make(map[int64]*config.GroupConfig)
+	}
+
+	c.groupConfigs[groupId] = g
+
+	return g
+}
+
+// ChatConfig returns the config to use in a chat, settings of groups override the bot config.
+func (c *Core) ChatConfig(chatId int64) *config.Config {
+	if chatId >= 0 {
+		return c.Config
+	}
+
+	return c.Config.ForGroup(c.GetGroupConfig(chatId))
+}
+
+// isGroupAdmin reports whether the user is an admin of the group or the bot.
+func isGroupAdmin(bot *gotgbot.Bot, chatId, userId int64) bool {
+	if containsI64(_app.Admins, userId) {
+		return true
+	}
+
+	member, err := bot.GetChatMember(chatId, userId, nil)
+	if err != nil {
+		_app.Log.Debug("core: get chat member failed", zap.Error(err), zap.Int64("chat_id", chatId))
+		return false
+	}
+
+	switch member.(type) {
+	case gotgbot.ChatMemberOwner, gotgbot.ChatMemberAdministrator:
+		return true
+	default:
+		return false
+	}
+}
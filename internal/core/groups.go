@@ -0,0 +1,418 @@
+package core
+
+import (
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Jisin0/autofilterbot/internal/database"
+	"github.com/Jisin0/autofilterbot/internal/model"
+	"github.com/Jisin0/autofilterbot/pkg/callbackdata"
+	"github.com/PaulSonOfLars/gotgbot/v2"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext"
+	"go.uber.org/zap"
+)
+
+const (
+	// groupsPerPage is the number of groups listed in a single page of /groups.
+	groupsPerPage = 10
+
+	groupActionList    = "list"
+	groupActionView    = "view"
+	groupActionApprove = "approve"
+	groupActionLeave   = "leave"
+	groupActionBan     = "ban"
+	groupActionUnban   = "unban"
+)
+
+// groupStatusEmoji is shown next to the title of groups to indicate their status.
+var groupStatusEmoji = map[string]string{
+	model.GroupStatusPending:  "⏳",
+	model.GroupStatusApproved: "✅",
+	model.GroupStatusLeft:     "🚪",
+	model.GroupStatusBanned:   "🚫",
+}
+
+// GetGroup returns the details of a group, they are fetched from the database once and cached.
+// A nil group and error are returned if the group was never saved.
+func (c *Core) GetGroup(groupId int64) (*model.Group, error) {
+	c.groupsMu.RLock()
+	g, ok := c.groups[groupId]
+	c.groupsMu.RUnlock()
+
+	if ok {
+		return g, nil
+	}
+
+	g, err := c.DB.GetGroup(groupId)
+	if err != nil {
+		if database.IsNoDocumentsError(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	c.cacheGroup(g)
+
+	return g, nil
+}
+
+// cacheGroup saves the group in the cache replacing older details.
+func (c *Core) cacheGroup(g *model.Group) {
+	c.groupsMu.Lock()
+	defer c.groupsMu.Unlock()
+
+	if c.groups == nil {
+		c.groups = make(map[int64]*model.Group)
+	}
+
+	c.groups[g.ID] = g
+}
+
+// setGroupStatus updates the status of a group in the database and cache and returns the updated group.
+func (c *Core) setGroupStatus(g *model.Group, status string) (*model.Group, error) {
+	if err := c.DB.UpdateGroupStatus(g.ID, status); err != nil {
+		return g, err
+	}
+
+	updated := *g
+	updated.Status = status
+
+	c.cacheGroup(&updated)
+
+	return &updated, nil
+}
+
+// groupMemberFilter passes updates to the membership of the bot in groups.
+func groupMemberFilter(u *gotgbot.ChatMemberUpdated) bool {
+	return u.Chat.Type == gotgbot.ChatTypeGroup || u.Chat.Type == gotgbot.ChatTypeSupergroup
+}
+
+// MyChatMember handles the bot being added to or removed from a group.
+func MyChatMember(bot *gotgbot.Bot, ctx *ext.Context) error {
+	u := ctx.MyChatMember
+
+	wasMember, isMember := isChatMember(u.OldChatMember), isChatMember(u.NewChatMember)
+
+	switch {
+	case isMember && !wasMember:
+		addGroup(bot, &u.Chat, u.From.Id)
+	case wasMember && !isMember:
+		g, err := _app.GetGroup(u.Chat.Id)
+		if err != nil {
+			_app.Log.Warn("groups: get group failed", zap.Error(err), zap.Int64("group", u.Chat.Id))
+			return nil
+		}
+
+		// banned groups stay banned after the bot leaves
+		if g == nil || g.LeftStatus() == g.Status {
+			return nil
+		}
+
+		if _, err := _app.setGroupStatus(g, g.LeftStatus()); err != nil {
+			_app.Log.Warn("groups: update group status failed", zap.Error(err), zap.Int64("group", g.ID))
+		}
+	}
+
+	return nil
+}
+
+// isChatMember reports whether the status of a chat member means they are in the chat.
+func isChatMember(m gotgbot.ChatMember) bool {
+	switch m.GetStatus() {
+	case "creator", "administrator", "member":
+		return true
+	case "restricted":
+		return m.MergeChatMember().IsMember
+	default:
+		return false
+	}
+}
+
+// addGroup saves a group the bot was added to and notifies admins.
+// New groups are pending until approved if approval is enabled and banned groups are left immediately.
+func addGroup(bot *gotgbot.Bot, chat *gotgbot.Chat, addedBy int64) *model.Group {
+	prev, err := _app.GetGroup(chat.Id)
+	if err != nil {
+		_app.Log.Warn("groups: get group failed", zap.Error(err), zap.Int64("group", chat.Id))
+	}
+
+	g := &model.Group{
+		ID:      chat.Id,
+		Title:   chat.Title,
+		AddedBy: addedBy,
+		AddedAt: time.Now().Unix(),
+		Status:  model.JoinStatus(prev, _app.Config.GetGroupApproval()),
+	}
+
+	if n, err := bot.GetChatMemberCount(chat.Id, nil); err == nil {
+		g.MemberCount = int(n)
+	} else {
+		_app.Log.Debug("groups: get member count failed", zap.Error(err), zap.Int64("group", chat.Id))
+	}
+
+	if err := _app.DB.SaveGroup(g); err != nil {
+		_app.Log.Warn("groups: save group failed", zap.Error(err), zap.Int64("group", g.ID))
+	}
+
+	_app.cacheGroup(g)
+
+	if g.Status == model.GroupStatusBanned {
+		leaveGroup(bot, g.ID)
+	}
+
+	text := "<b>👥 Bot Added to a Group</b>\n\n" + groupDetails(g)
+	if g.Status == model.GroupStatusPending {
+		text += "\n\n<i>The bot will stay silent in this group until it is approved.</i>"
+	}
+
+	for _, id := range _app.Admins {
+		_, err := bot.SendMessage(id, text, &gotgbot.SendMessageOpts{
+			ParseMode:   gotgbot.ParseModeHTML,
+			ReplyMarkup: gotgbot.InlineKeyboardMarkup{InlineKeyboard: groupButtons(g)},
+		})
+		if err != nil {
+			_app.Log.Debug("groups: send group notification failed", zap.Error(err), zap.Int64("admin", id))
+		}
+	}
+
+	return g
+}
+
+// groupAllowed reports whether the bot should work in the group.
+// Groups the bot was added to before they were tracked are saved first.
+func groupAllowed(bot *gotgbot.Bot, chat *gotgbot.Chat) bool {
+	g, err := _app.GetGroup(chat.Id)
+	if err != nil {
+		_app.Log.Warn("groups: get group failed", zap.Error(err), zap.Int64("group", chat.Id))
+		return true
+	}
+
+	// a message from a group the bot left means the update was missed
+	if g == nil || g.Status == "" || g.Status == model.GroupStatusLeft {
+		g = addGroup(bot, chat, 0)
+	}
+
+	return g.IsAllowed(_app.Config.GetGroupApproval())
+}
+
+// leaveGroup makes the bot leave a group.
+func leaveGroup(bot *gotgbot.Bot, groupId int64) {
+	if _, err := bot.LeaveChat(groupId, nil); err != nil {
+		_app.Log.Debug("groups: leave group failed", zap.Error(err), zap.Int64("group", groupId))
+	}
+}
+
+// groupDetails returns the details of a group as html.
+func groupDetails(g *model.Group) string {
+	addedBy := "Unknown"
+	if g.AddedBy != 0 {
+		addedBy = fmt.Sprintf("<a href='tg://user?id=%d'>%d</a>", g.AddedBy, g.AddedBy)
+	}
+
+	return fmt.Sprintf(
+		"<b>Title</b>: %s\n<b>ID</b>: <code>%d</code>\n<b>Members</b>: %d\n<b>Added By</b>: %s\n<b>Added On</b>: %s\n<b>Status</b>: %s %s",
+		html.EscapeString(g.Title),
+		g.ID,
+		g.MemberCount,
+		addedBy,
+		time.Unix(g.AddedAt, 0).UTC().Format("02 Jan 2006 15:04 MST"),
+		groupStatusEmoji[g.Status],
+		g.Status,
+	)
+}
+
+// groupButtons returns the buttons to manage a group depending on its status.
+func groupButtons(g *model.Group) [][]gotgbot.InlineKeyboardButton {
+	button := func(text, action string) gotgbot.InlineKeyboardButton {
+		return gotgbot.InlineKeyboardButton{Text: text, CallbackData: fmt.Sprintf("groups|%s_%d", action, g.ID)}
+	}
+
+	var row []gotgbot.InlineKeyboardButton
+
+	switch g.Status {
+	case model.GroupStatusPending:
+		row = append(row, button("✅ Approve", groupActionApprove), button("🚪 Leave", groupActionLeave), button("🚫 Ban", groupActionBan))
+	case model.GroupStatusApproved:
+		row = append(row, button("🚪 Leave", groupActionLeave), button("🚫 Ban", groupActionBan))
+	case model.GroupStatusLeft:
+		row = append(row, button("✅ Approve", groupActionApprove), button("🚫 Ban", groupActionBan))
+	case model.GroupStatusBanned:
+		row = append(row, button("♻️ Unban", groupActionUnban))
+	}
+
+	return [][]gotgbot.InlineKeyboardButton{row, {{Text: "« Groups", CallbackData: "groups|" + groupActionList + "_0"}}}
+}
+
+// CmdGroups handles the /groups command which lists all groups the bot was added to.
+func CmdGroups(bot *gotgbot.Bot, ctx *ext.Context) error {
+	if !_app.AuthAdmin(ctx) {
+		return nil
+	}
+
+	text, buttons, err := groupsPage(0)
+	if err != nil {
+		_app.Log.Warn("cmdgroups: get groups failed", zap.Error(err))
+		ctx.Message.Reply(bot, "Failed to fetch groups: "+err.Error(), nil)
+
+		return nil
+	}
+
+	_, err = ctx.Message.Reply(bot, text, &gotgbot.SendMessageOpts{
+		ParseMode:   gotgbot.ParseModeHTML,
+		ReplyMarkup: gotgbot.InlineKeyboardMarkup{InlineKeyboard: buttons},
+	})
+	if err != nil {
+		_app.Log.Warn("cmdgroups: send groups failed", zap.Error(err))
+	}
+
+	return nil
+}
+
+// groupsPage returns the text and buttons of a page of the list of groups.
+func groupsPage(page int) (string, [][]gotgbot.InlineKeyboardButton, error) {
+	groups, err := _app.DB.GetGroups()
+	if err != nil {
+		return "", nil, err
+	}
+
+	counts := make(map[string]int)
+	for _, g := range groups {
+		counts[g.Status]++
+	}
+
+	text := fmt.Sprintf(
+		"<b><u>Groups</u></b>\n\n✅ Approved: %d\n⏳ Pending: %d\n🚫 Banned: %d\n🚪 Left: %d\n\n<i>Select a group to manage it.</i>",
+		counts[model.GroupStatusApproved],
+		counts[model.GroupStatusPending],
+		counts[model.GroupStatusBanned],
+		counts[model.GroupStatusLeft],
+	)
+
+	totalPages := max((len(groups)+groupsPerPage-1)/groupsPerPage, 1)
+	page = min(max(page, 0), totalPages-1)
+
+	var buttons [][]gotgbot.InlineKeyboardButton
+
+	for _, g := range groups[page*groupsPerPage : min((page+1)*groupsPerPage, len(groups))] {
+		title := g.Title
+		if title == "" {
+			title = strconv.FormatInt(g.ID, 10)
+		}
+
+		buttons = append(buttons, []gotgbot.InlineKeyboardButton{{
+			Text:         groupStatusEmoji[g.Status] + " " + title,
+			CallbackData: fmt.Sprintf("groups|%s_%d", groupActionView, g.ID),
+		}})
+	}
+
+	if totalPages > 1 {
+		var row []gotgbot.InlineKeyboardButton
+
+		if page > 0 {
+			row = append(row, gotgbot.InlineKeyboardButton{Text: "« Prev", CallbackData: fmt.Sprintf("groups|%s_%d", groupActionList, page-1)})
+		}
+
+		row = append(row, gotgbot.InlineKeyboardButton{Text: fmt.Sprintf("%d/%d", page+1, totalPages), CallbackData: "ignore"})
+
+		if page < totalPages-1 {
+			row = append(row, gotgbot.InlineKeyboardButton{Text: "Next »", CallbackData: fmt.Sprintf("groups|%s_%d", groupActionList, page+1)})
+		}
+
+		buttons = append(buttons, row)
+	}
+
+	return text, append(buttons, []gotgbot.InlineKeyboardButton{{Text: "✖️ Close", CallbackData: "close"}}), nil
+}
+
+// CbGroups handles the callback from the group list and management buttons.
+// Structure: groups|<action>_<group id or page>
+func CbGroups(bot *gotgbot.Bot, ctx *ext.Context) error {
+	if !_app.AuthAdmin(ctx) {
+		return nil
+	}
+
+	c := ctx.CallbackQuery
+
+	d := callbackdata.FromString(c.Data)
+	if d.LenArgs() < 2 {
+		c.Answer(bot, &gotgbot.AnswerCallbackQueryOpts{Text: "Not enough arguments in callback button", ShowAlert: true})
+		_app.Log.Warn("cbgroups: no arguments in callback", zap.String("data", c.Data), zap.Strings("args", d.Args))
+
+		return nil
+	}
+
+	action := d.Args[0]
+
+	id, err := strconv.ParseInt(d.Args[1], 10, 64)
+	if err != nil {
+		c.Answer(bot, &gotgbot.AnswerCallbackQueryOpts{Text: "Bad callback data", ShowAlert: true})
+		return nil
+	}
+
+	var (
+		text    string
+		buttons [][]gotgbot.InlineKeyboardButton
+	)
+
+	if action == groupActionList {
+		text, buttons, err = groupsPage(int(id))
+		if err != nil {
+			_app.Log.Warn("cbgroups: get groups failed", zap.Error(err))
+			c.Answer(bot, &gotgbot.AnswerCallbackQueryOpts{Text: "Failed to fetch groups: " + err.Error(), ShowAlert: true})
+
+			return nil
+		}
+	} else {
+		g, err := _app.GetGroup(id)
+		if err != nil || g == nil {
+			c.Answer(bot, &gotgbot.AnswerCallbackQueryOpts{Text: "Group Not Found!", ShowAlert: true})
+			return nil
+		}
+
+		var status string
+
+		switch action {
+		case groupActionApprove:
+			status = model.GroupStatusApproved
+		case groupActionLeave:
+			status = model.GroupStatusLeft
+		case groupActionBan:
+			status = model.GroupStatusBanned
+		case groupActionUnban:
+			status = model.GroupStatusLeft
+		}
+
+		if status != "" {
+			g, err = _app.setGroupStatus(g, status)
+			if err != nil {
+				_app.Log.Warn("cbgroups: update group status failed", zap.Error(err), zap.Int64("group", g.ID))
+				c.Answer(bot, &gotgbot.AnswerCallbackQueryOpts{Text: "Failed to update group: " + err.Error(), ShowAlert: true})
+
+				return nil
+			}
+
+			// the status is saved first so the update sent when the bot leaves doesn't overwrite a ban
+			if action == groupActionLeave || action == groupActionBan {
+				leaveGroup(bot, g.ID)
+			}
+
+			c.Answer(bot, &gotgbot.AnswerCallbackQueryOpts{Text: "Group Status Changed to " + strings.ToUpper(status[:1]) + status[1:]})
+		}
+
+		text, buttons = "<b>👥 Group Details</b>\n\n"+groupDetails(g), groupButtons(g)
+	}
+
+	_, _, err = c.Message.EditText(bot, text, &gotgbot.EditMessageTextOpts{
+		ParseMode:   gotgbot.ParseModeHTML,
+		ReplyMarkup: gotgbot.InlineKeyboardMarkup{InlineKeyboard: buttons},
+	})
+	if err != nil {
+		_app.Log.Debug("cbgroups: edit message failed", zap.Error(err))
+	}
+
+	return nil
+}
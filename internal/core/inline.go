@@ -0,0 +1,134 @@
+package core
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/Jisin0/autofilterbot/internal/autofilter"
+	"github.com/Jisin0/autofilterbot/internal/database"
+	"github.com/Jisin0/autofilterbot/internal/fsub"
+	"github.com/Jisin0/autofilterbot/internal/functions"
+	"github.com/Jisin0/autofilterbot/internal/model"
+	"github.com/PaulSonOfLars/gotgbot/v2"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext"
+	"go.uber.org/zap"
+)
+
+const (
+	// inlineResultsLimit is the number of results sent in a single answer, telegram allows upto 50.
+	inlineResultsLimit = 50
+	// inlineCacheTime is the time in seconds for which telegram caches the results of a query for a user.
+	inlineCacheTime = 30
+)
+
+// InlineSearch handles inline queries by searching for files and answering with the cached files.
+// The offset of the query is the number of results already sent.
+func InlineSearch(bot *gotgbot.Bot, ctx *ext.Context) error {
+	q := ctx.InlineQuery
+
+	query := autofilter.ParseQuery(q.Query)
+	if query.Text == "" {
+		_, err := q.Answer(bot, nil, &gotgbot.AnswerInlineQueryOpts{CacheTime: inlineCacheTime, IsPersonal: true})
+		return err
+	}
+
+	ok, err := fsub.CheckFsub(_app, bot, ctx)
+	if err != nil {
+		_app.Log.Warn("inline: check fsub failed", zap.Error(err))
+	}
+
+	if !ok {
+		return nil
+	}
+
+	offset, _ := strconv.Atoi(q.Offset)
+
+	cursor, err := _app.DB.SearchFiles(query.Text, database.SearchFilesOpts{
+		Mode:     _app.Config.GetSearchMode(),
+		Filter:   &query.Filter,
+		Skip:     offset,
+		Limit:    inlineResultsLimit,
+		Channels: _app.Config.GetSearchChatIds(),
+	})
+	if err != nil {
+		_app.Log.Warn("inline: search files failed", zap.Error(err), zap.String("query", q.Query))
+		return nil
+	}
+
+	defer cursor.Close(context.Background())
+
+	var (
+		results = make([]gotgbot.InlineQueryResult, 0, inlineResultsLimit)
+		// files that can't be sent are skipped but still count towards the offset
+		fetched int
+	)
+
+	for cursor.Next(context.Background()) {
+		fetched++
+
+		var f model.File
+
+		if err := cursor.Decode(&f); err != nil {
+			_app.Log.Warn("inline: decode file failed", zap.Error(err))
+			continue
+		}
+
+		if r := inlineResult(ctx, &f); r != nil {
+			results = append(results, r)
+		}
+	}
+
+	var nextOffset string
+	if fetched == inlineResultsLimit {
+		nextOffset = strconv.Itoa(offset + fetched)
+	}
+
+	_, err = q.Answer(bot, results, &gotgbot.AnswerInlineQueryOpts{
+		CacheTime:  inlineCacheTime,
+		IsPersonal: true,
+		NextOffset: nextOffset,
+	})
+	if err != nil {
+		_app.Log.Warn("inline: answer query failed", zap.Error(err), zap.String("query", q.Query))
+	}
+
+	return nil
+}
+
+// inlineResult returns the cached inline result of a file or nil if the type of file is not supported.
+func inlineResult(ctx *ext.Context, f *model.File) gotgbot.InlineQueryResult {
+	var (
+		size    = functions.FileSizeToString(f.FileSize)
+		caption = _app.FormatText(ctx, _app.Config.GetFileCaption(), map[string]any{
+			"file_size": size,
+			"file_name": f.FileName,
+			"warn":      "",
+		})
+	)
+
+	switch f.FileType {
+	case model.FileTypeDocument:
+		return gotgbot.InlineQueryResultCachedDocument{Id: f.UniqueId, Title: f.FileName, DocumentFileId: f.FileId, Description: size, Caption: caption, ParseMode: gotgbot.ParseModeHTML}
+	case model.FileTypeVideo:
+		return gotgbot.InlineQueryResultCachedVideo{Id: f.UniqueId, Title: f.FileName, VideoFileId: f.FileId, Description: size, Caption: caption, ParseMode: gotgbot.ParseModeHTML}
+	case model.FileTypeAudio:
+		return gotgbot.InlineQueryResultCachedAudio{Id: f.UniqueId, AudioFileId: f.FileId, Caption: caption, ParseMode: gotgbot.ParseModeHTML}
+	case model.FileTypeVoice:
+		return gotgbot.InlineQueryResultCachedVoice{Id: f.UniqueId, Title: f.FileName, VoiceFileId: f.FileId, Caption: caption, ParseMode: gotgbot.ParseModeHTML}
+	default:
+		return nil
+	}
+}
+
+// ChosenInlineResult counts a download of the file chosen from inline results.
+// Inline feedback must be enabled from @BotFather for these updates to be received.
+func ChosenInlineResult(bot *gotgbot.Bot, ctx *ext.Context) error {
+	r := ctx.ChosenInlineResult
+
+	err := _app.DB.IncrementDownloads(r.ResultId)
+	if err != nil {
+		_app.Log.Warn("inline: increment downloads failed", zap.Error(err), zap.String("file", r.ResultId))
+	}
+
+	return nil
+}
@@ -0,0 +1,100 @@
+package core
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"os"
+	"time"
+
+	"github.com/Jisin0/autofilterbot/internal/config"
+	"github.com/Jisin0/autofilterbot/internal/database"
+	"github.com/Jisin0/autofilterbot/pkg/deeplink"
+	"github.com/PaulSonOfLars/gotgbot/v2"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext"
+	"go.uber.org/zap"
+)
+
+const (
+	// linkSecretSize is the number of random bytes in a generated link secret.
+	linkSecretSize = 32
+	// shortIdLength is the number of characters in the id of a batch or collection.
+	shortIdLength = 8
+	// shortIdChars are the characters used in the id of a batch or collection.
+	shortIdChars = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+)
+
+// errLegacyLink is returned for unsigned start data if old links are blocked.
+var errLegacyLink = errors.New("legacy links are blocked")
+
+// newLinkSigner creates the signer of deep links using the LINK_SECRET variable or the secret saved in the config.
+// A random secret is generated and saved if neither is set.
+func newLinkSigner(db database.Database, c *config.Config) (*deeplink.Signer, error) {
+	if s := os.Getenv("LINK_SECRET"); s != "" {
+		return deeplink.NewSigner([]byte(s)), nil
+	}
+
+	if c.LinkSecret != "" {
+		return deeplink.NewSigner([]byte(c.LinkSecret)), nil
+	}
+
+	b := make([]byte, linkSecretSize)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+
+	c.LinkSecret = hex.EncodeToString(b)
+
+	err := db.UpdateConfig(c.BotId, config.FieldNameLinkSecret, c.LinkSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	return deeplink.NewSigner([]byte(c.LinkSecret)), nil
+}
+
+// newShortId returns a random id for a new batch or collection, used in their links.
+func newShortId() (string, error) {
+	b := make([]byte, shortIdLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	for i := range b {
+		b[i] = shortIdChars[int(b[i])%len(shortIdChars)]
+	}
+
+	return string(b), nil
+}
+
+// linkOptions adds signing of file links to the config of a chat.
+type linkOptions struct {
+	*config.Config
+}
+
+// SignStartData signs the payload of a file link, the link expires after the link expiry time if one is set.
+func (o linkOptions) SignStartData(payload []byte) string {
+	var expiry time.Time
+	if h := o.GetLinkExpiry(); h != 0 {
+		expiry = time.Now().Add(time.Duration(h) * time.Hour)
+	}
+
+	return _app.Links.Sign(payload, expiry)
+}
+
+// resultOptions returns the options used to create result buttons in a chat.
+func resultOptions(chatId int64) linkOptions {
+	return linkOptions{_app.ChatConfig(chatId)}
+}
+
+// invalidLink replies to a start message with a link that failed verification.
+func invalidLink(bot *gotgbot.Bot, ctx *ext.Context, err error) {
+	_app.Log.Debug("start: invalid link", zap.Error(err), zap.String("text", ctx.Message.Text))
+
+	text := "<i>📛 This Link is Invalid, Please Get a New Link from the Chat Where You Found It :/</i>"
+	if errors.Is(err, deeplink.ErrExpired) {
+		text = "<i>⌛ This Link Has Expired, Please Get a New Link from the Chat Where You Found It !</i>"
+	}
+
+	ctx.Message.Reply(bot, text, &gotgbot.SendMessageOpts{ParseMode: gotgbot.ParseModeHTML})
+}
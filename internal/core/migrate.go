@@ -0,0 +1,238 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Jisin0/autofilterbot/internal/database"
+	"github.com/Jisin0/autofilterbot/internal/functions"
+	"github.com/Jisin0/autofilterbot/internal/migrate"
+	"github.com/Jisin0/autofilterbot/internal/model"
+	"github.com/Jisin0/autofilterbot/pkg/callbackdata"
+	"github.com/Jisin0/autofilterbot/pkg/conversation"
+	"github.com/PaulSonOfLars/gotgbot/v2"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext"
+	"go.uber.org/zap"
+)
+
+const migrateUsage = `<b><u>Migrate Files</u></b>
+
+<code>/migrate 0 1</code> - Move all files from database 0 to 1.
+<code>/migrate rebalance</code> - Spread files evenly across all databases by number of files.
+<code>/migrate rebalance size</code> - Spread files evenly by storage size.
+
+<b>Databases</b>:
+%s`
+
+// CmdMigrate handles the /migrate command which creates an operation to move files between databases.
+func CmdMigrate(bot *gotgbot.Bot, ctx *ext.Context) error {
+	if !_app.AuthAdmin(ctx) {
+		return nil
+	}
+
+	m := ctx.Message
+
+	db, ok := _app.DB.(migrate.Database)
+	if !ok {
+		m.Reply(bot, fmt.Sprintf("Migrations are not supported by the %s database.", _app.DB.GetName()), nil)
+		return nil
+	}
+
+	stats, err := db.StorageStats(_app.Ctx)
+	if err != nil {
+		_app.Log.Warn("cmdmigrate: failed to fetch storage stats", zap.Error(err))
+		m.Reply(bot, "Failed to fetch database stats: "+err.Error(), nil)
+
+		return nil
+	}
+
+	var steps []model.MigrationStep
+
+	args := strings.Fields(strings.ToLower(m.Text))[1:]
+
+	switch {
+	case len(args) != 0 && args[0] == "rebalance":
+		steps = migrate.Rebalance(stats, len(args) > 1 && args[1] == "size")
+		if len(steps) == 0 {
+			m.Reply(bot, "Files are already balanced, nothing to move 🎉", nil)
+			return nil
+		}
+	case len(args) == 2:
+		from, errFrom := strconv.Atoi(args[0])
+		to, errTo := strconv.Atoi(args[1])
+
+		if errFrom != nil || errTo != nil || from == to || from < 0 || to < 0 || from >= len(stats) || to >= len(stats) {
+			m.Reply(bot, fmt.Sprintf("Invalid databases, please use two different numbers from 0 to %d.", len(stats)-1), nil)
+			return nil
+		}
+
+		steps = []model.MigrationStep{{From: from, To: to}}
+	default:
+		m.Reply(bot, fmt.Sprintf(migrateUsage, storageStatsText(stats)), &gotgbot.SendMessageOpts{ParseMode: gotgbot.ParseModeHTML})
+		return nil
+	}
+
+	mig := model.Migration{
+		ID:                    functions.RandString(6),
+		Steps:                 steps,
+		Total:                 migrate.TotalFiles(steps, stats),
+		ProgressMessageChatID: m.Chat.Id,
+		IsPaused:              true, // incase app restarts before user starts it
+	}
+
+	err = db.NewMigration(&mig)
+	if err != nil {
+		_app.Log.Error("cmdmigrate: failed to insert migration to db", zap.Error(err))
+		m.Reply(bot, "Failed to create db entry: "+err.Error(), nil)
+
+		return nil
+	}
+
+	text := fmt.Sprintf("<b><u>Migration Overview</u></b>\n\n%s\n<b>Databases</b>:\n%s", migrate.Progress(&mig), storageStatsText(stats))
+
+	_, err = m.Reply(bot, text, &gotgbot.SendMessageOpts{
+		ParseMode:   gotgbot.ParseModeHTML,
+		ReplyMarkup: gotgbot.InlineKeyboardMarkup{InlineKeyboard: [][]gotgbot.InlineKeyboardButton{{mig.CancelButton(), mig.StartButton()}}},
+	})
+	if err != nil {
+		_app.Log.Warn("cmdmigrate: failed to send overview message", zap.Error(err))
+	}
+
+	return nil
+}
+
+// CbMigrate handles the callback from migration management buttons to start, pause or cancel it.
+// Strucuture: migrate|<pid>_<operation>
+func CbMigrate(bot *gotgbot.Bot, ctx *ext.Context) error {
+	if !_app.AuthAdmin(ctx) {
+		return nil
+	}
+
+	c := ctx.CallbackQuery
+
+	d := callbackdata.FromString(c.Data)
+	if d.LenArgs() < 2 {
+		c.Answer(bot, &gotgbot.AnswerCallbackQueryOpts{Text: "Not enough arguments in callback button", ShowAlert: true})
+		_app.Log.Warn("cbmigrate: no arguments in callback", zap.String("data", c.Data), zap.Strings("args", d.Args))
+
+		return nil
+	}
+
+	db, ok := _app.DB.(migrate.Database)
+	if !ok {
+		c.Answer(bot, &gotgbot.AnswerCallbackQueryOpts{Text: "Migrations are not supported by this database!", ShowAlert: true})
+		return nil
+	}
+
+	pid := d.Args[0]
+
+	switch d.Args[1] {
+	case model.MigrationCharCancel:
+		conv := conversation.NewConversatorFromUpdate(bot, ctx.Update)
+
+		confirmMessage, err := conv.Ask(
+			_app.Ctx,
+			fmt.Sprintf("⚠️ Are you sure you want to cancel this migration? Files already moved will stay in their new database. Plase send the process id <code>%s</code> to confirm: ", pid),
+			&gotgbot.SendMessageOpts{ParseMode: gotgbot.ParseModeHTML},
+		)
+		if err != nil {
+			_app.Log.Warn("cbmigrate: cancel: failed to send confirmation query message", zap.Error(err))
+			return nil
+		}
+
+		if strings.TrimSpace(confirmMessage.Text) != pid {
+			confirmMessage.Reply(bot, "❗ Operation pid does not match. Cancel Failed.", nil)
+			return nil
+		}
+
+		_app.MigrationManager.CancelOperation(pid)
+
+		err = db.DeleteOperation(pid)
+		if err != nil {
+			_app.Log.Warn("cbmigrate: cancel: failed to delete operation from db", zap.Error(err), zap.String("pid", pid))
+			confirmMessage.Reply(bot, fmt.Sprintf("An error occurred while trying to delete operation: %v", err), nil)
+
+			return nil
+		}
+
+		confirmMessage.Reply(bot, "✅ Migration Cancelled Successfully!", nil)
+	case model.MigrationCharPause:
+		if !_app.MigrationManager.CancelOperation(pid) {
+			_app.Log.Warn("cbmigrate: pause: operation is not currently active", zap.String("pid", pid)) // logs and still sets is_paused to true
+		}
+
+		ok, err := db.UpdateMigration(pid, map[string]any{"is_paused": true})
+		if !ok {
+			c.Answer(bot, &gotgbot.AnswerCallbackQueryOpts{Text: "Operation not found in database!\nMay have ended or been cancelled.", ShowAlert: true})
+			return nil
+		} else if err != nil {
+			_app.Log.Error("cbmigrate: pause: failed to set db paused status", zap.Error(err), zap.String("pid", pid))
+			c.Answer(bot, &gotgbot.AnswerCallbackQueryOpts{Text: "Setting DB status to paused failed, please check logs!", ShowAlert: true})
+
+			return nil
+		}
+
+		c.Answer(bot, &gotgbot.AnswerCallbackQueryOpts{Text: "Migration Will Pause Shortly 🎉"})
+	case model.MigrationCharStart:
+		_app.MigrationManager.CancelOperation(pid) // cancel active operation if applicable
+
+		mig, err := db.GetMigration(pid)
+		if database.IsNoDocumentsError(err) {
+			c.Answer(bot, &gotgbot.AnswerCallbackQueryOpts{Text: "Operation Not Found!\nOperation may be completed or cancelled.", ShowAlert: true})
+			return nil
+		} else if err != nil {
+			_app.Log.Error("cbmigrate: start: failed to fetch operation", zap.Error(err), zap.String("pid", pid))
+			return nil
+		}
+
+		_, err = db.UpdateMigration(pid, map[string]interface{}{"is_paused": false}) // ensure operation resumes at restart
+		if err != nil {
+			_app.Log.Error("cbmigrate: start: failed to set db paused status", zap.Error(err), zap.String("pid", pid))
+		}
+
+		operationCtx, operation := _app.MigrationManager.NewOperation(_app.Ctx, mig, db, _app.Log, bot)
+		_app.MigrationManager.RunOperation(operationCtx, operation)
+
+		c.Answer(bot, &gotgbot.AnswerCallbackQueryOpts{Text: "Starting Migration..."})
+	}
+
+	return nil
+}
+
+// RestartActiveMigrations resumes all migrations that were running when the app stopped.
+func (c *Core) RestartActiveMigrations(ctx context.Context) {
+	db, ok := c.DB.(migrate.Database)
+	if !ok {
+		return
+	}
+
+	ops, err := db.GetActiveMigrations()
+	if err != nil {
+		c.Log.Debug("core: failed to fetch active migrations", zap.Error(err))
+		return
+	}
+
+	if len(ops) == 0 {
+		return
+	}
+
+	c.Log.Debug("core: restarting active migrations", zap.Int("num", len(ops)))
+
+	for _, m := range ops {
+		ctx, o := c.MigrationManager.NewOperation(ctx, m, db, c.Log, c.Bot)
+		c.MigrationManager.RunOperation(ctx, o)
+	}
+}
+
+// storageStatsText lists the number of files and size of each storage.
+func storageStatsText(stats []database.StorageStats) string {
+	var b strings.Builder
+
+	for i, s := range stats {
+		fmt.Fprintf(&b, "<code>%d</code> : %d files, %s\n", i, s.Files, functions.FileSizeToString(s.Size))
+	}
+
+	return b.String()
+}
@@ -3,6 +3,7 @@ package core
 import (
 	"strconv"
 
+	"github.com/Jisin0/autofilterbot/internal/autofilter"
 	"github.com/Jisin0/autofilterbot/pkg/callbackdata"
 	"github.com/PaulSonOfLars/gotgbot/v2"
 	"github.com/PaulSonOfLars/gotgbot/v2/ext"
@@ -10,6 +11,7 @@ import (
 )
 
 // Navigate handles the navg callback query from autofilter results for pagination.
+// An optional third argument is the index of a group in the page whose files should be shown.
 func Navigate(bot *gotgbot.Bot, ctx *ext.Context) error {
 	c := ctx.CallbackQuery
 
@@ -43,24 +45,54 @@ func Navigate(bot *gotgbot.Bot, ctx *ext.Context) error {
 		return nil
 	}
 
+	loaded, err := loadPages(r, pageIndex)
+	if err != nil {
+		_app.Log.Warn("navg: load pages failed", zap.Error(err), zap.String("unique_id", r.UniqueId))
+	}
+
+	if loaded {
+		err = _app.Cache.Autofilter.Save(r)
+		if err != nil {
+			_app.Log.Warn("navg: save result failed", zap.Error(err), zap.String("unique_id", r.UniqueId))
+		}
+	}
+
 	files := r.Files
 
+	if pageIndex == len(files) && r.Complete {
+		c.Answer(bot, &gotgbot.AnswerCallbackQueryOpts{Text: "That's All! There Are No More Results.", ShowAlert: true})
+		return nil
+	}
+
 	if pageIndex > len(files)-1 {
 		c.Answer(bot, &gotgbot.AnswerCallbackQueryOpts{Text: "404: Result Page Not Found", ShowAlert: true})
 		_app.Log.Warn("navg: result page not found", zap.String("unique_id", r.UniqueId), zap.Int("index", pageIndex))
 		return nil
 	}
 
-	pageFiles := files[pageIndex]
-
 	var (
-		buttons = make([][]gotgbot.InlineKeyboardButton, 0, len(pageFiles)+2)
+		buttons [][]gotgbot.InlineKeyboardButton
 		chatId  = c.Message.GetChat().Id
 	)
 
-	buttons = append(buttons, headerRow(r.UniqueId, pageIndex))
-	buttons = append(buttons, pageFiles.Process(chatId, bot.Username, _app.Config)...)
-	buttons = append(buttons, footerRow(r.UniqueId, pageIndex, len(files)))
+	if len(data.Args) > 2 {
+		groups := autofilter.GroupFiles(files[pageIndex])
+
+		groupIndex, err := strconv.Atoi(data.Args[2])
+		if err != nil || groupIndex < 0 || groupIndex > len(groups)-1 {
+			c.Answer(bot, &gotgbot.AnswerCallbackQueryOpts{Text: "404: Result Group Not Found", ShowAlert: true})
+			_app.Log.Warn("navg: result group not found", zap.String("unique_id", r.UniqueId), zap.String("index", data.Args[2]))
+			return nil
+		}
+
+		g := groups[groupIndex]
+
+		buttons = append(buttons, []gotgbot.InlineKeyboardButton{{Text: "📁 " + g.Label(), CallbackData: "ignore"}})
+		buttons = append(buttons, g.GroupMenu(chatId, bot.Username, resultOptions(chatId))...)
+		buttons = append(buttons, []gotgbot.InlineKeyboardButton{backButton(r.UniqueId, pageIndex)})
+	} else {
+		buttons = resultButtons(r, pageIndex, chatId, bot.Username)
+	}
 
 	_, _, err = c.Message.EditReplyMarkup(bot, &gotgbot.EditMessageReplyMarkupOpts{
 		ChatId:    chatId,
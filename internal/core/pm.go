@@ -0,0 +1,127 @@
+package core
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/Jisin0/autofilterbot/internal/autofilter"
+	"github.com/Jisin0/autofilterbot/internal/fsub"
+	"github.com/Jisin0/autofilterbot/pkg/conversation"
+	"github.com/Jisin0/autofilterbot/pkg/ratelimit"
+	"github.com/PaulSonOfLars/gotgbot/v2"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext"
+	"go.uber.org/zap"
+)
+
+// pmSearchLimiter limits the number of queries a user can search for in the bot's private chat every minute.
+var pmSearchLimiter = ratelimit.New(time.Minute)
+
+// pmSearchFilter matches text messages in private chats that are not commands.
+func pmSearchFilter(m *gotgbot.Message) bool {
+	return m.Chat.Type == gotgbot.ChatTypePrivate && m.Text != "" && !strings.HasPrefix(m.Text, "/")
+}
+
+// PMAutofilter answers queries sent in the bot's private chat if pm search is enabled.
+func PMAutofilter(bot *gotgbot.Bot, ctx *ext.Context) error {
+	m := ctx.Message
+
+	if !_app.Config.GetPMSearch() || m.From == nil {
+		return nil
+	}
+
+	// the message is an answer to a question asked by the bot
+	if conversation.ActiveListeners.HasMatch(m) {
+		return nil
+	}
+
+	if !allowPMSearch(bot, ctx) {
+		return nil
+	}
+
+	return Autofilter(bot, ctx)
+}
+
+// searchStart sends the results of a query from search start data in the bot's private chat.
+func searchStart(bot *gotgbot.Bot, ctx *ext.Context, query string) error {
+	m := ctx.Message
+
+	q := autofilter.ParseQuery(query)
+	if !_app.Config.GetPMSearch() || q.Text == "" {
+		return StaticCommands(bot, ctx)
+	}
+
+	if !allowPMSearch(bot, ctx) {
+		return nil
+	}
+
+	msg, err := sendResults(bot, ctx, m, m.From, q.Text, q.Filter)
+	if err != nil {
+		_app.Log.Warn("start: search failed", zap.Error(err), zap.String("query", query))
+	}
+
+	autodeleteResult(msg)
+
+	return nil
+}
+
+// allowPMSearch reports whether the user is allowed to search in the bot's private chat.
+// The user is sent a message if they are searching too fast or have not joined the force subscribe channels.
+func allowPMSearch(bot *gotgbot.Bot, ctx *ext.Context) bool {
+	m := ctx.Message
+
+	ok, wait := pmSearchLimiter.Allow(m.From.Id, _app.Config.GetPMSearchLimit())
+	if !ok {
+		text := fmt.Sprintf("<i>⏳ You're Searching Too Fast! Please Try Again in %d Seconds.</i>", int(math.Ceil(wait.Seconds())))
+
+		_, err := m.Reply(bot, text, &gotgbot.SendMessageOpts{ParseMode: gotgbot.ParseModeHTML})
+		if err != nil {
+			_app.Log.Warn("pm: send rate limit message failed", zap.Error(err))
+		}
+
+		return false
+	}
+
+	ok, err := fsub.CheckFsub(_app, bot, ctx)
+	if err != nil {
+		_app.Log.Warn("pm: check fsub failed", zap.Error(err))
+	}
+
+	return ok
+}
+
+// SendFile handles the callback from file buttons of results in the bot's private chat by sending the file directly.
+func SendFile(bot *gotgbot.Bot, ctx *ext.Context) error {
+	c := ctx.CallbackQuery
+
+	ok, err := fsub.CheckFsub(_app, bot, ctx)
+	if err != nil {
+		_app.Log.Warn("getf: check fsub failed", zap.Error(err))
+	}
+
+	if !ok {
+		c.Answer(bot, nil)
+		return nil
+	}
+
+	f, err := _app.DB.GetFile(strings.TrimPrefix(c.Data, autofilter.SendFileDataPrefix))
+	if err != nil {
+		_app.Log.Warn("getf: get file failed", zap.Error(err))
+		c.Answer(bot, &gotgbot.AnswerCallbackQueryOpts{Text: "📛 I Couldn't Find This File, Please Report This to Admins :/", ShowAlert: true})
+
+		return nil
+	}
+
+	err = sendFile(bot, ctx, c.From.Id, f)
+	if err != nil {
+		_app.Log.Warn("getf: send file failed", zap.Error(err), zap.String("file_id", f.FileId))
+		c.Answer(bot, &gotgbot.AnswerCallbackQueryOpts{Text: "Sorry An Error occurred :/", ShowAlert: true})
+
+		return nil
+	}
+
+	c.Answer(bot, nil)
+
+	return nil
+}
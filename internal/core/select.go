@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strconv"
 
+	"github.com/Jisin0/autofilterbot/internal/autofilter"
 	"github.com/Jisin0/autofilterbot/internal/fsub"
 	"github.com/Jisin0/autofilterbot/internal/functions"
 	"github.com/Jisin0/autofilterbot/pkg/callbackdata"
@@ -55,6 +56,10 @@ func Select(bot *gotgbot.Bot, ctx *ext.Context) error {
 		return err
 	}
 
+	if _, err := loadPages(r, pageIndex); err != nil {
+		_app.Log.Warn("select: load pages failed", zap.Error(err), zap.String("unique_id", r.UniqueId))
+	}
+
 	if pageIndex >= len(r.Files) {
 		_app.Log.Warn("select: page not found", zap.Int("length", len(r.Files)), zap.Int("index", pageIndex))
 
@@ -63,7 +68,7 @@ func Select(bot *gotgbot.Bot, ctx *ext.Context) error {
 		return err
 	}
 
-	ok, err = fsub.CheckFsub(_app, bot, ctx)
+	ok, err = fsub.CheckChannels(_app, bot, ctx, _app.ChatConfig(c.Message.GetChat().Id).GetFsubChannels())
 	if err != nil {
 		if functions.IsChatNotFoundErr(err) { // user has not started bot or blocked
 			// redirect to dm for a retry msg
@@ -93,17 +98,8 @@ func Select(bot *gotgbot.Bot, ctx *ext.Context) error {
 		r.SelectFile(pageIndex, data.Args[2])
 	}
 
-	var (
-		pageFiles = r.Files[pageIndex]
-		buttons   = make([][]gotgbot.InlineKeyboardButton, 0, len(pageFiles)+2)
-	)
-
-	buttons = append(buttons, selectHeaderRow(r.UniqueId, pageIndex))
-	buttons = append(buttons, pageFiles.SelectMenu(uniqueId, pageIndex)...)
-	buttons = append(buttons, selectFooterRow(r.UniqueId, pageIndex, len(r.Files)))
-
 	_, _, err = c.Message.EditReplyMarkup(bot, &gotgbot.EditMessageReplyMarkupOpts{
-		ReplyMarkup: gotgbot.InlineKeyboardMarkup{InlineKeyboard: buttons},
+		ReplyMarkup: gotgbot.InlineKeyboardMarkup{InlineKeyboard: selectMenu(r, pageIndex, c.From.Id)},
 	})
 	if err != nil {
 		_app.Log.Warn("select: edit markup failed", zap.Error(err))
@@ -117,19 +113,41 @@ func Select(bot *gotgbot.Bot, ctx *ext.Context) error {
 	return nil
 }
 
-func selectHeaderRow(uniqueId string, pageIndex int) []gotgbot.InlineKeyboardButton {
-	return []gotgbot.InlineKeyboardButton{{Text: "ᴇxɪᴛ", CallbackData: fmt.Sprintf("navg|%s_%d", uniqueId, pageIndex)}, {Text: "sᴇɴᴅ ➡️", CallbackData: fmt.Sprintf("sendsel|%s", uniqueId)}}
+// selectMenu returns the buttons of a page of the select menu of a result.
+func selectMenu(r *autofilter.SearchResult, pageIndex int, userId int64) [][]gotgbot.InlineKeyboardButton {
+	var (
+		pageFiles = r.Files[pageIndex]
+		buttons   = make([][]gotgbot.InlineKeyboardButton, 0, len(pageFiles)+4)
+	)
+
+	buttons = append(buttons, selectHeaderRow(r.UniqueId, pageIndex, len(r.Selected())))
+	buttons = append(buttons, selectActionRow(r.UniqueId, pageIndex))
+	if containsI64(_app.Admins, userId) {
+		buttons = append(buttons, []gotgbot.InlineKeyboardButton{{Text: "➕ ᴀᴅᴅ ᴛᴏ ᴄᴏʟʟᴇᴄᴛɪᴏɴ", CallbackData: "coladd|" + r.UniqueId}})
+	}
+	buttons = append(buttons, pageFiles.SelectMenu(r.UniqueId, pageIndex)...)
+	buttons = append(buttons, selectFooterRow(r.UniqueId, pageIndex, len(r.Files), r.HasMore()))
+
+	return buttons
+}
+
+func selectHeaderRow(uniqueId string, pageIndex, selected int) []gotgbot.InlineKeyboardButton {
+	return []gotgbot.InlineKeyboardButton{{Text: "ᴇxɪᴛ", CallbackData: fmt.Sprintf("navg|%s_%d", uniqueId, pageIndex)}, {Text: fmt.Sprintf("sᴇɴᴅ (%d) ➡️", selected), CallbackData: fmt.Sprintf("sendsel|%s", uniqueId)}}
+}
+
+func selectActionRow(uniqueId string, pageIndex int) []gotgbot.InlineKeyboardButton {
+	return []gotgbot.InlineKeyboardButton{{Text: "✖️ ᴄʟᴇᴀʀ", CallbackData: fmt.Sprintf("clrsel|%s_%d", uniqueId, pageIndex)}, {Text: "🔗 ʟɪɴᴋ", CallbackData: fmt.Sprintf("linksel|%s", uniqueId)}}
 }
 
-func selectFooterRow(uniqueId string, pageIndex, totalPages int) []gotgbot.InlineKeyboardButton {
+func selectFooterRow(uniqueId string, pageIndex, totalPages int, hasMore bool) []gotgbot.InlineKeyboardButton {
 	btns := make([]gotgbot.InlineKeyboardButton, 0, 3)
 	if pageIndex != 0 {
 		btns = append(btns, selectBackButton(uniqueId, pageIndex-1))
 	}
 
-	btns = append(btns, gotgbot.InlineKeyboardButton{Text: fmt.Sprintf("📑 𝗣𝗔𝗚𝗘 %d/%d", pageIndex+1, totalPages), CallbackData: "ignore"})
+	btns = append(btns, gotgbot.InlineKeyboardButton{Text: fmt.Sprintf("📑 𝗣𝗔𝗚𝗘 %d/%s", pageIndex+1, pageCount(totalPages, hasMore)), CallbackData: "ignore"})
 
-	if pageIndex+1 != totalPages {
+	if pageIndex+1 < totalPages || hasMore {
 		btns = append(btns, selectNextButton(uniqueId, pageIndex+1))
 	}
 
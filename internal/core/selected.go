@@ -0,0 +1,253 @@
+package core
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/Jisin0/autofilterbot/internal/autofilter"
+	"github.com/Jisin0/autofilterbot/internal/cache"
+	"github.com/Jisin0/autofilterbot/internal/fsub"
+	"github.com/Jisin0/autofilterbot/internal/functions"
+	"github.com/Jisin0/autofilterbot/pkg/callbackdata"
+	"github.com/PaulSonOfLars/gotgbot/v2"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext"
+	"go.uber.org/zap"
+)
+
+// BundleDataPrefix is the prefix of start data that sends files selected from a result, followed by the id of the bundle.
+const BundleDataPrefix = "m_"
+
+// selectedResult fetches the result of a callback from the select menu and checks that it was started by the user.
+// The query is answered and ok is false if the result can't be used.
+func selectedResult(bot *gotgbot.Bot, c *gotgbot.CallbackQuery, logPrefix string) (*autofilter.SearchResult, callbackdata.CallbackData, bool) {
+	data := callbackdata.FromString(c.Data)
+	if len(data.Args) < 1 {
+		_app.Log.Warn(logPrefix+": not enough args", zap.Strings("args", data.Args))
+		c.Answer(bot, &gotgbot.AnswerCallbackQueryOpts{Text: "Error: Not Enough Arguments", ShowAlert: true})
+
+		return nil, data, false
+	}
+
+	r, ok, err := _app.Cache.Autofilter.Get(data.Args[0])
+	if !ok {
+		c.Answer(bot, &gotgbot.AnswerCallbackQueryOpts{Text: "Search Result Has Expired!\nPlease Try Again...", ShowAlert: true})
+		return nil, data, false
+	}
+
+	if err != nil {
+		_app.Log.Warn(logPrefix+": get result cache failed", zap.Error(err))
+		c.Answer(bot, &gotgbot.AnswerCallbackQueryOpts{Text: "Sorry An Error occurred :/", ShowAlert: true})
+
+		return nil, data, false
+	}
+
+	if r.FromUser != c.From.Id {
+		c.Answer(bot, &gotgbot.AnswerCallbackQueryOpts{Text: "You Can't Use This Button!", ShowAlert: true})
+		return nil, data, false
+	}
+
+	return r, data, true
+}
+
+// answerRetry redirects the user to the bot's private chat for a retry message if they haven't started the bot.
+func answerRetry(bot *gotgbot.Bot, c *gotgbot.CallbackQuery) {
+	data := &RetryData{
+		ChatId:    c.Message.GetChat().Id,
+		MessageId: c.Message.GetMessageId(),
+	}
+
+	_, err := c.Answer(bot, &gotgbot.AnswerCallbackQueryOpts{
+		Url: fmt.Sprintf("t.me/%s?start=%s", bot.Username, data.Encode()),
+	})
+	if err != nil {
+		_app.Log.Warn("select: retry answer failed", zap.Error(err))
+	}
+}
+
+// SendSelected handles the send button of the select menu which sends the selected files from all pages privately.
+// Structure: sendsel|<result unique id>
+func SendSelected(bot *gotgbot.Bot, ctx *ext.Context) error {
+	c := ctx.CallbackQuery
+
+	r, _, ok := selectedResult(bot, c, "sendsel")
+	if !ok {
+		return nil
+	}
+
+	selected := r.Selected()
+	if len(selected) == 0 {
+		c.Answer(bot, &gotgbot.AnswerCallbackQueryOpts{Text: "Select Files to Send First!", ShowAlert: true})
+		return nil
+	}
+
+	ok, err := fsub.CheckChannels(_app, bot, ctx, _app.ChatConfig(c.Message.GetChat().Id).GetFsubChannels())
+	if err != nil {
+		if functions.IsChatNotFoundErr(err) { // user has not started bot or blocked
+			answerRetry(bot, c)
+			return nil
+		}
+
+		_app.Log.Warn("sendsel: check fsub failed", zap.Error(err))
+	}
+
+	if !ok {
+		return nil
+	}
+
+	var sent int
+
+	for _, f := range selected {
+		err := sendFile(bot, ctx, c.From.Id, &f.File)
+		if err != nil {
+			if functions.IsChatNotFoundErr(err) {
+				answerRetry(bot, c)
+				return nil
+			}
+
+			_app.Log.Warn("sendsel: send file failed", zap.Error(err), zap.String("file_id", f.FileId))
+
+			continue
+		}
+
+		sent++
+	}
+
+	_, err = c.Answer(bot, &gotgbot.AnswerCallbackQueryOpts{
+		Text:      fmt.Sprintf("%d ғɪʟᴇs ʜᴀᴠᴇ ʙᴇᴇɴ sᴇɴᴛ ᴘʀɪᴠᴀᴛᴇʟʏ 🥳", sent),
+		ShowAlert: true,
+	})
+	if err != nil {
+		_app.Log.Warn("sendsel: answer query failed", zap.Error(err))
+	}
+
+	return nil
+}
+
+// ClearSelected handles the clear button of the select menu which unselects files on all pages.
+// Structure: clrsel|<result unique id>_<page index>
+func ClearSelected(bot *gotgbot.Bot, ctx *ext.Context) error {
+	c := ctx.CallbackQuery
+
+	r, data, ok := selectedResult(bot, c, "clrsel")
+	if !ok {
+		return nil
+	}
+
+	pageIndex, _ := strconv.Atoi(data.Args[len(data.Args)-1])
+	if pageIndex >= len(r.Files) {
+		pageIndex = 0
+	}
+
+	r.ClearSelection()
+
+	_, _, err := c.Message.EditReplyMarkup(bot, &gotgbot.EditMessageReplyMarkupOpts{
+		ReplyMarkup: gotgbot.InlineKeyboardMarkup{InlineKeyboard: selectMenu(r, pageIndex, c.From.Id)},
+	})
+	if err != nil {
+		_app.Log.Warn("clrsel: edit markup failed", zap.Error(err))
+	}
+
+	err = _app.Cache.Autofilter.Save(r)
+	if err != nil {
+		_app.Log.Warn("clrsel: save result failed", zap.Error(err))
+	}
+
+	c.Answer(bot, &gotgbot.AnswerCallbackQueryOpts{Text: "Selection Cleared ✔️"})
+
+	return nil
+}
+
+// LinkSelected handles the link button of the select menu which saves the selected files as a bundle and sends its link privately.
+// Structure: linksel|<result unique id>
+func LinkSelected(bot *gotgbot.Bot, ctx *ext.Context) error {
+	c := ctx.CallbackQuery
+
+	r, _, ok := selectedResult(bot, c, "linksel")
+	if !ok {
+		return nil
+	}
+
+	selected := r.Selected()
+	if len(selected) == 0 {
+		c.Answer(bot, &gotgbot.AnswerCallbackQueryOpts{Text: "Select Files to Share First!", ShowAlert: true})
+		return nil
+	}
+
+	id, err := newShortId()
+	if err != nil {
+		_app.Log.Warn("linksel: create id failed", zap.Error(err))
+		c.Answer(bot, &gotgbot.AnswerCallbackQueryOpts{Text: "Sorry An Error occurred :/", ShowAlert: true})
+
+		return nil
+	}
+
+	uniqueIds := make([]string, 0, len(selected))
+	for _, f := range selected {
+		uniqueIds = append(uniqueIds, f.UniqueId)
+	}
+
+	err = _app.Cache.Bundle.Save(id, &cache.BundleData{ChatId: r.ChatID, UniqueIds: uniqueIds})
+	if err != nil {
+		_app.Log.Warn("linksel: save bundle failed", zap.Error(err))
+		c.Answer(bot, &gotgbot.AnswerCallbackQueryOpts{Text: "Sorry An Error occurred :/", ShowAlert: true})
+
+		return nil
+	}
+
+	url := fmt.Sprintf("https://t.me/%s?start=%s%s", bot.Username, BundleDataPrefix, id)
+
+	_, err = bot.SendMessage(c.From.Id, fmt.Sprintf("<b>🔗 Here's a Link to the %d Selected Files</b>\n\n<code>%s</code>\n\n<i>The Link Expires in 24 Hours.</i>", len(uniqueIds), url), &gotgbot.SendMessageOpts{
+		ParseMode:   gotgbot.ParseModeHTML,
+		ReplyMarkup: gotgbot.InlineKeyboardMarkup{InlineKeyboard: [][]gotgbot.InlineKeyboardButton{{{Text: "𝖳𝗋𝗒 𝖭𝗈𝗐", Url: url}}}},
+	})
+	if err != nil {
+		if functions.IsChatNotFoundErr(err) {
+			answerRetry(bot, c)
+			return nil
+		}
+
+		_app.Log.Warn("linksel: send link failed", zap.Error(err))
+		c.Answer(bot, &gotgbot.AnswerCallbackQueryOpts{Text: "Sorry An Error occurred :/", ShowAlert: true})
+
+		return nil
+	}
+
+	c.Answer(bot, &gotgbot.AnswerCallbackQueryOpts{Text: "ʟɪɴᴋ ʜᴀs ʙᴇᴇɴ sᴇɴᴛ ᴘʀɪᴠᴀᴛᴇʟʏ 🔗", ShowAlert: true})
+
+	return nil
+}
+
+// bundleStart sends the files of a bundle created from the select menu in order.
+func bundleStart(bot *gotgbot.Bot, ctx *ext.Context, id string) error {
+	m := ctx.Message
+
+	b, ok, err := _app.Cache.Bundle.Get(id)
+	if err != nil {
+		_app.Log.Warn("start: get bundle failed", zap.Error(err), zap.String("id", id))
+	}
+
+	if !ok || err != nil || b == nil {
+		m.Reply(bot, "<i>⌛ This Link Has Expired, Please Get a New Link from the Chat Where You Found It !</i>", &gotgbot.SendMessageOpts{ParseMode: gotgbot.ParseModeHTML})
+		return nil
+	}
+
+	// channels of the group where the files were selected are checked
+	ok, err = fsub.CheckChannels(_app, bot, ctx, _app.ChatConfig(b.ChatId).GetFsubChannels())
+	if err != nil {
+		_app.Log.Warn("start: check fsub failed", zap.Error(err))
+	}
+
+	if !ok {
+		return nil
+	}
+
+	pm, _ := m.Reply(bot, "<b>Fetching Media 📥</b>", &gotgbot.SendMessageOpts{ParseMode: gotgbot.ParseModeHTML})
+
+	sendFiles(bot, ctx, m.Chat.Id, b.UniqueIds)
+
+	if pm != nil {
+		pm.Delete(bot, nil)
+	}
+
+	return nil
+}
@@ -3,12 +3,14 @@ package core
 import (
 	"encoding/base64"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/Jisin0/autofilterbot/internal/autofilter"
 	"github.com/Jisin0/autofilterbot/internal/fsub"
 	"github.com/Jisin0/autofilterbot/internal/functions"
 	"github.com/Jisin0/autofilterbot/internal/model"
+	"github.com/Jisin0/autofilterbot/pkg/deeplink"
 	"github.com/PaulSonOfLars/gotgbot/v2"
 	"github.com/PaulSonOfLars/gotgbot/v2/ext"
 	"go.uber.org/zap"
@@ -37,27 +39,75 @@ func StartCommand(bot *gotgbot.Bot, ctx *ext.Context) error {
 		return StaticCommands(bot, ctx)
 	}
 
-	bytes, err := base64.StdEncoding.DecodeString(split[1]) // any start data is expected to be base64 encoded
-	if err != nil {
-		_app.Log.Warn("start: decode data failed", zap.Error(err))
+	if query, ok := autofilter.QueryFromStartData(split[1]); ok {
+		return searchStart(bot, ctx, query)
+	}
+
+	if id, ok := strings.CutPrefix(split[1], BatchDataPrefix); ok {
+		return batchStart(bot, ctx, id)
+	}
+
+	if id, ok := strings.CutPrefix(split[1], model.CollectionDataPrefix); ok {
+		return collectionStart(bot, ctx, id)
+	}
+
+	if id, ok := strings.CutPrefix(split[1], BundleDataPrefix); ok {
+		return bundleStart(bot, ctx, id)
+	}
+
+	var (
+		signed = deeplink.IsSigned(split[1])
+		bytes  []byte
+		err    error
+	)
+
+	if signed {
+		bytes, err = _app.Links.Verify(split[1])
+		if err != nil {
+			invalidLink(bot, ctx, err)
+			return nil
+		}
+	} else {
+		bytes, err = base64.StdEncoding.DecodeString(split[1]) // old links are base64 encoded without a signature
+		if err != nil {
+			_app.Log.Warn("start: decode data failed", zap.Error(err))
+			return nil
+		}
+	}
+
+	if len(bytes) == 0 {
 		return nil
 	}
 
 	data := string(bytes)
+
+	// old file and batch links can be crafted by anyone
+	if !signed && data[0] != DataPrefixRetry && _app.Config.GetBlockLegacyLinks() {
+		invalidLink(bot, ctx, errLegacyLink)
+		return nil
+	}
+
 	switch data[0] {
 	case DataPrefixFile:
-		ok, err := fsub.CheckFsub(_app, bot, ctx)
-		if err != nil {
-			_app.Log.Warn("start: check fsub failed", zap.Error(err))
+		var d *autofilter.URLData
+		if signed {
+			d, err = autofilter.URLDataFromBytes(bytes)
+		} else {
+			d, err = autofilter.URLDataFromString(data)
 		}
 
-		if !ok {
+		if err != nil {
+			_app.Log.Warn("start: parse sendfile start data failed", zap.Error(err))
 			return nil
 		}
 
-		d, err := autofilter.URLDataFromString(data)
+		// channels of the group where the file was requested from are checked
+		ok, err := fsub.CheckChannels(_app, bot, ctx, _app.ChatConfig(d.ChatId).GetFsubChannels())
 		if err != nil {
-			_app.Log.Warn("start: parse sendfile start data failed", zap.Error(err))
+			_app.Log.Warn("start: check fsub failed", zap.Error(err))
+		}
+
+		if !ok {
 			return nil
 		}
 
@@ -68,32 +118,10 @@ func StartCommand(bot *gotgbot.Bot, ctx *ext.Context) error {
 			return nil
 		}
 
-		var (
-			warn    string
-			delTime = _app.Config.GetFileAutoDelete()
-		)
-		if delTime != 0 {
-			warn = fmt.Sprintf("<blockquote>⚠️ 𝖳𝗁𝗂𝗌 𝖥𝗂𝗅𝖾 𝖶𝗂𝗅𝗅 𝖻𝖾 𝖠𝗎𝗍𝗈𝗆𝖺𝗍𝗂𝖼𝖺𝗅𝗅𝗒 𝖣𝖾𝗅𝖾𝗍𝖾𝖽 𝗂𝗇 %d 𝖬𝗂𝗇𝗎𝗍𝖾𝗌. 𝖥𝗈𝗋𝗐𝖺𝗋𝖽 𝗂𝗍 𝗍𝗈 𝖠𝗇𝗈𝗍𝗁𝖾𝗋 𝖢𝗁𝖺𝗍 𝗈𝗋 𝖲𝖺𝗏𝖾𝖽 𝖬𝖾𝗌𝗌𝖺𝗀𝖾𝗌.</blockquote>", delTime)
-		}
-
-		msg, err := f.Send(bot, m.Chat.Id, &model.SendFileOpts{
-			Caption: _app.FormatText(ctx, _app.Config.GetFileCaption(), map[string]any{
-				"file_size": functions.FileSizeToString(f.FileSize),
-				"file_name": f.FileName,
-				"warn":      warn,
-			}),
-			Keyboard: [][]gotgbot.InlineKeyboardButton{{{Text: "🗑️ ᴅᴇʟᴇᴛᴇ ғɪʟᴇ 🗑️", CallbackData: "close"}}},
-		})
+		err = sendFile(bot, ctx, m.Chat.Id, f)
 		if err != nil {
 			_app.Log.Warn("start: send file failed", zap.Error(err), zap.String("file_id", f.FileId))
 		}
-
-		if delTime != 0 {
-			err = _app.AutoDelete.SaveMessage(msg, time.Minute*time.Duration(delTime))
-			if err != nil {
-				_app.Log.Warn("start: insert auto delete failed", zap.Error(err))
-			}
-		}
 	case DataPrefixRetry:
 		d, err := RetryDataFromString(data)
 		if err != nil {
@@ -114,23 +142,75 @@ func StartCommand(bot *gotgbot.Bot, ctx *ext.Context) error {
 			_app.Log.Warn("start: send retry msg failed", zap.Error(err))
 		}
 	case DataPrefixBatch:
-		ok, err := fsub.CheckFsub(_app, bot, ctx)
-		if err != nil {
-			_app.Log.Warn("start: check fsub failed", zap.Error(err))
+		var d *BatchURLData
+		if signed {
+			d, err = BatchURLDataFromBytes(bytes)
+		} else {
+			d, err = BatchURLDataFromString(data)
 		}
 
-		if !ok {
+		if err != nil {
+			_app.Log.Warn("start: parse batch start data failed", zap.Error(err))
 			return nil
 		}
 
-		pm, _ := m.Reply(bot, "<b>Fetching Media 📥</b>", &gotgbot.SendMessageOpts{ParseMode: gotgbot.ParseModeHTML})
+		deliverBatch(bot, ctx, d)
+	}
+
+	return nil
+}
+
+// sendFile sends a file to chatId with the file caption and a delete button, the message is deleted later if file autodelete is enabled.
+func sendFile(bot *gotgbot.Bot, ctx *ext.Context, chatId int64, f *model.File) error {
+	var (
+		warn    string
+		delTime = _app.Config.GetFileAutoDelete()
+	)
+	if delTime != 0 {
+		warn = fmt.Sprintf("<blockquote>⚠️ 𝖳𝗁𝗂𝗌 𝖥𝗂𝗅𝖾 𝖶𝗂𝗅𝗅 𝖻𝖾 𝖠𝗎𝗍𝗈𝗆𝖺𝗍𝗂𝖼𝖺𝗅𝗅𝗒 𝖣𝖾𝗅𝖾𝗍𝖾𝖽 𝗂𝗇 %d 𝖬𝗂𝗇𝗎𝗍𝖾𝗌. 𝖥𝗈𝗋𝗐𝖺𝗋𝖽 𝗂𝗍 𝗍𝗈 𝖠𝗇𝗈𝗍𝗁𝖾𝗋 𝖢𝗁𝖺𝗍 𝗈𝗋 𝖲𝖺𝗏𝖾𝖽 𝖬𝖾𝗌𝗌𝖺𝗀𝖾𝗌.</blockquote>", delTime)
+	}
 
-		SendBatch(bot, m.Chat.Id, data)
+	msg, err := f.Send(bot, chatId, &model.SendFileOpts{
+		Caption: _app.FormatText(ctx, _app.Config.GetFileCaption(), map[string]any{
+			"file_size": functions.FileSizeToString(f.FileSize),
+			"file_name": f.FileName,
+			"warn":      warn,
+		}),
+		Keyboard: [][]gotgbot.InlineKeyboardButton{{{Text: "🗑️ ᴅᴇʟᴇᴛᴇ ғɪʟᴇ 🗑️", CallbackData: "close"}}},
+	})
+	if err != nil {
+		return err
+	}
 
-		if pm != nil {
-			pm.Delete(bot, nil)
+	if delTime != 0 {
+		err = _app.AutoDelete.SaveMessage(msg, time.Minute*time.Duration(delTime))
+		if err != nil {
+			_app.Log.Warn("core: insert auto delete failed", zap.Error(err))
 		}
 	}
 
 	return nil
 }
+
+// sendFiles sends saved files in order using sendFile and returns the number of files sent, files that were deleted are skipped.
+func sendFiles(bot *gotgbot.Bot, ctx *ext.Context, chatId int64, uniqueIds []string) int {
+	var sent int
+
+	for _, uid := range uniqueIds {
+		f, err := _app.DB.GetFile(uid)
+		if err != nil {
+			_app.Log.Debug("core: get file failed", zap.Error(err), zap.String("file", uid))
+			continue
+		}
+
+		err = sendFile(bot, ctx, chatId, f)
+		if err != nil {
+			_app.Log.Warn("core: send file failed", zap.Error(err), zap.String("file_id", f.FileId))
+			continue
+		}
+
+		sent++
+	}
+
+	return sent
+}
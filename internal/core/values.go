@@ -42,12 +42,15 @@ func (core *Core) BasicMessageValues(ctx *ext.Context, extraValues ...map[string
 		values["mention"] = mention
 	}
 
-	if m.Chat.Title != "" {
-		values["chat_name"] = m.Chat.Title
-	}
+	// inline queries have no message
+	if m != nil {
+		if m.Chat.Title != "" {
+			values["chat_name"] = m.Chat.Title
+		}
 
-	if m.Chat.Username != "" {
-		values["chat_username"] = m.Chat.Username
+		if m.Chat.Username != "" {
+			values["chat_username"] = m.Chat.Username
+		}
 	}
 
 	if len(extraValues) != 0 {
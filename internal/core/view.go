@@ -0,0 +1,176 @@
+package core
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/Jisin0/autofilterbot/internal/autofilter"
+	"github.com/Jisin0/autofilterbot/pkg/callbackdata"
+	"github.com/PaulSonOfLars/gotgbot/v2"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext"
+	"go.uber.org/zap"
+)
+
+const (
+	viewMenuFilter = "filter"
+	viewMenuSort   = "sort"
+
+	viewKeyType       = "type"
+	viewKeyLanguage   = "lang"
+	viewKeyResolution = "res"
+	viewKeySort       = "sort"
+)
+
+// View handles the view callback query from the filter and sort buttons of autofilter results.
+//
+// Args are the result id and page index followed by either a menu to open or a key and value to set.
+func View(bot *gotgbot.Bot, ctx *ext.Context) error {
+	c := ctx.CallbackQuery
+
+	data := callbackdata.FromString(c.Data)
+	if len(data.Args) < 3 {
+		c.Answer(bot, &gotgbot.AnswerCallbackQueryOpts{Text: "Error: Not Enough Arguments", ShowAlert: true, CacheTime: fiveHoursInSeconds})
+		return nil
+	}
+
+	r, ok, err := _app.Cache.Autofilter.Get(data.Args[0])
+	if err != nil {
+		c.Answer(bot, &gotgbot.AnswerCallbackQueryOpts{Text: "An Error occurred :\\", ShowAlert: true})
+		_app.Log.Warn("view: result from cache failed", zap.Error(err))
+		return nil
+	}
+
+	if !ok {
+		c.Answer(bot, &gotgbot.AnswerCallbackQueryOpts{Text: "This Query Has Expired!\nPlease Request Again...", ShowAlert: true, CacheTime: fiveHoursInSeconds})
+		return nil
+	}
+
+	if r.FromUser != c.From.Id {
+		c.Answer(bot, &gotgbot.AnswerCallbackQueryOpts{Text: "You Can't Use This, Please Ask For Your Own!", ShowAlert: true, CacheTime: fiveHoursInSeconds})
+		return nil
+	}
+
+	pageIndex, err := strconv.Atoi(data.Args[1])
+	if err != nil {
+		c.Answer(bot, &gotgbot.AnswerCallbackQueryOpts{Text: "An Error occurred :\\", ShowAlert: true})
+		_app.Log.Warn("view: parse page index failed", zap.Error(err))
+		return nil
+	}
+
+	var (
+		buttons [][]gotgbot.InlineKeyboardButton
+		chatId  = c.Message.GetChat().Id
+	)
+
+	if len(data.Args) == 3 {
+		switch data.Args[2] {
+		case viewMenuFilter:
+			buttons = filterMenu(r, pageIndex)
+		case viewMenuSort:
+			buttons = sortMenu(r, pageIndex)
+		default:
+			c.Answer(bot, &gotgbot.AnswerCallbackQueryOpts{Text: "Error: Unknown Menu", ShowAlert: true})
+			return nil
+		}
+	} else {
+		v := r.View
+
+		switch val := data.Args[3]; data.Args[2] {
+		case viewKeyType:
+			v.FileType = val
+		case viewKeyLanguage:
+			v.Language = val
+		case viewKeyResolution:
+			v.Resolution = val
+		case viewKeySort:
+			v.Sort = val
+		default:
+			c.Answer(bot, &gotgbot.AnswerCallbackQueryOpts{Text: "Error: Unknown Option", ShowAlert: true})
+			return nil
+		}
+
+		if cfg := _app.ChatConfig(r.ChatID); !r.SetView(v, cfg.GetMaxPerPage(), cfg.GetGroupResults()) {
+			c.Answer(bot, &gotgbot.AnswerCallbackQueryOpts{Text: "No Files Match This Filter 🤷", ShowAlert: true})
+			return nil
+		}
+
+		err = _app.Cache.Autofilter.Save(r)
+		if err != nil {
+			_app.Log.Warn("view: save result failed", zap.Error(err), zap.String("unique_id", r.UniqueId))
+		}
+
+		buttons = resultButtons(r, 0, chatId, bot.Username)
+	}
+
+	_, _, err = c.Message.EditReplyMarkup(bot, &gotgbot.EditMessageReplyMarkupOpts{
+		ChatId:      chatId,
+		MessageId:   c.Message.GetMessageId(),
+		ReplyMarkup: gotgbot.InlineKeyboardMarkup{InlineKeyboard: buttons},
+	})
+	if err != nil {
+		_app.Log.Warn("view: edit markup failed", zap.Error(err), zap.String("unique_id", r.UniqueId))
+	}
+
+	return nil
+}
+
+// filterMenu returns a keyboard with the file types, resolutions and languages found in the result.
+func filterMenu(r *autofilter.SearchResult, pageIndex int) [][]gotgbot.InlineKeyboardButton {
+	var (
+		fileTypes, languages, resolutions = r.Options()
+		keyboard                          [][]gotgbot.InlineKeyboardButton
+	)
+
+	section := func(title, key, current string, values []string) {
+		if len(values) == 0 {
+			return
+		}
+
+		keyboard = append(keyboard, []gotgbot.InlineKeyboardButton{{Text: "〰️ " + title + " 〰️", CallbackData: "ignore"}})
+
+		// the first option clears the filter
+		row := []gotgbot.InlineKeyboardButton{viewOptionButton(r.UniqueId, pageIndex, key, "", "ᴀʟʟ", current == "")}
+
+		for _, v := range values {
+			if len(row) == 3 {
+				keyboard = append(keyboard, row)
+				row = nil
+			}
+
+			row = append(row, viewOptionButton(r.UniqueId, pageIndex, key, v, v, current == v))
+		}
+
+		keyboard = append(keyboard, row)
+	}
+
+	section("ᴛʏᴘᴇ", viewKeyType, r.View.FileType, fileTypes)
+	section("ʀᴇsᴏʟᴜᴛɪᴏɴ", viewKeyResolution, r.View.Resolution, resolutions)
+	section("ʟᴀɴɢᴜᴀɢᴇ", viewKeyLanguage, r.View.Language, languages)
+
+	return append(keyboard, []gotgbot.InlineKeyboardButton{backButton(r.UniqueId, pageIndex)})
+}
+
+// sortMenu returns a keyboard with the sort orders available for results.
+func sortMenu(r *autofilter.SearchResult, pageIndex int) [][]gotgbot.InlineKeyboardButton {
+	options := []struct{ value, text string }{
+		{autofilter.SortDefault, "ʀᴇʟᴇᴠᴀɴᴄᴇ"},
+		{autofilter.SortSize, "sɪᴢᴇ"},
+		{autofilter.SortDate, "ᴅᴀᴛᴇ"},
+		{autofilter.SortName, "ɴᴀᴍᴇ"},
+	}
+
+	row := make([]gotgbot.InlineKeyboardButton, 0, len(options))
+	for _, o := range options {
+		row = append(row, viewOptionButton(r.UniqueId, pageIndex, viewKeySort, o.value, o.text, r.View.Sort == o.value))
+	}
+
+	return [][]gotgbot.InlineKeyboardButton{row, {backButton(r.UniqueId, pageIndex)}}
+}
+
+func viewOptionButton(uniqueId string, pageIndex int, key, value, text string, selected bool) gotgbot.InlineKeyboardButton {
+	if selected {
+		text = "✅ " + text
+	}
+
+	return gotgbot.InlineKeyboardButton{Text: text, CallbackData: fmt.Sprintf("view|%s_%d_%s_%s", uniqueId, pageIndex, key, value)}
+}
@@ -0,0 +1,63 @@
+package core
+
+import (
+	"context"
+	"time"
+
+	"github.com/Jisin0/autofilterbot/internal/autofilter"
+	"github.com/Jisin0/autofilterbot/internal/model"
+	"github.com/Jisin0/autofilterbot/pkg/fuzzy"
+	"go.uber.org/zap"
+)
+
+const (
+	// vocabularyRefreshInterval is the duration after which the vocabulary is rebuilt to include files saved by index operations.
+	vocabularyRefreshInterval = 6 * time.Hour
+)
+
+// RunVocabularyUpdater builds the fuzzy search vocabulary from all saved files and periodically rebuilds it until ctx is cancelled.
+func (c *Core) RunVocabularyUpdater(ctx context.Context) {
+	ticker := time.NewTicker(vocabularyRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		v, err := c.buildVocabulary(ctx)
+		if err != nil {
+			c.Log.Warn("core: build vocabulary failed", zap.Error(err))
+		} else {
+			c.Vocabulary.Replace(v)
+			c.Log.Debug("core: vocabulary updated", zap.Int("words", c.Vocabulary.Len()))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// buildVocabulary creates a new vocabulary with words from the names of all saved files.
+func (c *Core) buildVocabulary(ctx context.Context) (*fuzzy.Vocabulary, error) {
+	cursor, err := c.DB.GetAllFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	defer cursor.Close(ctx)
+
+	v := fuzzy.NewVocabulary()
+
+	for cursor.Next(ctx) {
+		var f model.File
+
+		if err := cursor.Decode(&f); err != nil {
+			c.Log.Debug("core: build vocabulary: decode file failed", zap.Error(err))
+			continue
+		}
+
+		v.Add(autofilter.WordsFromFileName(f.FileName)...)
+	}
+
+	return v, ctx.Err()
+}
@@ -2,6 +2,8 @@ package database
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 
 	"go.mongodb.org/mongo-driver/mongo"
 )
@@ -16,7 +18,52 @@ type Cursor interface {
 	// Decode unmarshals the current document into the value pointed to by v.
 	// The value v must be a pointer to a struct or map.
 	Decode(v interface{}) error
+	// Err returns the last error that stopped the cursor, Next returning false is not an error if Err returns nil.
+	Err() error
 	// Close closes the cursor, releasing any resources associated with it.
 	// It should be called after the cursor is no longer needed.
 	Close(ctx context.Context) error
 }
+
+// sliceCursor iterates over documents already loaded in memory, decoding them through json.
+type sliceCursor struct {
+	docs    []interface{}
+	current int
+}
+
+// NewSliceCursor creates a cursor over docs, they're decoded using their json tags.
+func NewSliceCursor(docs []interface{}) Cursor {
+	return &sliceCursor{docs: docs, current: -1}
+}
+
+func (c *sliceCursor) Next(_ context.Context) bool {
+	if c.current+1 >= len(c.docs) {
+		return false
+	}
+
+	c.current++
+
+	return true
+}
+
+func (c *sliceCursor) Decode(v interface{}) error {
+	if c.current < 0 || c.current >= len(c.docs) {
+		return errors.New("cursor: no current document")
+	}
+
+	b, err := json.Marshal(c.docs[c.current])
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(b, v)
+}
+
+func (c *sliceCursor) Err() error {
+	return nil
+}
+
+func (c *sliceCursor) Close(_ context.Context) error {
+	c.docs = nil
+	return nil
+}
@@ -2,8 +2,12 @@
 package database
 
 import (
+	"context"
+	"time"
+
 	"github.com/Jisin0/autofilterbot/internal/config"
 	"github.com/Jisin0/autofilterbot/internal/model"
+	"go.uber.org/zap"
 )
 
 const (
@@ -13,11 +17,13 @@ const (
 	CollectionNameOperations   = "Operations"
 	CollectionNameGroups       = "Groups"
 	CollectionNameJoinRequests = "JoinRequests"
+	CollectionNameBatches      = "Batches"
+	CollectionNameCollections  = "Collections"
 
 	DefaultDatabaseName = "AutoFilterBot"
 )
 
-// DEPRECATED
+// Database is implemented by all storage backends supported by the bot.
 type Database interface {
 	// Shutdown gracefully closes the database.
 	Shutdown() error
@@ -33,6 +39,8 @@ type Database interface {
 	SaveUserJoinRequest(userId, chatId int64) error
 	// DeletUserJoinRequest deletes the chat from the join requests list.
 	DeleteUserJoinRequest(userId, chatId int64) error
+	// GetUserJoinRequests fetches a user along with the list of chats they've sent join requests to.
+	GetUserJoinRequests(userId int64) (*model.User, error)
 	// GetUsers returns a cursor to loop through all saved users.
 	GetAllUsers() (Cursor, error)
 
@@ -45,11 +53,50 @@ type Database interface {
 	GetFile(fileId string) (*model.File, error)
 	// DeleteFile deletes a file from the database using its unique_id.
 	DeleteFile(fileId string) error
+	// IncrementDownloads increases the download count of a file by one, a missing file is ignored.
+	IncrementDownloads(fileId string) error
 	// SearchFiles searches for files in the database by their name. The query should be sanitized first.
-	SearchFiles(query string) (Cursor, error)
+	// Results are sorted by time unless a ranked search mode is set in opts.
+	SearchFiles(query string, opts ...SearchFilesOpts) (Cursor, error)
+	// GetAllFiles returns a cursor to loop through all saved files.
+	GetAllFiles() (Cursor, error)
+
+	// SaveGroup saves the details of a group or updates them if it already exists, settings of the group are kept.
+	SaveGroup(g *model.Group) error
+	// GetGroup fetches the details of a group using its id.
+	GetGroup(groupId int64) (*model.Group, error)
+	// GetGroups fetches all groups saved using SaveGroup, the most recently added groups are first.
+	GetGroups() ([]*model.Group, error)
+	// UpdateGroupStatus changes the status of a group, a missing group is ignored.
+	UpdateGroupStatus(groupId int64, status string) error
+	// GetGroupConfig fetches the settings of a group, an empty config is returned if the group has none.
+	GetGroupConfig(groupId int64) (*config.GroupConfig, error)
+	// UpdateGroupConfig updates a single setting of a group, the group is saved if it does not exist.
+	UpdateGroupConfig(groupId int64, key string, value interface{}) error
+	// ResetGroupConfig removes a setting of a group so that the bot config is used instead.
+	ResetGroupConfig(groupId int64, key string) error
 
-	// SaveGroup inserts a group id into the database to keep track of them.
-	SaveGroup(id int64) error
+	// SaveBatch saves a new batch.
+	SaveBatch(b *model.Batch) error
+	// GetBatch fetches a batch using its id.
+	GetBatch(id string) (*model.Batch, error)
+	// GetBatches fetches all batches, the most recently created batches are first.
+	GetBatches() ([]*model.Batch, error)
+	// UpdateBatch updates fields of a batch using their json names, a missing batch is ignored.
+	UpdateBatch(id string, vals map[string]interface{}) error
+	// IncrementBatchAccesses increases the access count of a batch by one, a missing batch is ignored.
+	IncrementBatchAccesses(id string) error
+	// DeleteBatch deletes a batch so its link stops working.
+	DeleteBatch(id string) error
+
+	// SaveCollection saves a collection of files or replaces it if it already exists.
+	SaveCollection(c *model.Collection) error
+	// GetCollection fetches a collection using its id.
+	GetCollection(id string) (*model.Collection, error)
+	// GetCollections fetches all collections, the most recently created collections are first.
+	GetCollections() ([]*model.Collection, error)
+	// DeleteCollection deletes a collection so its link stops working.
+	DeleteCollection(id string) error
 
 	// GetConfig fetches the bot configs from the database.
 	GetConfig(botId int64) (*config.Config, error)
@@ -77,6 +124,66 @@ type Database interface {
 	DeleteOperation(pid string) error
 }
 
+// MultiStorage is implemented by databases that spread files across multiple storage locations, like multiple mongodb clusters.
+type MultiStorage interface {
+	// UpdateStorageCollection sets the index of the storage to which new files are saved.
+	UpdateStorageCollection(index int) error
+	// RunCollectionUpdater starts a job in a new goroutine that periodically switches to the storage with the least files, it returns immediately and the job stops when ctx is cancelled.
+	RunCollectionUpdater(ctx context.Context, log *zap.Logger)
+}
+
+// StorageHealth is the status of a single file storage.
+type StorageHealth struct {
+	// Indicates whether the storage is reachable.
+	Healthy bool
+	// Last error returned by the storage, empty if healthy.
+	Error string
+	// Time at which the storage became healthy or unhealthy.
+	Since time.Time
+	// Time of the last check.
+	CheckedAt time.Time
+	// Time taken to respond to the last check.
+	Latency time.Duration
+}
+
+// HealthMonitor is implemented by databases that monitor the health of their storages.
+type HealthMonitor interface {
+	// StorageHealth returns the health of each storage in order of their index.
+	StorageHealth() []StorageHealth
+	// RunHealthChecker starts a job in a new goroutine that periodically checks and reconnects storages, it returns immediately and the job stops when ctx is cancelled.
+	// onChange is called when a storage goes down or recovers.
+	RunHealthChecker(ctx context.Context, log *zap.Logger, onChange func(index int, h StorageHealth))
+}
+
+// StorageStats is the usage of a single file storage.
+type StorageStats struct {
+	// Number of files in the storage.
+	Files int64
+	// Size of the stored documents in bytes.
+	Size int64
+}
+
+// Migrator is implemented by databases with multiple storages that can move files between them.
+// Migrations are saved in the operations collection alongside index operations.
+type Migrator interface {
+	// StorageStats returns the usage of each storage in order of their index.
+	StorageStats(ctx context.Context) ([]StorageStats, error)
+	// FileIDs returns upto limit ids of files in a storage that are greater than after, in ascending order.
+	FileIDs(ctx context.Context, storage int, after string, limit int) ([]string, error)
+	// MoveFile copies a file to a storage and then deletes it from the other.
+	// It is safe to retry, a file that already exists in the target is only deleted from the source and a missing file is ignored.
+	MoveFile(ctx context.Context, fileId string, from, to int) error
+
+	// NewMigration inserts a new migration into the operations collection.
+	NewMigration(m *model.Migration) error
+	// UpdateMigration updates fields of a migration, returns false if it was not found.
+	UpdateMigration(id string, vals map[string]interface{}) (bool, error)
+	// GetMigration fetches a migration by it's id.
+	GetMigration(id string) (*model.Migration, error)
+	// GetActiveMigrations fetches all migrations that are not paused.
+	GetActiveMigrations() ([]*model.Migration, error)
+}
+
 // KeyValuePair represents a single key-value pair in a document.
 type KeyValuePair struct {
 	Key   string
@@ -0,0 +1,672 @@
+// Package databasetest provides a conformance test suite that every database.Database implementation should pass.
+package databasetest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/Jisin0/autofilterbot/internal/config"
+	"github.com/Jisin0/autofilterbot/internal/database"
+	"github.com/Jisin0/autofilterbot/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+// NewDatabaseFunc should return a new empty database, cleanup can be registered using t.Cleanup.
+type NewDatabaseFunc func(t *testing.T) database.Database
+
+// Run runs the full conformance suite, a fresh database is created for each subtest.
+func Run(t *testing.T, newDB NewDatabaseFunc) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, db database.Database)
+	}{
+		{name: "Users", fn: testUsers},
+		{name: "JoinRequests", fn: testJoinRequests},
+		{name: "SaveFile", fn: testSaveFile},
+		{name: "SearchFiles", fn: testSearchFiles},
+		{name: "RankedSearch", fn: testRankedSearch},
+		{name: "SearchFilter", fn: testSearchFilter},
+		{name: "SearchPagination", fn: testSearchPagination},
+		{name: "Downloads", fn: testDownloads},
+		{name: "Config", fn: testConfig},
+		{name: "Groups", fn: testGroups},
+		{name: "GroupConfig", fn: testGroupConfig},
+		{name: "Batches", fn: testBatches},
+		{name: "Collections", fn: testCollections},
+		{name: "IndexOperations", fn: testIndexOperations},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.fn(t, newDB(t))
+		})
+	}
+}
+
+// testFiles are saved by tests that need files in the database.
+var testFiles = []*model.File{
+	{UniqueId: "AgADa", FileId: "BQACAgUAAa", FileName: "Avatar 2009 720p.mkv", FileType: model.FileTypeVideo, FileSize: 1000, Extension: "mkv", Time: 1, ChatId: -1001, MessageLink: "https://t.me/c/1/1"},
+	{UniqueId: "AgADb", FileId: "BQACAgUAAb", FileName: "Avatar.The.Way.Of.Water.2022.1080p.mkv", FileType: model.FileTypeVideo, FileSize: 5000, Time: 3, ChatId: -1001, MessageLink: "https://t.me/c/1/2"},
+	{UniqueId: "AgADc", FileId: "BQACAgUAAc", FileName: "Interstellar 2014.mkv", FileType: model.FileTypeDocument, FileSize: 3000, Time: 2, ChatId: -1001, MessageLink: "https://t.me/c/1/3"},
+	{
+		UniqueId: "AgADd", FileId: "BQACAgUAAd", FileName: "Avatar_The_Last_Airbender_S01E01.mp4", FileType: model.FileTypeVideo, FileSize: 200, Time: 2, ChatId: -1002, MessageLink: "https://t.me/c/2/1",
+		Release: &model.Release{Title: "Avatar The Last Airbender", Season: 1, Episode: 1, Languages: []string{"English", "Hindi"}},
+	},
+}
+
+func testUsers(t *testing.T, db database.Database) {
+	assert := assert.New(t)
+
+	assert.NoError(db.SaveUser(1))
+	assert.NoError(db.SaveUser(1), "saving an existing user should not fail")
+	assert.NoError(db.SaveUser(2))
+
+	u, err := db.GetUser(1)
+	assert.NoError(err)
+	assert.Equal(int64(1), u.UserId)
+
+	_, err = db.GetUser(3)
+	assert.True(database.IsNoDocumentsError(err), "expected no documents error got %v", err)
+
+	assert.NoError(db.DeleteUser(2))
+
+	cursor, err := db.GetAllUsers()
+	if !assert.NoError(err) {
+		return
+	}
+
+	var ids []int64
+
+	for cursor.Next(context.Background()) {
+		var u model.User
+
+		assert.NoError(cursor.Decode(&u))
+
+		ids = append(ids, u.UserId)
+	}
+
+	assert.NoError(cursor.Close(context.Background()))
+	assert.Equal([]int64{1}, ids)
+}
+
+func testJoinRequests(t *testing.T, db database.Database) {
+	assert := assert.New(t)
+
+	u, err := db.GetUserJoinRequests(1)
+	if err == nil {
+		assert.Empty(u.JoinRequests)
+	} else {
+		assert.True(database.IsNoDocumentsError(err), "expected no documents error got %v", err)
+	}
+
+	assert.NoError(db.SaveUserJoinRequest(1, -1001))
+	assert.NoError(db.SaveUserJoinRequest(1, -1002))
+	assert.NoError(db.SaveUserJoinRequest(1, -1002), "saving a join request twice should not fail")
+	assert.NoError(db.SaveUserJoinRequest(2, -1001))
+
+	u, err = db.GetUserJoinRequests(1)
+	if assert.NoError(err) {
+		assert.ElementsMatch([]int64{-1001, -1002}, u.JoinRequests)
+	}
+
+	assert.NoError(db.DeleteUserJoinRequest(1, -1001))
+	assert.NoError(db.DeleteUserJoinRequest(1, -1003), "deleting a missing join request should not fail")
+
+	u, err = db.GetUserJoinRequests(1)
+	if assert.NoError(err) {
+		assert.Equal([]int64{-1002}, u.JoinRequests)
+	}
+
+	assert.NoError(db.DeleteUserJoinRequest(1, -1002))
+
+	u, err = db.GetUserJoinRequests(1)
+	if err == nil {
+		assert.Empty(u.JoinRequests)
+	} else {
+		assert.True(database.IsNoDocumentsError(err), "expected no documents error got %v", err)
+	}
+
+	u, err = db.GetUserJoinRequests(2)
+	if assert.NoError(err) {
+		assert.Equal([]int64{-1001}, u.JoinRequests, "join requests of other users should be untouched")
+	}
+}
+
+func testSaveFile(t *testing.T, db database.Database) {
+	assert := assert.New(t)
+
+	assert.Empty(db.SaveFiles(testFiles...))
+
+	f, err := db.GetFile(testFiles[2].UniqueId)
+	if assert.NoError(err) {
+		assert.Equal(testFiles[2], f)
+	}
+
+	f, err = db.GetFile(testFiles[3].UniqueId)
+	if assert.NoError(err) {
+		assert.Equal(testFiles[3], f, "release metadata should be saved")
+	}
+
+	_, err = db.GetFile("AgADx")
+	assert.True(database.IsNoDocumentsError(err), "expected no documents error got %v", err)
+
+	duplicates := []struct {
+		name string
+		file *model.File
+	}{
+		{name: "same file_id", file: &model.File{UniqueId: "AgADe", FileId: testFiles[0].FileId, FileName: "Something Else.mkv", FileSize: 1}},
+		{name: "same file_name and file_size", file: &model.File{UniqueId: "AgADf", FileId: "BQACAgUAAf", FileName: testFiles[2].FileName, FileSize: testFiles[2].FileSize}},
+		{name: "same file_name and close file_size", file: &model.File{UniqueId: "AgADg", FileId: "BQACAgUAAg", FileName: testFiles[2].FileName, FileSize: testFiles[2].FileSize + 100}},
+		{name: "file_name prefix and close file_size", file: &model.File{UniqueId: "AgADh", FileId: "BQACAgUAAh", FileName: "Interstellar", FileSize: testFiles[2].FileSize - 50}},
+	}
+
+	for _, tc := range duplicates {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.ErrorAs(db.SaveFile(tc.file), &database.FileAlreadyExistsError{})
+		})
+	}
+
+	unique := []struct {
+		name string
+		file *model.File
+	}{
+		{name: "same file_name and different file_size", file: &model.File{UniqueId: "AgADi", FileId: "BQACAgUAAi", FileName: testFiles[2].FileName, FileSize: testFiles[2].FileSize + 101, Time: 4}},
+		{name: "different file_name and same file_size", file: &model.File{UniqueId: "AgADj", FileId: "BQACAgUAAj", FileName: "Inception 2010.mkv", FileSize: testFiles[2].FileSize, Time: 5}},
+	}
+
+	for _, tc := range unique {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.NoError(db.SaveFile(tc.file))
+		})
+	}
+
+	assert.NoError(db.DeleteFile(testFiles[0].UniqueId))
+
+	_, err = db.GetFile(testFiles[0].UniqueId)
+	assert.True(database.IsNoDocumentsError(err), "expected no documents error got %v", err)
+
+	assert.NoError(db.SaveFile(testFiles[0]), "file should be saved again after deletion")
+
+	cursor, err := db.GetAllFiles()
+	if assert.NoError(err) {
+		var n int
+
+		for cursor.Next(context.Background()) {
+			var f model.File
+
+			assert.NoError(cursor.Decode(&f))
+
+			n++
+		}
+
+		assert.NoError(cursor.Close(context.Background()))
+		assert.Equal(len(testFiles)+len(unique), n)
+	}
+
+	s, err := db.Stats()
+	if assert.NoError(err) {
+		assert.Equal(fmt.Sprint(len(testFiles)+len(unique)), fmt.Sprint(s.Files))
+	}
+}
+
+func testSearchFiles(t *testing.T, db database.Database) {
+	assert := assert.New(t)
+
+	assert.Empty(db.SaveFiles(testFiles...))
+
+	tests := []struct {
+		query    string
+		expected []string
+	}{
+		{query: "avatar", expected: []string{"AgADb", "AgADd", "AgADa"}},
+		{query: "avatar water", expected: []string{"AgADb"}},
+		{query: "interstellar 2014", expected: []string{"AgADc"}},
+		{query: "inception", expected: nil},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.query, func(t *testing.T) {
+			cursor, err := db.SearchFiles(tc.query)
+			if !assert.NoError(err) {
+				return
+			}
+
+			var found []string
+
+			for cursor.Next(context.Background()) {
+				var f model.File
+
+				assert.NoError(cursor.Decode(&f))
+
+				found = append(found, f.UniqueId)
+			}
+
+			assert.NoError(cursor.Close(context.Background()))
+			assert.Equal(tc.expected, found, "results should be sorted by newest first")
+		})
+	}
+}
+
+func testRankedSearch(t *testing.T, db database.Database) {
+	assert := assert.New(t)
+
+	assert.Empty(db.SaveFiles(testFiles...))
+
+	opts := database.SearchFilesOpts{Mode: config.SearchModeText}
+
+	tests := []struct {
+		name     string
+		query    string
+		first    string
+		expected []string
+	}{
+		{name: "words out of order", query: "water avatar", first: "AgADb", expected: []string{"AgADa", "AgADb", "AgADd"}},
+		{name: "best match first", query: "avatar 2009", first: "AgADa", expected: []string{"AgADa", "AgADb", "AgADd"}},
+		{name: "partial word fallback", query: "interst", first: "AgADc", expected: []string{"AgADc"}},
+		{name: "no match", query: "inception", expected: nil},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cursor, err := db.SearchFiles(tc.query, opts)
+			if !assert.NoError(err) {
+				return
+			}
+
+			var found []string
+
+			for cursor.Next(context.Background()) {
+				var f model.File
+
+				assert.NoError(cursor.Decode(&f))
+
+				found = append(found, f.UniqueId)
+			}
+
+			assert.NoError(cursor.Close(context.Background()))
+			assert.ElementsMatch(tc.expected, found)
+
+			if tc.first != "" && assert.NotEmpty(found) {
+				assert.Equal(tc.first, found[0])
+			}
+		})
+	}
+}
+
+func testSearchFilter(t *testing.T, db database.Database) {
+	assert := assert.New(t)
+
+	assert.Empty(db.SaveFiles(testFiles...))
+
+	tests := []struct {
+		name     string
+		query    string
+		filter   database.FileFilter
+		channels []int64
+		expected []string
+	}{
+		{name: "file type", query: "avatar", filter: database.FileFilter{FileType: model.FileTypeVideo}, expected: []string{"AgADa", "AgADb", "AgADd"}},
+		{name: "min size", query: "avatar", filter: database.FileFilter{MinSize: 1000}, expected: []string{"AgADa", "AgADb"}},
+		{name: "size range", query: "avatar", filter: database.FileFilter{MinSize: 100, MaxSize: 1000}, expected: []string{"AgADa", "AgADd"}},
+		{name: "extension", query: "avatar", filter: database.FileFilter{Extensions: []string{"mp4"}}, expected: []string{"AgADd"}},
+		{name: "saved extension", query: "avatar", filter: database.FileFilter{Extensions: []string{"mkv"}}, expected: []string{"AgADa", "AgADb"}},
+		{name: "exclude", query: "avatar", filter: database.FileFilter{Exclude: []string{"water", "airbender"}}, expected: []string{"AgADa"}},
+		{name: "phrase", query: "avatar", filter: database.FileFilter{Phrases: []string{"the way of"}}, expected: []string{"AgADb"}},
+		{name: "year", query: "avatar", filter: database.FileFilter{Year: 2022}, expected: []string{"AgADb"}},
+		{name: "no match", query: "avatar", filter: database.FileFilter{FileType: model.FileTypeAudio}, expected: nil},
+		{name: "channel", query: "avatar", channels: []int64{-1002}, expected: []string{"AgADd"}},
+		{name: "multiple channels", query: "avatar", channels: []int64{-1001, -1002}, expected: []string{"AgADa", "AgADb", "AgADd"}},
+		{name: "channel and filter", query: "avatar", filter: database.FileFilter{MaxSize: 1000}, channels: []int64{-1001}, expected: []string{"AgADa"}},
+	}
+
+	for _, mode := range []string{config.SearchModeRegex, config.SearchModeText} {
+		for _, tc := range tests {
+			t.Run(mode+"/"+tc.name, func(t *testing.T) {
+				cursor, err := db.SearchFiles(tc.query, database.SearchFilesOpts{Mode: mode, Filter: &tc.filter, Channels: tc.channels})
+				if !assert.NoError(err) {
+					return
+				}
+
+				var found []string
+
+				for cursor.Next(context.Background()) {
+					var f model.File
+
+					assert.NoError(cursor.Decode(&f))
+
+					found = append(found, f.UniqueId)
+				}
+
+				assert.NoError(cursor.Close(context.Background()))
+				assert.ElementsMatch(tc.expected, found)
+			})
+		}
+	}
+}
+
+func testSearchPagination(t *testing.T, db database.Database) {
+	assert := assert.New(t)
+
+	assert.Empty(db.SaveFiles(testFiles...))
+
+	search := func(opts database.SearchFilesOpts) []string {
+		cursor, err := db.SearchFiles("avatar", opts)
+		if !assert.NoError(err) {
+			return nil
+		}
+
+		var found []string
+
+		for cursor.Next(context.Background()) {
+			var f model.File
+
+			assert.NoError(cursor.Decode(&f))
+
+			found = append(found, f.UniqueId)
+		}
+
+		assert.NoError(cursor.Close(context.Background()))
+
+		return found
+	}
+
+	for _, mode := range []string{config.SearchModeRegex, config.SearchModeText} {
+		t.Run(mode, func(t *testing.T) {
+			all := search(database.SearchFilesOpts{Mode: mode})
+			assert.Len(all, 3)
+
+			// pages fetched one after the other should add up to the full results
+			var paged []string
+
+			for skip := 0; skip < len(all)+2; skip += 2 {
+				page := search(database.SearchFilesOpts{Mode: mode, Skip: skip, Limit: 2})
+				assert.LessOrEqual(len(page), 2)
+
+				paged = append(paged, page...)
+			}
+
+			assert.Equal(all, paged)
+		})
+	}
+}
+
+func testDownloads(t *testing.T, db database.Database) {
+	assert := assert.New(t)
+
+	assert.Empty(db.SaveFiles(testFiles...))
+
+	assert.NoError(db.IncrementDownloads(testFiles[0].UniqueId))
+	assert.NoError(db.IncrementDownloads(testFiles[0].UniqueId))
+	assert.NoError(db.IncrementDownloads(testFiles[1].UniqueId))
+	assert.NoError(db.IncrementDownloads("AgADx"), "missing files should be ignored")
+
+	f, err := db.GetFile(testFiles[0].UniqueId)
+	if assert.NoError(err) {
+		assert.Equal(int64(2), f.Downloads)
+	}
+
+	s, err := db.Stats()
+	if assert.NoError(err) {
+		assert.Equal(int64(3), s.Downloads)
+	}
+}
+
+func testConfig(t *testing.T, db database.Database) {
+	assert := assert.New(t)
+
+	const botId = 12345
+
+	c, err := db.GetConfig(botId)
+	if assert.NoError(err) {
+		assert.Equal(&config.Config{}, c, "missing config should be empty")
+	}
+
+	assert.NoError(db.UpdateConfig(botId, config.FieldNameMaxResults, 20))
+	assert.NoError(db.UpdateConfig(botId, config.FieldNameNoResultText, "nothing found"))
+
+	c, err = db.GetConfig(botId)
+	if assert.NoError(err) {
+		assert.Equal(int64(botId), c.BotId)
+		assert.Equal(20, c.MaxResults)
+		assert.Equal("nothing found", c.NoResultText)
+	}
+
+	assert.NoError(db.ResetConfig(botId, config.FieldNameMaxResults))
+
+	c, err = db.GetConfig(botId)
+	if assert.NoError(err) {
+		assert.Zero(c.MaxResults)
+		assert.Equal("nothing found", c.NoResultText)
+	}
+
+	assert.NoError(db.SaveConfig(botId+1, &config.Config{BotId: botId + 1, MaxPerPage: 5}))
+
+	c, err = db.GetConfig(botId + 1)
+	if assert.NoError(err) {
+		assert.Equal(5, c.MaxPerPage)
+	}
+}
+
+func testGroups(t *testing.T, db database.Database) {
+	assert := assert.New(t)
+
+	_, err := db.GetGroup(-1001)
+	assert.True(database.IsNoDocumentsError(err), "missing group should return a no documents error")
+
+	older := &model.Group{ID: -1001, Title: "Movies", MemberCount: 120, AddedBy: 5, AddedAt: 1000, Status: model.GroupStatusPending}
+	newer := &model.Group{ID: -1002, Title: "Series", MemberCount: 40, AddedBy: 6, AddedAt: 2000, Status: model.GroupStatusApproved}
+
+	assert.NoError(db.SaveGroup(older))
+	assert.NoError(db.SaveGroup(newer))
+
+	// settings of a group are not a saved group
+	assert.NoError(db.UpdateGroupConfig(-1003, config.FieldNameMaxResults, 20))
+
+	g, err := db.GetGroup(older.ID)
+	if assert.NoError(err) {
+		assert.Equal(older, g)
+	}
+
+	groups, err := db.GetGroups()
+	if assert.NoError(err) {
+		assert.Equal([]*model.Group{newer, older}, groups)
+	}
+
+	assert.NoError(db.UpdateGroupConfig(older.ID, config.FieldNameMaxResults, 30))
+	assert.NoError(db.UpdateGroupStatus(older.ID, model.GroupStatusBanned))
+	assert.NoError(db.UpdateGroupStatus(-1004, model.GroupStatusBanned), "missing group should be ignored")
+
+	// saving a group again updates the details and keeps its settings
+	older.Title = "Movies HD"
+	older.Status = model.GroupStatusBanned
+	assert.NoError(db.SaveGroup(older))
+
+	g, err = db.GetGroup(older.ID)
+	if assert.NoError(err) {
+		assert.Equal(older, g)
+	}
+
+	c, err := db.GetGroupConfig(older.ID)
+	if assert.NoError(err) {
+		assert.Equal(30, c.MaxResults)
+	}
+
+	_, err = db.GetGroup(-1004)
+	assert.Error(err)
+}
+
+func testBatches(t *testing.T, db database.Database) {
+	assert := assert.New(t)
+
+	_, err := db.GetBatch("missing")
+	assert.True(database.IsNoDocumentsError(err), "missing batch should return a no documents error")
+
+	older := &model.Batch{ID: "aB3dE6gH", ChatId: -1001, StartMessageId: 10, EndMessageId: 20, CreatedBy: 5, CreatedAt: 1000}
+	newer := &model.Batch{ID: "Zx9Yw8Vu", ChatId: -1002, StartMessageId: 1, EndMessageId: 1, CreatedBy: 6, CreatedAt: 2000, ExpiresAt: 3000, Password: "hash"}
+
+	assert.NoError(db.SaveBatch(older))
+	assert.NoError(db.SaveBatch(newer))
+	assert.Error(db.SaveBatch(older), "saving a batch with the same id should fail")
+
+	b, err := db.GetBatch(older.ID)
+	if assert.NoError(err) {
+		assert.Equal(older, b)
+	}
+
+	batches, err := db.GetBatches()
+	if assert.NoError(err) {
+		assert.Equal([]*model.Batch{newer, older}, batches)
+	}
+
+	assert.NoError(db.IncrementBatchAccesses(older.ID))
+	assert.NoError(db.IncrementBatchAccesses(older.ID))
+	assert.NoError(db.IncrementBatchAccesses("missing"), "missing batch should be ignored")
+
+	assert.NoError(db.UpdateBatch(older.ID, map[string]interface{}{"start": int64(15), "end": int64(30), "expires_at": int64(5000), "password": "secret"}))
+	assert.NoError(db.UpdateBatch("missing", map[string]interface{}{"start": int64(1)}), "missing batch should be ignored")
+
+	b, err = db.GetBatch(older.ID)
+	if assert.NoError(err) {
+		assert.Equal(&model.Batch{ID: older.ID, ChatId: -1001, StartMessageId: 15, EndMessageId: 30, CreatedBy: 5, CreatedAt: 1000, ExpiresAt: 5000, Password: "secret", Accesses: 2}, b)
+	}
+
+	assert.NoError(db.DeleteBatch(older.ID))
+
+	_, err = db.GetBatch(older.ID)
+	assert.True(database.IsNoDocumentsError(err), "deleted batch should return a no documents error")
+}
+
+func testCollections(t *testing.T, db database.Database) {
+	assert := assert.New(t)
+
+	_, err := db.GetCollection("missing")
+	assert.True(database.IsNoDocumentsError(err), "missing collection should return a no documents error")
+
+	older := &model.Collection{ID: "aB3dE6gH", Name: "Avatar", Files: model.CollectionFiles{"AgADb", "AgADa"}, CreatedBy: 5, CreatedAt: 1000}
+	newer := &model.Collection{ID: "Zx9Yw8Vu", Name: "Empty", Files: model.CollectionFiles{}, CreatedBy: 6, CreatedAt: 2000}
+
+	assert.NoError(db.SaveCollection(older))
+	assert.NoError(db.SaveCollection(newer))
+
+	c, err := db.GetCollection(older.ID)
+	if assert.NoError(err) {
+		assert.Equal(older, c)
+	}
+
+	collections, err := db.GetCollections()
+	if assert.NoError(err) {
+		assert.Equal([]*model.Collection{newer, older}, collections)
+	}
+
+	// saving a collection again replaces it and keeps the order of files
+	older.Name = "Avatar Movies"
+	older.Files = append(older.Files, "AgADc")
+	assert.NoError(db.SaveCollection(older))
+
+	c, err = db.GetCollection(older.ID)
+	if assert.NoError(err) {
+		assert.Equal(older, c)
+	}
+
+	assert.NoError(db.DeleteCollection(older.ID))
+
+	_, err = db.GetCollection(older.ID)
+	assert.True(database.IsNoDocumentsError(err), "deleted collection should return a no documents error")
+}
+
+func testGroupConfig(t *testing.T, db database.Database) {
+	assert := assert.New(t)
+
+	const groupId = -1001234
+
+	g, err := db.GetGroupConfig(groupId)
+	if assert.NoError(err) {
+		assert.True(g.IsZero(), "missing group should have no settings")
+	}
+
+	assert.NoError(db.SaveGroup(&model.Group{ID: groupId, Status: model.GroupStatusApproved}))
+	assert.NoError(db.UpdateGroupConfig(groupId, config.FieldNameMaxResults, 20))
+	assert.NoError(db.UpdateGroupConfig(groupId, config.FieldNameAutofilter, false))
+	assert.NoError(db.UpdateGroupConfig(groupId, config.FieldNameFsub, []model.Channel{{ID: -1005, Title: "Updates"}}))
+
+	// settings of a group that was never saved are also kept
+	assert.NoError(db.UpdateGroupConfig(groupId-1, config.FieldNameSizeButton, true))
+
+	g, err = db.GetGroupConfig(groupId)
+	if assert.NoError(err) {
+		assert.Equal(20, g.MaxResults)
+		assert.False(g.GetAutofilter())
+		assert.Equal([]model.Channel{{ID: -1005, Title: "Updates"}}, g.FsubChannels)
+		assert.Nil(g.SizeButton)
+	}
+
+	assert.NoError(db.ResetGroupConfig(groupId, config.FieldNameAutofilter))
+
+	g, err = db.GetGroupConfig(groupId)
+	if assert.NoError(err) {
+		assert.True(g.GetAutofilter())
+		assert.Equal(20, g.MaxResults)
+	}
+
+	g, err = db.GetGroupConfig(groupId - 1)
+	if assert.NoError(err) && assert.NotNil(g.SizeButton) {
+		assert.True(*g.SizeButton)
+	}
+}
+
+func testIndexOperations(t *testing.T, db database.Database) {
+	assert := assert.New(t)
+
+	// operations are created paused and started once setup is complete
+	op := &model.Index{ID: "op1", StartMessageID: 1, EndMessageID: 100, CurrentMessageID: 1, ChannelID: -1001, IsPaused: true, ProgressMessageChatID: 1}
+
+	assert.NoError(db.NewIndexOperation(op))
+
+	o, err := db.GetIndexOperation(op.ID)
+	if assert.NoError(err) {
+		assert.Equal(op, o)
+	}
+
+	ops, err := db.GetActiveIndexOperations()
+	assert.NoError(err)
+	assert.Empty(ops, "paused operations should not be active")
+
+	ok, err := db.UpdateIndexOperation(op.ID, map[string]interface{}{"is_paused": false})
+	assert.NoError(err)
+	assert.True(ok)
+
+	ok, err = db.UpdateIndexOperation(op.ID, map[string]interface{}{"current": 50, "saved": 40, "failed": 2})
+	assert.NoError(err)
+	assert.True(ok)
+
+	ok, err = db.UpdateIndexOperation("op2", map[string]interface{}{"current": 50})
+	assert.NoError(err)
+	assert.False(ok, "missing operation should not be matched")
+
+	o, err = db.GetIndexOperation(op.ID)
+	if assert.NoError(err) {
+		assert.Equal(int64(50), o.CurrentMessageID)
+		assert.Equal(40, o.Saved)
+		assert.Equal(2, o.Failed)
+		assert.Equal(int64(100), o.EndMessageID)
+		assert.False(o.IsPaused)
+	}
+
+	ops, err = db.GetActiveIndexOperations()
+	if assert.NoError(err) && assert.Len(ops, 1) {
+		assert.Equal(op.ID, ops[0].ID)
+	}
+
+	_, err = db.UpdateIndexOperation(op.ID, map[string]interface{}{"is_paused": true})
+	assert.NoError(err)
+
+	ops, err = db.GetActiveIndexOperations()
+	assert.NoError(err)
+	assert.Empty(ops)
+
+	assert.NoError(db.DeleteOperation(op.ID))
+
+	_, err = db.GetIndexOperation(op.ID)
+	assert.True(database.IsNoDocumentsError(err), "expected no documents error got %v", err)
+}
@@ -7,6 +7,9 @@ import (
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
+// ErrNoDocuments is returned by databases other than mongodb when a single document is requested and not found.
+var ErrNoDocuments = errors.New("database: no documents found")
+
 // FileAlreadyExistsError indicates a similar or same file exists.
 type FileAlreadyExistsError struct {
 	FileName string
@@ -16,7 +19,7 @@ func (e FileAlreadyExistsError) Error() string {
 	return fmt.Sprintf("file already exists: %s", e.FileName)
 }
 
-// IsNoDocumentsError reports wether the error is mongo no documnet found error for SingleResult methods.
+// IsNoDocumentsError reports wether the error is a no document found error for single document methods.
 func IsNoDocumentsError(err error) bool {
-	return errors.Is(err, mongo.ErrNoDocuments)
+	return errors.Is(err, mongo.ErrNoDocuments) || errors.Is(err, ErrNoDocuments)
 }
@@ -0,0 +1,167 @@
+package database
+
+import (
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Jisin0/autofilterbot/internal/model"
+)
+
+// FileFilter narrows down the results of SearchFiles, it is built from the search query syntax by autofilter.ParseQuery.
+// Backends should translate it into their own query language, all conditions must match.
+type FileFilter struct {
+	// Phrases that should appear in the file name exactly, ignoring case and separators.
+	Phrases []string
+	// Words that must not appear in the file name.
+	Exclude []string
+	// Type of the file, one of the model.FileType values.
+	FileType string
+	// Extensions of the file without the dot, any of them may match.
+	Extensions []string
+	// Minimum size of the file in bytes, 0 means no minimum.
+	MinSize int64
+	// Maximum size of the file in bytes, 0 means no maximum.
+	MaxSize int64
+	// Year that should appear in the file name, 0 means any year.
+	Year int
+	// Ids of the chats the file was posted in, any of them may match.
+	// It is set from SearchFilesOpts.Channels and not from the query.
+	ChatIds []int64
+}
+
+// IsEmpty reports whether the filter has no conditions.
+func (f *FileFilter) IsEmpty() bool {
+	return f == nil || (len(f.Phrases) == 0 && len(f.Exclude) == 0 && f.FileType == "" && len(f.Extensions) == 0 && f.MinSize == 0 && f.MaxSize == 0 && f.Year == 0 && len(f.ChatIds) == 0)
+}
+
+// NamePatterns returns patterns that the file name must match and patterns it must not match.
+// The year is matched as a whole word in the name.
+func (f *FileFilter) NamePatterns() (include, exclude []string) {
+	if f == nil {
+		return nil, nil
+	}
+
+	for _, p := range f.Phrases {
+		include = append(include, PhrasePattern(p))
+	}
+
+	if f.Year != 0 {
+		include = append(include, AnyWordPattern([]string{strconv.Itoa(f.Year)}))
+	}
+
+	if len(f.Exclude) != 0 {
+		exclude = append(exclude, AnyWordPattern(f.Exclude))
+	}
+
+	return include, exclude
+}
+
+// Matcher compiles the filter into a function that reports whether a file matches it.
+// Used by backends that filter files in go.
+func (f *FileFilter) Matcher() (func(file *model.File) bool, error) {
+	if f.IsEmpty() {
+		return func(_ *model.File) bool { return true }, nil
+	}
+
+	includeStr, excludeStr := f.NamePatterns()
+
+	include, err := compileAll(includeStr)
+	if err != nil {
+		return nil, err
+	}
+
+	exclude, err := compileAll(excludeStr)
+	if err != nil {
+		return nil, err
+	}
+
+	var ext *regexp.Regexp
+
+	if len(f.Extensions) != 0 {
+		ext, err = regexp.Compile(ExtensionPattern(f.Extensions))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return func(file *model.File) bool {
+		switch {
+		case f.FileType != "" && file.FileType != f.FileType:
+			return false
+		case f.MinSize != 0 && file.FileSize < f.MinSize:
+			return false
+		case f.MaxSize != 0 && file.FileSize > f.MaxSize:
+			return false
+		case ext != nil && !containsString(f.Extensions, file.Extension) && !ext.MatchString(file.FileName):
+			return false
+		case len(f.ChatIds) != 0 && !slices.Contains(f.ChatIds, file.ChatId):
+			return false
+		}
+
+		for _, r := range include {
+			if !r.MatchString(file.FileName) {
+				return false
+			}
+		}
+
+		for _, r := range exclude {
+			if r.MatchString(file.FileName) {
+				return false
+			}
+		}
+
+		return true
+	}, nil
+}
+
+// PhrasePattern builds a case-insensitive regular expression that matches the words of the phrase next to each other, separated by any non alpha-numeric characters.
+// Words are kept in order including repeated ones.
+func PhrasePattern(phrase string) string {
+	var words []string
+
+	for _, w := range wordSplitter.Split(phrase, -1) {
+		if w != "" {
+			words = append(words, regexp.QuoteMeta(w))
+		}
+	}
+
+	return `(?i)(^|[^\p{L}\p{N}])` + strings.Join(words, `[^\p{L}\p{N}]+`) + `($|[^\p{L}\p{N}])`
+}
+
+// ExtensionPattern builds a case-insensitive regular expression that matches file names ending with any of the extensions.
+// Used for files saved without the extension field where the name still contains the extension.
+func ExtensionPattern(extensions []string) string {
+	quoted := make([]string, len(extensions))
+	for i, e := range extensions {
+		quoted[i] = regexp.QuoteMeta(e)
+	}
+
+	return `(?i)\.(` + strings.Join(quoted, "|") + `)$`
+}
+
+func compileAll(patterns []string) ([]*regexp.Regexp, error) {
+	r := make([]*regexp.Regexp, 0, len(patterns))
+
+	for _, p := range patterns {
+		c, err := regexp.Compile(p)
+		if err != nil {
+			return nil, err
+		}
+
+		r = append(r, c)
+	}
+
+	return r, nil
+}
+
+func containsString(l []string, s string) bool {
+	for _, e := range l {
+		if e == s {
+			return true
+		}
+	}
+
+	return false
+}
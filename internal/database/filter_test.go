@@ -0,0 +1,32 @@
+package database_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/Jisin0/autofilterbot/internal/database"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPhrasePattern(t *testing.T) {
+	tests := []struct {
+		phrase   string
+		name     string
+		expected bool
+	}{
+		{phrase: "the dark knight", name: "The.Dark.Knight.2008.mkv", expected: true},
+		{phrase: "the dark knight", name: "The Dark Knight Rises.mkv", expected: true},
+		{phrase: "dark knight", name: "The.Knight.Dark.mkv", expected: false},
+		{phrase: "dark knight", name: "Darker.Knight.mkv", expected: false},
+		{phrase: "the lord of the rings", name: "The.Lord.of.the.Rings.mkv", expected: true},
+		{phrase: "the lord of the rings", name: "The.Lord.of.Rings.mkv", expected: false},
+		{phrase: "c++ basics", name: "[C++] Basics.mp4", expected: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.phrase+"/"+tc.name, func(t *testing.T) {
+			r := regexp.MustCompile(database.PhrasePattern(tc.phrase))
+			assert.Equal(t, tc.expected, r.MatchString(tc.name))
+		})
+	}
+}
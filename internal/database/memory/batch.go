@@ -0,0 +1,111 @@
+package memory
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+
+	"github.com/Jisin0/autofilterbot/internal/database"
+	"github.com/Jisin0/autofilterbot/internal/model"
+)
+
+func (c *Client) SaveBatch(b *model.Batch) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.batches[b.ID]; ok {
+		return errors.New("memory: batch already exists")
+	}
+
+	c.batches[b.ID] = *b
+
+	return nil
+}
+
+func (c *Client) GetBatch(id string) (*model.Batch, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	b, ok := c.batches[id]
+	if !ok {
+		return nil, database.ErrNoDocuments
+	}
+
+	return &b, nil
+}
+
+func (c *Client) GetBatches() ([]*model.Batch, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	batches := make([]*model.Batch, 0, len(c.batches))
+	for _, b := range c.batches {
+		batches = append(batches, &b)
+	}
+
+	sort.SliceStable(batches, func(i, j int) bool { return batches[i].CreatedAt > batches[j].CreatedAt })
+
+	return batches, nil
+}
+
+func (c *Client) UpdateBatch(id string, vals map[string]interface{}) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	b, ok := c.batches[id]
+	if !ok {
+		return nil
+	}
+
+	// fields are updated by their json key, the same way they're set in mongodb
+	data, err := json.Marshal(b)
+	if err != nil {
+		return err
+	}
+
+	doc := make(map[string]interface{})
+
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+
+	for k, v := range vals {
+		doc[k] = v
+	}
+
+	data, err = json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+
+	var updated model.Batch
+
+	if err := json.Unmarshal(data, &updated); err != nil {
+		return err
+	}
+
+	c.batches[id] = updated
+
+	return nil
+}
+
+func (c *Client) IncrementBatchAccesses(id string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if b, ok := c.batches[id]; ok {
+		b.Accesses++
+		c.batches[id] = b
+	}
+
+	return nil
+}
+
+func (c *Client) DeleteBatch(id string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.batches, id)
+
+	return nil
+}
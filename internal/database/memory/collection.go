@@ -0,0 +1,59 @@
+package memory
+
+import (
+	"slices"
+	"sort"
+
+	"github.com/Jisin0/autofilterbot/internal/database"
+	"github.com/Jisin0/autofilterbot/internal/model"
+)
+
+func (c *Client) SaveCollection(coll *model.Collection) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	saved := *coll
+	saved.Files = slices.Clone(coll.Files)
+
+	c.collections[coll.ID] = saved
+
+	return nil
+}
+
+func (c *Client) GetCollection(id string) (*model.Collection, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	coll, ok := c.collections[id]
+	if !ok {
+		return nil, database.ErrNoDocuments
+	}
+
+	coll.Files = slices.Clone(coll.Files)
+
+	return &coll, nil
+}
+
+func (c *Client) GetCollections() ([]*model.Collection, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	collections := make([]*model.Collection, 0, len(c.collections))
+	for _, coll := range c.collections {
+		coll.Files = slices.Clone(coll.Files)
+		collections = append(collections, &coll)
+	}
+
+	sort.SliceStable(collections, func(i, j int) bool { return collections[i].CreatedAt > collections[j].CreatedAt })
+
+	return collections, nil
+}
+
+func (c *Client) DeleteCollection(id string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.collections, id)
+
+	return nil
+}
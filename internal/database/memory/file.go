@@ -0,0 +1,183 @@
+package memory
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Jisin0/autofilterbot/internal/config"
+	"github.com/Jisin0/autofilterbot/internal/database"
+	"github.com/Jisin0/autofilterbot/internal/model"
+)
+
+func (c *Client) SaveFile(f *model.File) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.files[f.UniqueId]; ok {
+		return database.FileAlreadyExistsError{FileName: f.FileName}
+	}
+
+	// Find a file with matching file_id or one that starts with the same file_name and is within a 100 byte range of file_size
+	for _, e := range c.files {
+		if e.FileId == f.FileId || (strings.HasPrefix(e.FileName, f.FileName) && e.FileSize >= f.FileSize-100 && e.FileSize <= f.FileSize+100) {
+			return database.FileAlreadyExistsError{FileName: f.FileName}
+		}
+	}
+
+	c.files[f.UniqueId] = *f
+
+	return nil
+}
+
+func (c *Client) SaveFiles(files ...*model.File) []error {
+	var errs []error
+
+	for _, f := range files {
+		if err := c.SaveFile(f); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errs
+}
+
+func (c *Client) GetFile(fileId string) (*model.File, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	f, ok := c.files[fileId]
+	if !ok {
+		return nil, database.ErrNoDocuments
+	}
+
+	return &f, nil
+}
+
+func (c *Client) DeleteFile(fileId string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.files, fileId)
+
+	return nil
+}
+
+func (c *Client) IncrementDownloads(fileId string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if f, ok := c.files[fileId]; ok {
+		f.Downloads++
+		c.files[fileId] = f
+	}
+
+	return nil
+}
+
+func (c *Client) SearchFiles(query string, opts ...database.SearchFilesOpts) (database.Cursor, error) {
+	var o database.SearchFilesOpts
+	if len(opts) != 0 {
+		o = opts[0]
+	}
+
+	match, err := o.GetFilter().Matcher()
+	if err != nil {
+		return nil, err
+	}
+
+	if o.Mode == config.SearchModeText {
+		return c.rankedSearch(query, match, o.Skip, o.GetLimit())
+	}
+
+	matches, err := c.matchFiles(database.SearchPattern(query), match)
+	if err != nil {
+		return nil, err
+	}
+
+	matches = matches[min(o.Skip, len(matches)):]
+	if len(matches) > o.GetLimit() {
+		matches = matches[:o.GetLimit()]
+	}
+
+	docs := make([]interface{}, len(matches))
+	for i, f := range matches {
+		docs[i] = f
+	}
+
+	return database.NewSliceCursor(docs), nil
+}
+
+func (c *Client) GetAllFiles() (database.Cursor, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	docs := make([]interface{}, 0, len(c.files))
+
+	for _, f := range c.files {
+		docs = append(docs, f)
+	}
+
+	return database.NewSliceCursor(docs), nil
+}
+
+// rankedSearch scores all files against the words of the query, topped up by partial word matches.
+func (c *Client) rankedSearch(query string, match func(*model.File) bool, skip, limit int) (database.Cursor, error) {
+	c.mu.RLock()
+
+	all := make([]model.File, 0, len(c.files))
+
+	for _, f := range c.files {
+		if match(&f) {
+			all = append(all, f)
+		}
+	}
+
+	c.mu.RUnlock()
+
+	ranked := database.RankFiles(database.SearchWords(query), all)
+
+	var fallback []model.File
+
+	if len(ranked) < skip+limit {
+		var err error
+
+		fallback, err = c.matchFiles(database.PartialSearchPattern(query), match)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return database.MergeRanked(ranked, fallback, skip, limit), nil
+}
+
+// matchFiles returns all files with names matching the pattern that also match the filter, newest first.
+func (c *Client) matchFiles(pattern string, match func(*model.File) bool) ([]model.File, error) {
+	r, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.RLock()
+
+	var matches []model.File
+
+	for _, f := range c.files {
+		if r.MatchString(f.FileName) && match(&f) {
+			matches = append(matches, f)
+		}
+	}
+
+	c.mu.RUnlock()
+
+	// ids break ties so that pages are stable between searches
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Time != matches[j].Time {
+			return matches[i].Time > matches[j].Time
+		}
+
+		return matches[i].UniqueId < matches[j].UniqueId
+	})
+
+	return matches, nil
+}
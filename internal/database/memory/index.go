@@ -0,0 +1,105 @@
+package memory
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/Jisin0/autofilterbot/internal/database"
+	"github.com/Jisin0/autofilterbot/internal/model"
+)
+
+// NewIndexOperation inserts a new index operation into the database.
+func (c *Client) NewIndexOperation(i *model.Index) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.operations[i.ID]; ok {
+		return errors.New("memory: operation already exists")
+	}
+
+	c.operations[i.ID] = *i
+
+	return nil
+}
+
+// UpdateIndexOperation updates an index operation.
+// Returns a bool indication wether a match was found and errors.
+func (c *Client) UpdateIndexOperation(pid string, vals map[string]interface{}) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	o, ok := c.operations[pid]
+	if !ok {
+		return false, nil
+	}
+
+	// fields are updated by their json key, the same way they're set in mongodb
+	b, err := json.Marshal(o)
+	if err != nil {
+		return true, err
+	}
+
+	doc := make(map[string]interface{})
+
+	if err := json.Unmarshal(b, &doc); err != nil {
+		return true, err
+	}
+
+	for k, v := range vals {
+		doc[k] = v
+	}
+
+	b, err = json.Marshal(doc)
+	if err != nil {
+		return true, err
+	}
+
+	var updated model.Index
+
+	if err := json.Unmarshal(b, &updated); err != nil {
+		return true, err
+	}
+
+	c.operations[pid] = updated
+
+	return true, nil
+}
+
+// GetIndexOperation fetches an index operation by it's id.
+func (c *Client) GetIndexOperation(pid string) (*model.Index, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	o, ok := c.operations[pid]
+	if !ok {
+		return nil, database.ErrNoDocuments
+	}
+
+	return &o, nil
+}
+
+// GetAllIndexOperations fetches all active index operations.
+func (c *Client) GetActiveIndexOperations() ([]*model.Index, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	ops := make([]*model.Index, 0)
+
+	for _, o := range c.operations {
+		if !o.IsPaused {
+			ops = append(ops, &o)
+		}
+	}
+
+	return ops, nil
+}
+
+// DeleteOperation deletes an active operation by id.
+func (c *Client) DeleteOperation(pid string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.operations, pid)
+
+	return nil
+}
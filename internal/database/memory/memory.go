@@ -0,0 +1,257 @@
+// Package memory implements database.Database by keeping all data in memory.
+// Nothing is persisted across restarts, it is mainly useful for tests and trying out the bot.
+package memory
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+	"sync"
+
+	"github.com/Jisin0/autofilterbot/internal/config"
+	"github.com/Jisin0/autofilterbot/internal/database"
+	"github.com/Jisin0/autofilterbot/internal/model"
+)
+
+// Ensure *Client implements database.Database
+var _ database.Database = (*Client)(nil)
+
+// URLScheme is the scheme of a DATABASE_URL that should be opened in memory.
+const URLScheme = "memory://"
+
+// Client implements database.Database using maps guarded by a mutex.
+type Client struct {
+	mu sync.RWMutex
+
+	users        map[int64]struct{}
+	joinRequests map[int64][]int64
+	files        map[string]model.File
+	configs      map[int64]map[string]json.RawMessage
+	groups       map[int64]map[string]json.RawMessage
+	groupInfo    map[int64]model.Group
+	batches      map[string]model.Batch
+	collections  map[string]model.Collection
+	operations   map[string]model.Index
+}
+
+// NewClient creates a new empty in-memory database.
+func NewClient() *Client {
+	return &Client{
+		users:        make(map[int64]struct{}),
+		joinRequests: make(map[int64][]int64),
+		files:        make(map[string]model.File),
+		configs:      make(map[int64]map[string]json.RawMessage),
+		groups:       make(map[int64]map[string]json.RawMessage),
+		groupInfo:    make(map[int64]model.Group),
+		batches:      make(map[string]model.Batch),
+		collections:  make(map[string]model.Collection),
+		operations:   make(map[string]model.Index),
+	}
+}
+
+func (c *Client) Shutdown() error {
+	return nil
+}
+
+func (c *Client) SaveGroup(g *model.Group) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.groups[g.ID]; !ok {
+		c.groups[g.ID] = make(map[string]json.RawMessage)
+	}
+
+	c.groupInfo[g.ID] = *g
+
+	return nil
+}
+
+func (c *Client) GetGroup(groupId int64) (*model.Group, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	g, ok := c.groupInfo[groupId]
+	if !ok {
+		return nil, database.ErrNoDocuments
+	}
+
+	return &g, nil
+}
+
+func (c *Client) GetGroups() ([]*model.Group, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	groups := make([]*model.Group, 0, len(c.groupInfo))
+	for _, g := range c.groupInfo {
+		groups = append(groups, &g)
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool { return groups[i].AddedAt > groups[j].AddedAt })
+
+	return groups, nil
+}
+
+func (c *Client) UpdateGroupStatus(groupId int64, status string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if g, ok := c.groupInfo[groupId]; ok {
+		g.Status = status
+		c.groupInfo[groupId] = g
+	}
+
+	return nil
+}
+
+func (c *Client) GetGroupConfig(groupId int64) (*config.GroupConfig, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	r := &config.GroupConfig{}
+
+	doc, ok := c.groups[groupId]
+	if !ok {
+		return r, nil
+	}
+
+	b, err := json.Marshal(doc)
+	if err != nil {
+		return r, err
+	}
+
+	return r, json.Unmarshal(b, r)
+}
+
+func (c *Client) UpdateGroupConfig(groupId int64, key string, value interface{}) error {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	doc, ok := c.groups[groupId]
+	if !ok {
+		doc = make(map[string]json.RawMessage)
+		c.groups[groupId] = doc
+	}
+
+	doc[key] = b
+
+	return nil
+}
+
+func (c *Client) ResetGroupConfig(groupId int64, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if doc, ok := c.groups[groupId]; ok {
+		delete(doc, key)
+	}
+
+	return nil
+}
+
+func (c *Client) GetConfig(botId int64) (*config.Config, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	r := &config.Config{}
+
+	doc, ok := c.configs[botId]
+	if !ok {
+		return r, nil
+	}
+
+	b, err := json.Marshal(doc)
+	if err != nil {
+		return r, err
+	}
+
+	return r, json.Unmarshal(b, r)
+}
+
+func (c *Client) UpdateConfig(botId int64, key string, value interface{}) error {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	doc := c.configDocument(botId)
+	doc[key] = b
+
+	return nil
+}
+
+func (c *Client) SaveConfig(botId int64, data *config.Config) error {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	doc := make(map[string]json.RawMessage)
+
+	if err := json.Unmarshal(b, &doc); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.configs[botId]; ok {
+		return errors.New("memory: config already exists")
+	}
+
+	c.configs[botId] = doc
+
+	return nil
+}
+
+func (c *Client) ResetConfig(botId int64, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if doc, ok := c.configs[botId]; ok {
+		delete(doc, key)
+	}
+
+	return nil
+}
+
+// configDocument returns the config document of the bot, creating it if necessary. The caller must hold the lock.
+func (c *Client) configDocument(botId int64) map[string]json.RawMessage {
+	doc, ok := c.configs[botId]
+	if !ok {
+		id, _ := json.Marshal(botId)
+		doc = map[string]json.RawMessage{"_id": id}
+		c.configs[botId] = doc
+	}
+
+	return doc
+}
+
+func (c *Client) Stats() (*model.Stats, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var downloads int64
+	for _, f := range c.files {
+		downloads += f.Downloads
+	}
+
+	return &model.Stats{
+		Users:     int64(len(c.users)),
+		Groups:    int64(len(c.groups)),
+		Files:     int64(len(c.files)),
+		Downloads: downloads,
+	}, nil
+}
+
+func (c *Client) GetName() string {
+	return "Memory"
+}
@@ -0,0 +1,15 @@
+package memory_test
+
+import (
+	"testing"
+
+	"github.com/Jisin0/autofilterbot/internal/database"
+	"github.com/Jisin0/autofilterbot/internal/database/databasetest"
+	"github.com/Jisin0/autofilterbot/internal/database/memory"
+)
+
+func TestConformance(t *testing.T) {
+	databasetest.Run(t, func(_ *testing.T) database.Database {
+		return memory.NewClient()
+	})
+}
@@ -0,0 +1,82 @@
+package memory
+
+import (
+	"slices"
+
+	"github.com/Jisin0/autofilterbot/internal/database"
+	"github.com/Jisin0/autofilterbot/internal/model"
+)
+
+func (c *Client) SaveUser(userId int64) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.users[userId] = struct{}{}
+
+	return nil
+}
+
+func (c *Client) GetUser(userId int64) (*model.User, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if _, ok := c.users[userId]; !ok {
+		return nil, database.ErrNoDocuments
+	}
+
+	return &model.User{UserId: userId}, nil
+}
+
+func (c *Client) DeleteUser(userId int64) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.users, userId)
+
+	return nil
+}
+
+func (c *Client) GetAllUsers() (database.Cursor, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	docs := make([]interface{}, 0, len(c.users))
+
+	for id := range c.users {
+		docs = append(docs, model.User{UserId: id})
+	}
+
+	return database.NewSliceCursor(docs), nil
+}
+
+func (c *Client) SaveUserJoinRequest(userId, chatId int64) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !slices.Contains(c.joinRequests[userId], chatId) {
+		c.joinRequests[userId] = append(c.joinRequests[userId], chatId)
+	}
+
+	return nil
+}
+
+func (c *Client) DeleteUserJoinRequest(userId, chatId int64) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.joinRequests[userId] = slices.DeleteFunc(c.joinRequests[userId], func(id int64) bool { return id == chatId })
+
+	return nil
+}
+
+func (c *Client) GetUserJoinRequests(userId int64) (*model.User, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	requests, ok := c.joinRequests[userId]
+	if !ok {
+		return nil, database.ErrNoDocuments
+	}
+
+	return &model.User{UserId: userId, JoinRequests: slices.Clone(requests)}, nil
+}
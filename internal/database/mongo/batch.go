@@ -0,0 +1,49 @@
+package mongo
+
+import (
+	"github.com/Jisin0/autofilterbot/internal/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func (c *Client) SaveBatch(b *model.Batch) error {
+	_, err := c.batchCollection.InsertOne(c.ctx, b)
+	return err
+}
+
+func (c *Client) GetBatch(id string) (*model.Batch, error) {
+	var b model.Batch
+
+	err := c.batchCollection.FindOne(c.ctx, idFilter(id)).Decode(&b)
+	if err != nil {
+		return nil, err
+	}
+
+	return &b, nil
+}
+
+func (c *Client) GetBatches() ([]*model.Batch, error) {
+	cursor, err := c.batchCollection.Find(c.ctx, bson.D{}, options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}))
+	if err != nil {
+		return nil, err
+	}
+
+	var batches []*model.Batch
+
+	return batches, cursor.All(c.ctx, &batches)
+}
+
+func (c *Client) UpdateBatch(id string, vals map[string]interface{}) error {
+	_, err := c.batchCollection.UpdateOne(c.ctx, idFilter(id), bson.M{"$set": vals})
+	return err
+}
+
+func (c *Client) IncrementBatchAccesses(id string) error {
+	_, err := c.batchCollection.UpdateOne(c.ctx, idFilter(id), bson.M{"$inc": bson.M{"accesses": 1}})
+	return err
+}
+
+func (c *Client) DeleteBatch(id string) error {
+	_, err := c.batchCollection.DeleteOne(c.ctx, idFilter(id))
+	return err
+}
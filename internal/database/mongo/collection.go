@@ -0,0 +1,39 @@
+package mongo
+
+import (
+	"github.com/Jisin0/autofilterbot/internal/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func (c *Client) SaveCollection(coll *model.Collection) error {
+	_, err := c.collectionsCollection.ReplaceOne(c.ctx, idFilter(coll.ID), coll, options.Replace().SetUpsert(true))
+	return err
+}
+
+func (c *Client) GetCollection(id string) (*model.Collection, error) {
+	var coll model.Collection
+
+	err := c.collectionsCollection.FindOne(c.ctx, idFilter(id)).Decode(&coll)
+	if err != nil {
+		return nil, err
+	}
+
+	return &coll, nil
+}
+
+func (c *Client) GetCollections() ([]*model.Collection, error) {
+	cursor, err := c.collectionsCollection.Find(c.ctx, bson.D{}, options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}))
+	if err != nil {
+		return nil, err
+	}
+
+	var collections []*model.Collection
+
+	return collections, cursor.All(c.ctx, &collections)
+}
+
+func (c *Client) DeleteCollection(id string) error {
+	_, err := c.collectionsCollection.DeleteOne(c.ctx, idFilter(id))
+	return err
+}
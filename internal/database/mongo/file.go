@@ -2,11 +2,12 @@ package mongo
 
 import (
 	"context"
-	"strings"
 
+	"github.com/Jisin0/autofilterbot/internal/config"
 	"github.com/Jisin0/autofilterbot/internal/database"
 	"github.com/Jisin0/autofilterbot/internal/model"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
@@ -64,11 +65,128 @@ func (c *Client) DeleteFile(fileId string) error {
 	return err
 }
 
-func (c *Client) SearchFiles(query string) (database.Cursor, error) {
-	pattern := `(?i)(\b|[\.\+\-_])` + strings.ReplaceAll(query, " ", `.*[\s\.\+\-_]`) + `(\b|[\.\+\-_])`
-	pipeline := bson.D{{Key: "file_name", Value: bson.D{{Key: "$regex", Value: pattern}}}}
+func (c *Client) IncrementDownloads(fileId string) error {
+	_, err := c.fileCollection.UpdateOne(c.ctx, idFilter(fileId), bson.M{"$inc": bson.M{"downloads": 1}})
+	return err
+}
+
+func (c *Client) SearchFiles(query string, opts ...database.SearchFilesOpts) (database.Cursor, error) {
+	var o database.SearchFilesOpts
+	if len(opts) != 0 {
+		o = opts[0]
+	}
+
+	if o.Mode == config.SearchModeText {
+		return c.rankedSearch(query, o)
+	}
+
+	return c.regexSearch(database.SearchPattern(query), o.GetFilter(), o.Skip, o.GetLimit())
+}
+
+func (c *Client) GetAllFiles() (database.Cursor, error) {
+	return c.fileCollection.Find(c.ctx, bson.M{})
+}
+
+// regexSearch finds files with names matching the pattern and the filter, newest first.
+func (c *Client) regexSearch(pattern string, f *database.FileFilter, skip, limit int) (database.Cursor, error) {
+	filter := append(bson.D{{Key: "file_name", Value: bson.D{{Key: "$regex", Value: pattern}}}}, filterConditions(f)...)
+
+	return c.fileCollection.Find(context.Background(), filter, options.Find().SetSort(bson.D{{Key: "time", Value: -1}, {Key: "_id", Value: 1}}).SetSkip(int64(skip)).SetLimit(int64(limit)))
+}
+
+// rankedSearch finds files using the text index ranked by their textScore.
+// The results are topped up by a regex search since the text index only matches whole words.
+func (c *Client) rankedSearch(query string, o database.SearchFilesOpts) (database.Cursor, error) {
+	var (
+		ctx = context.Background()
+		// files of both searches upto the end of the requested page are needed to merge them
+		limit = o.Skip + o.GetLimit()
+	)
+
+	ranked, err := c.fileCollection.TextSearch(ctx, query, filterConditions(o.GetFilter()), int64(limit))
+	if err != nil {
+		return nil, err
+	}
+
+	var fallback []model.File
+
+	if len(ranked) < limit {
+		cursor, err := c.regexSearch(database.PartialSearchPattern(query), o.GetFilter(), 0, limit)
+		if err != nil {
+			return nil, err
+		}
+
+		defer cursor.Close(ctx)
+
+		for cursor.Next(ctx) {
+			var f model.File
+
+			if err := cursor.Decode(&f); err != nil {
+				return nil, err
+			}
+
+			fallback = append(fallback, f)
+		}
+	}
+
+	return database.MergeRanked(ranked, fallback, o.Skip, o.GetLimit()), nil
+}
+
+// filterConditions translates the filter into bson conditions that can be appended to a query.
+func filterConditions(f *database.FileFilter) bson.D {
+	if f.IsEmpty() {
+		return nil
+	}
+
+	var (
+		conds   bson.D
+		nameAnd bson.A
+	)
+
+	if f.FileType != "" {
+		conds = append(conds, bson.E{Key: "file_type", Value: f.FileType})
+	}
+
+	if f.MinSize != 0 || f.MaxSize != 0 {
+		size := bson.D{}
+
+		if f.MinSize != 0 {
+			size = append(size, bson.E{Key: "$gte", Value: f.MinSize})
+		}
+
+		if f.MaxSize != 0 {
+			size = append(size, bson.E{Key: "$lte", Value: f.MaxSize})
+		}
+
+		conds = append(conds, bson.E{Key: "file_size", Value: size})
+	}
+
+	if len(f.Extensions) != 0 {
+		conds = append(conds, bson.E{Key: "$or", Value: bson.A{
+			bson.D{{Key: "ext", Value: bson.D{{Key: "$in", Value: f.Extensions}}}},
+			bson.D{{Key: "file_name", Value: bson.D{{Key: "$regex", Value: database.ExtensionPattern(f.Extensions)}}}},
+		}})
+	}
+
+	if len(f.ChatIds) != 0 {
+		conds = append(conds, bson.E{Key: "chat_id", Value: bson.D{{Key: "$in", Value: f.ChatIds}}})
+	}
+
+	include, exclude := f.NamePatterns()
+
+	for _, p := range include {
+		nameAnd = append(nameAnd, bson.D{{Key: "file_name", Value: bson.D{{Key: "$regex", Value: p}}}})
+	}
+
+	for _, p := range exclude {
+		nameAnd = append(nameAnd, bson.D{{Key: "file_name", Value: bson.D{{Key: "$not", Value: primitive.Regex{Pattern: p}}}}})
+	}
+
+	if len(nameAnd) != 0 {
+		conds = append(conds, bson.E{Key: "$and", Value: nameAnd})
+	}
 
-	return c.fileCollection.Find(context.Background(), pipeline, options.Find().SetSort(bson.M{"time": -1}).SetLimit(50))
+	return conds
 }
 
 // fileIdFilter creates a bson filter to match by file_id.
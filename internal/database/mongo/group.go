@@ -1,6 +1,85 @@
 package mongo
 
-func (c *Client) SaveGroup(id int64) error {
-	_, err := c.groupCollection.InsertOne(c.ctx, idFilter(id))
+import (
+	"errors"
+
+	"github.com/Jisin0/autofilterbot/internal/config"
+	"github.com/Jisin0/autofilterbot/internal/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// groupConfigKey is the key of the settings of a group in its document.
+const groupConfigKey = "config"
+
+func (c *Client) SaveGroup(g *model.Group) error {
+	update := bson.D{
+		{Key: "title", Value: g.Title},
+		{Key: "members", Value: g.MemberCount},
+		{Key: "added_by", Value: g.AddedBy},
+		{Key: "added_at", Value: g.AddedAt},
+		{Key: "status", Value: g.Status},
+	}
+
+	_, err := c.groupCollection.UpdateOne(c.ctx, idFilter(g.ID), bson.D{{Key: "$set", Value: update}}, &options.UpdateOptions{Upsert: &boolTrue})
+
+	return err
+}
+
+func (c *Client) GetGroup(groupId int64) (*model.Group, error) {
+	var g model.Group
+
+	err := c.groupCollection.FindOne(c.ctx, idFilter(groupId), options.FindOne().SetProjection(bson.D{{Key: groupConfigKey, Value: 0}})).Decode(&g)
+	if err != nil {
+		return nil, err
+	}
+
+	return &g, nil
+}
+
+func (c *Client) GetGroups() ([]*model.Group, error) {
+	opts := options.Find().SetProjection(bson.D{{Key: groupConfigKey, Value: 0}}).SetSort(bson.D{{Key: "added_at", Value: -1}})
+
+	// groups that only have settings were never saved
+	cursor, err := c.groupCollection.Find(c.ctx, bson.D{{Key: "status", Value: bson.D{{Key: "$exists", Value: true}}}}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var groups []*model.Group
+
+	return groups, cursor.All(c.ctx, &groups)
+}
+
+func (c *Client) UpdateGroupStatus(groupId int64, status string) error {
+	_, err := c.groupCollection.UpdateOne(c.ctx, idFilter(groupId), bson.D{{Key: "$set", Value: bson.D{{Key: "status", Value: status}}}})
+	return err
+}
+
+func (c *Client) GetGroupConfig(groupId int64) (*config.GroupConfig, error) {
+	var doc struct {
+		Config config.GroupConfig `bson:"config"`
+	}
+
+	err := c.groupCollection.FindOne(c.ctx, idFilter(groupId), options.FindOne().SetProjection(bson.D{{Key: groupConfigKey, Value: 1}})).Decode(&doc)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return &config.GroupConfig{}, nil
+		}
+
+		return &config.GroupConfig{}, err
+	}
+
+	return &doc.Config, nil
+}
+
+func (c *Client) UpdateGroupConfig(groupId int64, key string, value interface{}) error {
+	_, err := c.groupCollection.UpdateOne(c.ctx, idFilter(groupId), bson.D{{Key: "$set", Value: bson.D{{Key: groupConfigKey + "." + key, Value: value}}}}, &options.UpdateOptions{Upsert: &boolTrue})
+	return err
+}
+
+func (c *Client) ResetGroupConfig(groupId int64, key string) error {
+	_, err := c.groupCollection.UpdateOne(c.ctx, idFilter(groupId), bson.D{{Key: "$unset", Value: bson.D{{Key: groupConfigKey + "." + key, Value: ""}}}})
 	return err
 }
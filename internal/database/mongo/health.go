@@ -0,0 +1,141 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Jisin0/autofilterbot/internal/database"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.uber.org/zap"
+)
+
+const healthCheckDuration = time.Minute // duration between health checks of all collections
+
+// SetConnectFunc sets the function used to connect the collection at index when it is not connected.
+func (c *MultiCollection) SetConnectFunc(index int, fn ConnectFunc) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.connectFuncs[index] = fn
+}
+
+// Health returns the health of each collection in order of their index.
+func (c *MultiCollection) Health() []database.StorageHealth {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	h := make([]database.StorageHealth, len(c.health))
+	copy(h, c.health)
+
+	return h
+}
+
+// RunHealthChecker periodically pings every collection and reconnects collections that are not connected.
+// onChange is called whenever a collection becomes unhealthy or recovers, it blocks until ctx is cancelled.
+func (c *MultiCollection) RunHealthChecker(ctx context.Context, log *zap.Logger, onChange func(index int, h database.StorageHealth)) {
+	c.mu.Lock()
+	c.onHealthChange = onChange
+	c.mu.Unlock()
+
+	log.Debug("mongo health checker job started")
+
+	ticker := time.NewTicker(healthCheckDuration)
+	defer ticker.Stop()
+
+	for {
+		c.CheckHealth(ctx)
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// CheckHealth pings all collections once and updates their health.
+func (c *MultiCollection) CheckHealth(ctx context.Context) {
+	for i := 0; i < c.Len(); i++ {
+		c.mu.RLock()
+		col, connect, timeout := c.allCollections[i], c.connectFuncs[i], c.queryTimeout
+		c.mu.RUnlock()
+
+		if col == nil {
+			if connect == nil {
+				continue
+			}
+
+			connectCtx, cancel := context.WithTimeout(ctx, timeout)
+			newCol, err := connect(connectCtx)
+			cancel()
+
+			if err != nil {
+				c.log.Debug("multicollection: reconnect failed", zap.Error(err), zap.Int("collection", i))
+				c.setHealth(i, false, err)
+
+				continue
+			}
+
+			c.mu.Lock()
+			c.allCollections[i] = newCol
+			c.mu.Unlock()
+
+			col = newCol
+		}
+
+		pingCtx, cancel := context.WithTimeout(ctx, timeout)
+		start := time.Now()
+		err := col.Database().Client().Ping(pingCtx, readpref.Primary())
+		latency := time.Since(start)
+
+		cancel()
+
+		if ctx.Err() != nil { // app is stopping
+			return
+		}
+
+		c.setHealth(i, err == nil, err)
+
+		if err == nil {
+			c.mu.Lock()
+			c.health[i].Latency = latency
+			c.mu.Unlock()
+		}
+	}
+}
+
+// setHealth updates the health of a collection and reports changes to onHealthChange.
+func (c *MultiCollection) setHealth(index int, healthy bool, err error) {
+	c.mu.Lock()
+
+	h := c.health[index]
+	changed := h.Healthy != healthy
+
+	h.Healthy = healthy
+	h.Error = ""
+	h.CheckedAt = time.Now()
+
+	if err != nil {
+		h.Error = err.Error()
+	}
+
+	if changed {
+		h.Since = h.CheckedAt
+	}
+
+	c.health[index] = h
+	onChange := c.onHealthChange
+
+	c.mu.Unlock()
+
+	if changed && onChange != nil {
+		onChange(index, h)
+	}
+}
+
+// isConnectionError reports whether the error was caused by the database being unreachable.
+func isConnectionError(err error) bool {
+	return mongo.IsNetworkError(err) || mongo.IsTimeout(err) || errors.Is(err, context.DeadlineExceeded)
+}
@@ -0,0 +1,128 @@
+package mongo_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Jisin0/autofilterbot/internal/database"
+	"github.com/Jisin0/autofilterbot/internal/database/mongo"
+	"github.com/stretchr/testify/assert"
+	mongodriver "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// unreachableCollection returns a collection of a server that refuses connections.
+func unreachableCollection(t *testing.T) *mongodriver.Collection {
+	c, err := mongodriver.Connect(context.Background(), options.Client().ApplyURI("mongodb://127.0.0.1:1").SetServerSelectionTimeout(100*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { c.Disconnect(context.Background()) })
+
+	return c.Database("test").Collection("files")
+}
+
+func TestMultiCollectionHealth(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	c := mongo.NewMultiCollection([]*mongodriver.Collection{unreachableCollection(t), nil}, 0, zap.NewNop())
+	c.SetQueryTimeout(200 * time.Millisecond)
+
+	health := c.Health()
+	assert.True(health[0].Healthy, "connected collections are healthy until checked")
+	assert.False(health[1].Healthy, "missing collections are unhealthy")
+
+	var (
+		mu              sync.Mutex
+		connectAttempts int
+		changes         = make(map[int]bool)
+	)
+
+	c.SetConnectFunc(1, func(ctx context.Context) (*mongodriver.Collection, error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		connectAttempts++
+
+		return nil, errors.New("connection refused")
+	})
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	go c.RunHealthChecker(ctx, zap.NewNop(), func(index int, h database.StorageHealth) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		changes[index] = h.Healthy
+	})
+
+	assert.Eventually(func() bool { return c.Health()[1].Error == "connection refused" }, 5*time.Second, 50*time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	health = c.Health()
+	assert.NotEmpty(health[0].Error)
+	assert.Equal("connection refused", health[1].Error)
+	assert.Equal(1, connectAttempts)
+	assert.Equal(map[int]bool{0: false}, changes, "only collections that changed are reported")
+
+	_, err := c.InsertOne(ctx, map[string]string{"_id": "a"})
+	assert.Error(err, "insert should fail when no collection is healthy")
+
+	_, err = c.Find(ctx, map[string]string{})
+	assert.Error(err, "find should fail when no collection is healthy")
+
+	assert.True(database.IsNoDocumentsError(c.FindOne(ctx, map[string]string{}).Err()))
+
+	dr, err := c.DeleteOne(ctx, map[string]string{"_id": "a"})
+	assert.ErrorContains(err, "collection 1: not connected", "delete should not look like a missing document when collections are down")
+	assert.Zero(dr.DeletedCount)
+
+	ur, err := c.UpdateOne(ctx, map[string]string{"_id": "a"}, map[string]any{"$set": map[string]int{"downloads": 1}})
+	assert.ErrorContains(err, "collection 0: ", "update should report collections that failed")
+	assert.Zero(ur.ModifiedCount)
+}
+
+func TestMultiCollectionQueryTimeout(t *testing.T) {
+	assert := assert.New(t)
+
+	c := mongo.NewMultiCollection([]*mongodriver.Collection{nil}, 0, zap.NewNop())
+	assert.Equal(mongo.DefaultQueryTimeout, c.QueryTimeout())
+
+	c.SetQueryTimeout(0)
+	assert.Equal(mongo.DefaultQueryTimeout, c.QueryTimeout(), "zero timeout should be ignored")
+
+	c.SetConnectFunc(0, func(ctx context.Context) (*mongodriver.Collection, error) {
+		return nil, errors.New("connection refused")
+	})
+
+	var wg sync.WaitGroup
+
+	// the timeout is changed while the health checker reads it
+	for i := 1; i <= 10; i++ {
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+			c.SetQueryTimeout(time.Duration(i) * time.Millisecond)
+		}()
+
+		go func() {
+			defer wg.Done()
+			c.CheckHealth(context.Background())
+		}()
+	}
+
+	wg.Wait()
+
+	c.SetQueryTimeout(time.Second)
+	assert.Equal(time.Second, c.QueryTimeout())
+}
@@ -45,7 +45,7 @@ func (c *Client) GetIndexOperation(pid string) (*model.Index, error) {
 
 // GetAllIndexOperations fetches all active index operations.
 func (c *Client) GetActiveIndexOperations() ([]*model.Index, error) {
-	cursor, err := c.opsCollection.Find(c.ctx, bson.M{"is_paused": false})
+	cursor, err := c.opsCollection.Find(c.ctx, bson.M{"is_paused": false, "kind": bson.M{"$exists": false}})
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,157 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Jisin0/autofilterbot/internal/database"
+	"github.com/Jisin0/autofilterbot/internal/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Ensure *Client implements database.Migrator.
+var _ database.Migrator = (*Client)(nil)
+
+// StorageStats returns the number of documents and their size for each file collection.
+func (c *Client) StorageStats(ctx context.Context) ([]database.StorageStats, error) {
+	stats := make([]database.StorageStats, c.fileCollection.Len())
+
+	for i := range stats {
+		col, err := c.fileCollection.Collection(i)
+		if err != nil {
+			return nil, err
+		}
+
+		var res struct {
+			Count int64 `bson:"count"`
+			Size  int64 `bson:"size"`
+		}
+
+		err = col.Database().RunCommand(ctx, bson.D{{Key: "collStats", Value: col.Name()}}).Decode(&res)
+		if err != nil {
+			return nil, err
+		}
+
+		stats[i] = database.StorageStats{Files: res.Count, Size: res.Size}
+	}
+
+	return stats, nil
+}
+
+// FileIDs returns upto limit ids of files in a collection that are greater than after, in ascending order.
+func (c *Client) FileIDs(ctx context.Context, storage int, after string, limit int) ([]string, error) {
+	col, err := c.fileCollection.Collection(storage)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := options.Find().SetSort(bson.M{"_id": 1}).SetLimit(int64(limit)).SetProjection(bson.M{"_id": 1})
+
+	cursor, err := col.Find(ctx, bson.M{"_id": bson.M{"$gt": after}}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var docs []struct {
+		ID string `bson:"_id"`
+	}
+
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, len(docs))
+	for i, d := range docs {
+		ids[i] = d.ID
+	}
+
+	return ids, nil
+}
+
+// MoveFile copies a file to another collection and then deletes it from the original.
+// The copy is always made before deleting so the file is never lost, if it already exists in the target it is only deleted from the source.
+func (c *Client) MoveFile(ctx context.Context, fileId string, from, to int) error {
+	src, err := c.fileCollection.Collection(from)
+	if err != nil {
+		return err
+	}
+
+	dst, err := c.fileCollection.Collection(to)
+	if err != nil {
+		return err
+	}
+
+	raw, err := src.FindOne(ctx, idFilter(fileId)).Raw()
+	if errors.Is(err, mongo.ErrNoDocuments) { // already moved or deleted
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	_, err = dst.InsertOne(ctx, raw)
+	if err != nil && !mongo.IsDuplicateKeyError(err) {
+		return err
+	}
+
+	_, err = src.DeleteOne(ctx, idFilter(fileId))
+
+	return err
+}
+
+// NewMigration inserts a new migration into the operations collection.
+func (c *Client) NewMigration(m *model.Migration) error {
+	m.Kind = model.OperationKindMigration
+
+	_, err := c.opsCollection.InsertOne(c.ctx, m)
+
+	return err
+}
+
+// UpdateMigration updates fields of a migration.
+// Returns a bool indication wether a match was found and errors.
+func (c *Client) UpdateMigration(id string, vals map[string]interface{}) (bool, error) {
+	r, err := c.opsCollection.UpdateOne(c.ctx, migrationFilter(id), bson.M{"$set": bson.M(vals)})
+
+	var ok bool
+
+	if r != nil {
+		ok = r.MatchedCount != 0
+	}
+
+	return ok, err
+}
+
+// GetMigration fetches a migration by it's id.
+func (c *Client) GetMigration(id string) (*model.Migration, error) {
+	res := c.opsCollection.FindOne(c.ctx, migrationFilter(id))
+	if err := res.Err(); err != nil {
+		return nil, err
+	}
+
+	var m model.Migration
+
+	err := res.Decode(&m)
+
+	return &m, err
+}
+
+// GetActiveMigrations fetches all migrations that are not paused.
+func (c *Client) GetActiveMigrations() ([]*model.Migration, error) {
+	cursor, err := c.opsCollection.Find(c.ctx, bson.M{"kind": model.OperationKindMigration, "is_paused": false})
+	if err != nil {
+		return nil, err
+	}
+
+	var ops []*model.Migration
+
+	err = cursor.All(c.ctx, &ops)
+
+	return ops, err
+}
+
+// migrationFilter creates a bson filter to match a migration by id.
+func migrationFilter(id string) bson.D {
+	return bson.D{{Key: "_id", Value: id}, {Key: "kind", Value: model.OperationKindMigration}}
+}
@@ -3,7 +3,10 @@ package mongo
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
+	"time"
 
 	"github.com/Jisin0/autofilterbot/internal/database"
 	"github.com/Jisin0/autofilterbot/internal/model"
@@ -16,6 +19,9 @@ import (
 // Ensure *Client implements database.Database
 var _ database.Database = (*Client)(nil)
 
+// textIndexTimeout is the time allowed for creating the text index of a file collection.
+const textIndexTimeout = time.Minute
+
 // Client implements database.Database using mongodb
 type Client struct {
 	// userCollections stores data about users of the bot.
@@ -28,12 +34,20 @@ type Client struct {
 	configCollection *mongo.Collection
 	// groupCollection contains data about group chats.
 	groupCollection *mongo.Collection
+	// batchCollection stores batches of channel posts shared using links.
+	batchCollection *mongo.Collection
+	// collectionsCollection stores curated collections of files.
+	collectionsCollection *mongo.Collection
 	// Collection of long operations like index.
 	opsCollection *mongo.Collection
 
 	ctx    context.Context
 	client *mongo.Client
 	db     *mongo.Database
+
+	// clients of additional databases, they are added when reconnected by the health checker.
+	additionalClients   []*mongo.Client
+	additionalClientsMu sync.Mutex
 }
 
 // NewClientOpts provides optional parameters to NewClient().
@@ -46,6 +60,8 @@ type NewClientOpts struct {
 	AdditionalURLs []string
 	// Index of the file collection to use for storage, defaults to 0. Can be updated from config panel.
 	MultiCollectionIndex int
+	// Time allowed for a search in a single file collection, defaults to DefaultQueryTimeout.
+	QueryTimeout time.Duration
 }
 
 // NewClient creates a new client and connect to mongodb.
@@ -79,57 +95,116 @@ func NewClient(ctx context.Context, mongodbUri string, log *zap.Logger, opts ...
 	dataBase := mongoClient.Database(databaseName)
 	primaryFileCollection := dataBase.Collection(collectionName)
 
+	client := &Client{
+		ctx:                    ctx,
+		client:                 mongoClient,
+		db:                     dataBase,
+		userCollection:         dataBase.Collection(database.CollectionNameUsers),
+		configCollection:       dataBase.Collection(database.CollectionNameConfigs),
+		groupCollection:        dataBase.Collection(database.CollectionNameGroups),
+		batchCollection:        dataBase.Collection(database.CollectionNameBatches),
+		collectionsCollection:  dataBase.Collection(database.CollectionNameCollections),
+		opsCollection:          dataBase.Collection(database.CollectionNameOperations),
+		joinRequestsCollection: dataBase.Collection(database.CollectionNameJoinRequests),
+	}
+
 	fileCollections := []*mongo.Collection{primaryFileCollection}
+	connectFuncs := make(map[int]ConnectFunc)
 
 	for i, url := range clientOpts.AdditionalURLs {
-		c, err := mongo.Connect(ctx, options.Client().ApplyURI(url))
+		connect := func(ctx context.Context) (*mongo.Collection, error) {
+			c, err := mongo.Connect(ctx, options.Client().ApplyURI(url))
+			if err != nil {
+				return nil, err
+			}
+
+			client.additionalClientsMu.Lock()
+			client.additionalClients = append(client.additionalClients, c)
+			client.additionalClientsMu.Unlock()
+
+			coll := c.Database(databaseName).Collection(collectionName)
+			createTextIndex(ctx, coll, i+1, log)
+
+			return coll, nil
+		}
+
+		// the collection keeps it's index even if it can't be connected, it is retried by the health checker
+		c, err := connect(ctx)
 		if err != nil {
-			log.Warn("mongo: newclient: failed to connect to additional database", zap.Int("num", i+1))
-			continue
+			log.Warn("mongo: newclient: failed to connect to additional database", zap.Int("num", i+1), zap.Error(err))
 		}
 
-		fileCollections = append(fileCollections, c.Database(databaseName).Collection(collectionName))
+		fileCollections = append(fileCollections, c)
+		connectFuncs[i+1] = connect
 	}
 
-	fileCollection := NewMultiCollection(fileCollections, clientOpts.MultiCollectionIndex, log)
+	createTextIndex(ctx, primaryFileCollection, 0, log)
 
-	primaryFileCollection.Indexes().CreateOne(context.TODO(), mongo.IndexModel{Keys: bson.D{{Key: "file_name", Value: "text"}, {Key: "time", Value: 1}}})
+	client.fileCollection = NewMultiCollection(fileCollections, clientOpts.MultiCollectionIndex, log)
+	client.fileCollection.SetQueryTimeout(clientOpts.QueryTimeout)
 
-	client := &Client{
-		ctx:                    ctx,
-		client:                 mongoClient,
-		db:                     dataBase,
-		userCollection:         dataBase.Collection(database.CollectionNameUsers),
-		fileCollection:         fileCollection,
-		configCollection:       dataBase.Collection(database.CollectionNameConfigs),
-		groupCollection:        dataBase.Collection(database.CollectionNameGroups),
-		opsCollection:          dataBase.Collection(database.CollectionNameOperations),
-		joinRequestsCollection: dataBase.Collection(database.CollectionNameJoinRequests),
+	for i, fn := range connectFuncs {
+		client.fileCollection.SetConnectFunc(i, fn)
 	}
 
 	return client, nil
 }
 
+// createTextIndex creates the text index needed for ranked search, it gives up after textIndexTimeout so an unresponsive database doesn't block.
+func createTextIndex(ctx context.Context, coll *mongo.Collection, index int, log *zap.Logger) {
+	ctx, cancel := context.WithTimeout(ctx, textIndexTimeout)
+	defer cancel()
+
+	_, err := coll.Indexes().CreateOne(ctx, mongo.IndexModel{Keys: bson.D{{Key: "file_name", Value: "text"}, {Key: "time", Value: 1}}})
+	if err != nil {
+		log.Warn("mongo: failed to create text index", zap.Int("collection", index), zap.Error(err))
+	}
+}
+
+// Shutdown disconnects the clients of the primary and all additional databases.
 func (c *Client) Shutdown() error {
-	return c.client.Disconnect(context.Background()) // main ctx may already have been cancelled when this is called
+	ctx := context.Background() // main ctx may already have been cancelled when this is called
+
+	allErrors := []error{c.client.Disconnect(ctx)}
+
+	c.additionalClientsMu.Lock()
+	defer c.additionalClientsMu.Unlock()
+
+	for _, client := range c.additionalClients {
+		allErrors = append(allErrors, client.Disconnect(ctx))
+	}
+
+	c.additionalClients = nil
+
+	return errors.Join(allErrors...)
 }
 
-// fileCounts generates a better visual list of file collection counts, when implementing fmt.Stringer, used for stats.
-type fileCounts []int64
+// fileCounts generates a better visual list of file collection counts and their health, when implementing fmt.Stringer, used for stats.
+type fileCounts []fileCount
+
+// fileCount is the number of files in a collection and it's health.
+type fileCount struct {
+	count  int64
+	health database.StorageHealth
+}
 
 func (f fileCounts) String() string {
 	if len(f) == 0 {
 		return "No Collections Found"
 	}
 
-	if len(f) == 1 {
-		return fmt.Sprint(f[0])
+	if len(f) == 1 && f[0].health.Healthy {
+		return fmt.Sprint(f[0].count)
 	}
 
 	var s string
 
 	for i, n := range f {
-		s += fmt.Sprintf("\n├┄┄Collection %d: %d", i, n)
+		if n.health.Healthy {
+			s += fmt.Sprintf("\n├┄┄Collection %d: %d 🟢 %s", i, n.count, n.health.Latency.Round(time.Millisecond))
+		} else {
+			s += fmt.Sprintf("\n├┄┄Collection %d: 🔴 Down since %s", i, n.health.Since.Format("Jan 02 15:04 MST"))
+		}
 	}
 
 	return s
@@ -146,21 +221,39 @@ func (c *Client) Stats() (*model.Stats, error) {
 		return nil, err
 	}
 
-	var files []int64
+	var (
+		health = c.fileCollection.Health()
+		files  = make(fileCounts, len(health))
+	)
+
+	for i, h := range health {
+		files[i].health = h
 
-	for _, coll := range c.fileCollection.allCollections {
-		n, err := coll.EstimatedDocumentCount(c.ctx)
+		if !h.Healthy {
+			continue
+		}
+
+		coll, err := c.fileCollection.Collection(i)
+		if err != nil {
+			continue
+		}
+
+		files[i].count, err = coll.EstimatedDocumentCount(c.ctx)
 		if err != nil {
 			return nil, err
 		}
+	}
 
-		files = append(files, n)
+	downloads, err := c.fileCollection.Sum(c.ctx, "downloads")
+	if err != nil {
+		return nil, err
 	}
 
 	return &model.Stats{
-		Users:  users,
-		Groups: groups,
-		Files:  fileCounts(files),
+		Users:     users,
+		Groups:    groups,
+		Files:     files,
+		Downloads: downloads,
 	}, nil
 }
 
@@ -173,9 +266,17 @@ func (c *Client) UpdateStorageCollection(index int) error {
 	return c.fileCollection.SetStorageCollection(index)
 }
 
-// RunCollectionUpdater is a background job that ensures storageCollection is set to the collection with least documents stored.
-//
-// WARNING: The document count of the collection does not essentially represent the storage usage of the database but the logic depends on the assumption that files will be by far the heaviest collection.
+// RunCollectionUpdater starts the collection updater of the file collections in a new goroutine and returns immediately, it stops when ctx is cancelled.
 func (c *Client) RunCollectionUpdater(ctx context.Context, log *zap.Logger) {
 	go c.fileCollection.RunCollectionUpdater(ctx, log)
 }
+
+// StorageHealth returns the health of each file collection.
+func (c *Client) StorageHealth() []database.StorageHealth {
+	return c.fileCollection.Health()
+}
+
+// RunHealthChecker starts the health checker of the file collections in a new goroutine and returns immediately, onChange is called when a collection goes down or recovers.
+func (c *Client) RunHealthChecker(ctx context.Context, log *zap.Logger, onChange func(index int, h database.StorageHealth)) {
+	go c.fileCollection.RunHealthChecker(ctx, log, onChange)
+}
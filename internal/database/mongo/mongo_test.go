@@ -0,0 +1,46 @@
+package mongo_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/Jisin0/autofilterbot/internal/database"
+	"github.com/Jisin0/autofilterbot/internal/database/databasetest"
+	"github.com/Jisin0/autofilterbot/internal/database/mongo"
+	mongodriver "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// TestConformance runs the database suite against a live mongodb server.
+// It is skipped unless MONGODB_TEST_URI is set, a new database is created and dropped for each test.
+func TestConformance(t *testing.T) {
+	uri := os.Getenv("MONGODB_TEST_URI")
+	if uri == "" {
+		t.Skip("MONGODB_TEST_URI not set")
+	}
+
+	databasetest.Run(t, func(t *testing.T) database.Database {
+		ctx := context.Background()
+		name := fmt.Sprintf("AutoFilterBotTest%d", time.Now().UnixNano())
+
+		c, err := mongo.NewClient(ctx, uri, zap.NewNop(), mongo.NewClientOpts{DatabaseName: name})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		t.Cleanup(func() {
+			c.Shutdown()
+
+			if m, err := mongodriver.Connect(ctx, options.Client().ApplyURI(uri)); err == nil {
+				m.Database(name).Drop(ctx)
+				m.Disconnect(ctx)
+			}
+		})
+
+		return c
+	})
+}
@@ -4,9 +4,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/Jisin0/autofilterbot/internal/database"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.uber.org/zap"
@@ -14,74 +16,259 @@ import (
 
 const (
 	collectionUpdateDuration = 10 * time.Minute // duration every collection update should be run
+	// DefaultQueryTimeout is the time allowed for a query to a single collection before it is skipped.
+	DefaultQueryTimeout = 5 * time.Second
 )
 
 // MultiCollection wraps a number of collections to create a virtual collection that can be effectively queried like a regular mongo collection.
+//
+// Collections may be nil if their database could not be connected to, they are reconnected by the health checker.
+// Reads skip unhealthy collections and writes are redirected to the next healthy collection.
 type MultiCollection struct {
-	// storageCollection is the current collection that will save new documents.
+	mu sync.RWMutex
+
+	// Index number of current storage collection that will save new documents.
 	// It is the collection with least documents and is periodically updated by a background job.
-	storageCollection *mongo.Collection
-	// Index number of current storage collection.
 	storageCollectionIndex int
 	// all file storage collections with collection from primary database at index 0.
 	allCollections []*mongo.Collection
+	// health of each collection.
+	health []database.StorageHealth
+	// functions to connect collections that are not connected, by index.
+	connectFuncs map[int]ConnectFunc
+	// called when the health of a collection changes.
+	onHealthChange func(index int, h database.StorageHealth)
+	// time allowed for a query to a single collection.
+	queryTimeout time.Duration
 
 	log *zap.Logger
 }
 
+// ConnectFunc connects to the database of a collection, used to reconnect collections that failed to connect.
+type ConnectFunc func(ctx context.Context) (*mongo.Collection, error)
+
+// indexedCollection is a collection along with it's index.
+type indexedCollection struct {
+	index int
+	*mongo.Collection
+}
+
 // NewMultiCollection creates a new multi collection and sets the collection at given index as current storage.
+// Collections that are nil are marked as unhealthy until they are connected.
 func NewMultiCollection(allCollections []*mongo.Collection, index int, log *zap.Logger) *MultiCollection {
+	now := time.Now()
+	health := make([]database.StorageHealth, len(allCollections))
+
+	for i, col := range allCollections {
+		health[i] = database.StorageHealth{Healthy: col != nil, Since: now}
+		if col == nil {
+			health[i].Error = "not connected"
+		}
+	}
+
+	if index < 0 || index >= len(allCollections) {
+		index = 0
+	}
+
 	return &MultiCollection{
-		storageCollection:      allCollections[index],
 		storageCollectionIndex: index,
 		allCollections:         allCollections,
+		health:                 health,
+		connectFuncs:           make(map[int]ConnectFunc),
+		queryTimeout:           DefaultQueryTimeout,
 		log:                    log,
 	}
 }
 
+// connected returns all collections that are connected.
+func (c *MultiCollection) connected() []indexedCollection {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	cols := make([]indexedCollection, 0, len(c.allCollections))
+
+	for i, col := range c.allCollections {
+		if col != nil {
+			cols = append(cols, indexedCollection{index: i, Collection: col})
+		}
+	}
+
+	return cols
+}
+
+// disconnectedErrors returns an error for each collection that is not connected, documents in them can't be reached.
+func (c *MultiCollection) disconnectedErrors() []error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var allErrors []error
+
+	for i, col := range c.allCollections {
+		if col == nil {
+			allErrors = append(allErrors, fmt.Errorf("collection %d: not connected", i))
+		}
+	}
+
+	return allErrors
+}
+
+// available returns all collections that are connected and healthy.
+func (c *MultiCollection) available() []indexedCollection {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	cols := make([]indexedCollection, 0, len(c.allCollections))
+
+	for i, col := range c.allCollections {
+		if col != nil && c.health[i].Healthy {
+			cols = append(cols, indexedCollection{index: i, Collection: col})
+		}
+	}
+
+	return cols
+}
+
+// writeTargets returns healthy collections in the order they should be tried for writes.
+// The storage collection is first, followed by the next collections by index.
+func (c *MultiCollection) writeTargets() []indexedCollection {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	n := len(c.allCollections)
+	cols := make([]indexedCollection, 0, n)
+
+	for j := 0; j < n; j++ {
+		i := (c.storageCollectionIndex + j) % n
+
+		if col := c.allCollections[i]; col != nil && c.health[i].Healthy {
+			cols = append(cols, indexedCollection{index: i, Collection: col})
+		}
+	}
+
+	return cols
+}
+
 // InsertOne inserts a single document to the current storage collection.
+// If the storage collection is unreachable the document is inserted into the next healthy collection.
 func (c *MultiCollection) InsertOne(ctx context.Context, document interface{},
 	opts ...*options.InsertOneOptions) (*mongo.InsertOneResult, error) {
-	return c.storageCollection.InsertOne(ctx, document)
+	allErrors := []error{errors.New("multicollection: insertone: no healthy collection available")}
+
+	for _, col := range c.writeTargets() {
+		res, err := col.InsertOne(ctx, document, opts...)
+		if err == nil || !isConnectionError(err) {
+			return res, err
+		}
+
+		c.log.Warn("multicollection: insertone: collection unreachable, trying next", zap.Error(err), zap.Int("collection", col.index))
+		c.setHealth(col.index, false, err)
+
+		allErrors = append(allErrors, err)
+	}
+
+	return nil, errors.Join(allErrors...)
 }
 
-// Find executes a find command and returns a Cursor over the matching documents in the virtual collection.
+// Find executes a find command concurrently in all healthy collections and returns a Cursor over the matching documents in the virtual collection.
+// Documents are merged by the sort option, skip and limit apply to the merged documents instead of each collection.
+// Collections that return an error or time out are skipped, an error is returned only if all of them fail.
 func (c *MultiCollection) Find(ctx context.Context, filter interface{}, opts ...*options.FindOptions) (database.Cursor, error) {
-	cursor := &MultiCursor{
-		log:    c.log,
-		filter: filter,
-		opts:   opts,
+	findOpts := options.MergeFindOptions(opts...)
+
+	// every collection should return enough documents to fill the merged limit after skipping
+	colOpts := *findOpts
+	colOpts.Skip = nil
+
+	if findOpts.Limit != nil && *findOpts.Limit > 0 {
+		var skip int64
+		if findOpts.Skip != nil {
+			skip = *findOpts.Skip
+		}
+
+		colOpts.SetLimit(*findOpts.Limit + skip)
 	}
 
 	var (
-		res *mongo.Cursor
-		err error
+		wg          sync.WaitGroup
+		collections = c.available()
+		timeout     = c.QueryTimeout()
+		cursors     = make([]*mongo.Cursor, len(collections))
+		allErrors   = make([]error, len(collections))
 	)
 
-	for i, col := range c.allCollections {
-		res, err = col.Find(ctx, filter, opts...)
-		if err != nil { // this does not mean no documents are found. If filter was not matched, an empty cursor will be returned.
-			c.log.Debug("multicollection: find: find operation returned error", zap.Error(err), zap.Int("collection", i))
-			continue
+	for i, col := range collections {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			findCtx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+
+			cursors[i], allErrors[i] = col.Find(findCtx, filter, &colOpts)
+			if allErrors[i] != nil {
+				c.log.Debug("multicollection: find: find operation returned error", zap.Error(allErrors[i]), zap.Int("collection", col.index))
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	var found []SourceCursor
+
+	for _, cursor := range cursors {
+		if cursor != nil {
+			found = append(found, cursor)
 		}
+	}
+
+	if len(found) == 0 {
+		return nil, fmt.Errorf("multicollection: find: all collections returned error: %w", errors.Join(allErrors...))
+	}
+
+	mc := NewMultiCursor(ctx, found, findOpts, timeout, c.log)
+	// collections that failed are skipped but reported by Err so the results are known to be partial.
+	// errors of cursors that failed on their first document are already recorded by NewMultiCursor.
+	mc.err = errors.Join(mc.err, errors.Join(allErrors...))
 
-		cursor.currentCursor = res
+	return mc, nil
+}
+
+// TextSearch runs a $text search in every collection and merges the results by their textScore, best matches first.
+// Documents must also match the extra conditions in filter which may be nil.
+// Collections that return an error are skipped, an error is returned only if all of them fail.
+func (c *MultiCollection) TextSearch(ctx context.Context, query string, filter bson.D, limit int64) ([]database.ScoredFile, error) {
+	score := bson.M{"score": bson.M{"$meta": "textScore"}}
+	opts := options.Find().SetProjection(score).SetSort(score).SetLimit(limit)
+	search := append(bson.D{{Key: "$text", Value: bson.M{"$search": query}}}, filter...)
+
+	cursor, err := c.Find(ctx, search, opts)
+	if err != nil {
+		return nil, err
+	}
 
-		if len(c.allCollections) > i+1 {
-			cursor.remainingCollections = c.allCollections[i+1:]
+	defer cursor.Close(ctx)
+
+	var results []database.ScoredFile
+
+	for cursor.Next(ctx) {
+		var f database.ScoredFile
+
+		if err := cursor.Decode(&f); err != nil {
+			return results, err
 		}
 
-		return cursor, err
+		results = append(results, f)
 	}
 
-	return nil, errors.New("multicollection: find: all collections returned error")
+	return results, nil
 }
 
-// FindOne finds a single document in any collection that matches given filter.
+// FindOne finds a single document in any healthy collection that matches given filter.
 func (c *MultiCollection) FindOne(ctx context.Context, filter interface{}, opts ...*options.FindOneOptions) *mongo.SingleResult {
-	var r *mongo.SingleResult
+	r := mongo.NewSingleResultFromDocument(bson.D{}, mongo.ErrNoDocuments, nil)
 
-	for _, col := range c.allCollections {
+	for _, col := range c.available() {
 		r = col.FindOne(ctx, filter, opts...)
 		if r.Err() == nil {
 			return r
@@ -98,7 +285,7 @@ func (c *MultiCollection) DeleteMany(ctx context.Context, filter interface{}, op
 		allErrors []error
 	)
 
-	for _, col := range c.allCollections {
+	for _, col := range c.connected() {
 		r, err := col.DeleteMany(ctx, filter, opts...)
 		if err != nil {
 			allErrors = append(allErrors, err)
@@ -112,21 +299,25 @@ func (c *MultiCollection) DeleteMany(ctx context.Context, filter interface{}, op
 }
 
 // DeleteOne deletes the first document matching the filter in any collection.
-// If the filter does not match any documents, the operation will succeed and a DeleteResult with a DeletedCount of 0 will be returned.
+// If no collection has a matching document, a DeleteResult with a DeletedCount of 0 is returned with the errors of collections that failed or are not connected.
 func (c *MultiCollection) DeleteOne(ctx context.Context, filter interface{}, opts ...*options.DeleteOptions) (*mongo.DeleteResult, error) {
-	for i, col := range c.allCollections {
+	allErrors := c.disconnectedErrors()
+
+	for _, col := range c.connected() {
 		res, err := col.DeleteOne(ctx, filter, opts...)
 		if err != nil {
-			c.log.Error("multicollection: deleteone failed", zap.Int("index", i), zap.Error(err))
+			c.log.Error("multicollection: deleteone failed", zap.Int("index", col.index), zap.Error(err))
+			allErrors = append(allErrors, fmt.Errorf("collection %d: %w", col.index, err))
+
 			continue
 		}
 
 		if res.DeletedCount > 0 {
-			return res, err
+			return res, nil
 		}
 	}
 
-	return &mongo.DeleteResult{}, nil
+	return &mongo.DeleteResult{}, errors.Join(allErrors...)
 }
 
 // UpdateMany updates all documents that match filter in every collection.
@@ -138,7 +329,7 @@ func (c *MultiCollection) UpdateMany(ctx context.Context, filter, update interfa
 		allErrors []error
 	)
 
-	for _, col := range c.allCollections {
+	for _, col := range c.connected() {
 		res, err := col.UpdateMany(ctx, filter, update, opts...)
 		if err != nil {
 			allErrors = append(allErrors, err)
@@ -155,30 +346,62 @@ func (c *MultiCollection) UpdateMany(ctx context.Context, filter, update interfa
 }
 
 // UpdateOne updates the first document matching the filter in any collection.
-// If the filter does not match any documents, the operation will succeed and a UpdateResult with a ModifiedCount of 0 will be returned.
+// If no collection has a matching document, a UpdateResult with a ModifiedCount of 0 is returned with the errors of collections that failed or are not connected.
 func (c *MultiCollection) UpdateOne(ctx context.Context, filter, update interface{}, opts ...*options.UpdateOptions) (*mongo.UpdateResult, error) {
-	for i, col := range c.allCollections {
+	allErrors := c.disconnectedErrors()
+
+	for _, col := range c.connected() {
 		res, err := col.UpdateOne(ctx, filter, update, opts...)
 		if err != nil {
-			c.log.Error("multicollection updateone failed", zap.Int("index", i), zap.Error(err))
+			c.log.Error("multicollection: updateone failed", zap.Int("index", col.index), zap.Error(err))
+			allErrors = append(allErrors, fmt.Errorf("collection %d: %w", col.index, err))
+
 			continue
 		}
 
 		if res.ModifiedCount > 0 {
-			return res, err
+			return res, nil
+		}
+	}
+
+	return &mongo.UpdateResult{}, errors.Join(allErrors...)
+}
+
+// Sum returns the total of a numeric field across all documents in all healthy collections.
+func (c *MultiCollection) Sum(ctx context.Context, field string) (int64, error) {
+	pipeline := mongo.Pipeline{{{Key: "$group", Value: bson.M{"_id": nil, "total": bson.M{"$sum": "$" + field}}}}}
+
+	var total int64
+
+	for _, col := range c.available() {
+		cursor, err := col.Aggregate(ctx, pipeline)
+		if err != nil {
+			return total, err
+		}
+
+		var results []struct {
+			Total int64 `bson:"total"`
+		}
+
+		if err := cursor.All(ctx, &results); err != nil {
+			return total, err
+		}
+
+		for _, r := range results {
+			total += r.Total
 		}
 	}
 
-	return &mongo.UpdateResult{}, nil
+	return total, nil
 }
 
-// EstimatedDocumentCount executes a count command and returns an estimate of the total number of documents in all collections using collection metadata.
+// EstimatedDocumentCount executes a count command and returns an estimate of the total number of documents in all healthy collections using collection metadata.
 //
 // An error in any collectino will end with the accumulated total and error being returned immediately.
 func (c *MultiCollection) EstimatedDocumentCount(ctx context.Context, opts ...*options.EstimatedDocumentCountOptions) (int64, error) {
 	var total int64
 
-	for _, col := range c.allCollections {
+	for _, col := range c.available() {
 		n, err := col.EstimatedDocumentCount(ctx, opts...)
 		if err != nil {
 			return total, err
@@ -192,56 +415,45 @@ func (c *MultiCollection) EstimatedDocumentCount(ctx context.Context, opts ...*o
 
 // RunCollectionUpdater is a background job that ensures storageCollection is set to the collection with least documents stored.
 //
+// It blocks until ctx is cancelled.
+//
 // WARNING: The document count of the collection does not essentially represent the storage usage of the database but the logic depends on the assumption that files will be by far the heaviest collection.
 func (c *MultiCollection) RunCollectionUpdater(ctx context.Context, log *zap.Logger) {
-	if len(c.allCollections) == 1 { // hopefully isnt 0 lol
+	if c.Len() == 1 { // hopefully isnt 0 lol
 		return
 	}
 
 	log.Debug("mongo collection updater job started")
 
 	ticker := time.NewTicker(collectionUpdateDuration)
+	defer ticker.Stop()
 
 	for {
 		select {
 		case <-ticker.C:
-			currentCount, err := c.storageCollection.EstimatedDocumentCount(ctx)
-			if err != nil {
-				log.Error("failed to get document count for current storage collection", zap.Error(err))
-				continue
-			}
-
 			var (
-				smallestDocumentCount      = currentCount
-				smallestDocumentCollection = c.storageCollection
-
-				i   int
-				col *mongo.Collection
+				smallestDocumentCount int64 = -1
+				smallestIndex               = -1
 			)
 
-			for i, col = range c.allCollections {
-				if col == c.storageCollection { // skip current storage collection
-					continue
-				}
-
+			for _, col := range c.available() {
 				count, err := col.EstimatedDocumentCount(ctx)
 				if err != nil {
-					log.Error("failed to get document count for collection", zap.Int("index", i), zap.Error(err))
+					log.Error("failed to get document count for collection", zap.Int("index", col.index), zap.Error(err))
 					continue
 				}
 
-				if count < smallestDocumentCount {
+				if smallestIndex == -1 || count < smallestDocumentCount {
 					smallestDocumentCount = count
-					smallestDocumentCollection = col
+					smallestIndex = col.index
 				}
 			}
 
 			// If smallest collection is different from current then update
-			if smallestDocumentCollection != c.storageCollection {
-				c.storageCollection = smallestDocumentCollection
-				c.storageCollectionIndex = i
-
-				log.Debug("multicollection: updated storage collection", zap.Int("index", i))
+			if smallestIndex != -1 && smallestIndex != c.StorageIndex() {
+				if err := c.SetStorageCollection(smallestIndex); err == nil {
+					log.Debug("multicollection: updated storage collection", zap.Int("index", smallestIndex))
+				}
 			}
 		case <-ctx.Done():
 			return
@@ -251,12 +463,66 @@ func (c *MultiCollection) RunCollectionUpdater(ctx context.Context, log *zap.Log
 
 // SetStorageCollection sets the collection with given index for storing new files.
 func (c *MultiCollection) SetStorageCollection(index int) error {
-	if len(c.allCollections) <= index {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if index < 0 || len(c.allCollections) <= index {
 		return fmt.Errorf("multicolllection: setstorage: index %d out of range with length %d", index, len(c.allCollections))
 	}
 
-	c.storageCollection = c.allCollections[index]
 	c.storageCollectionIndex = index
 
 	return nil
 }
+
+// StorageIndex returns the index of the current storage collection.
+func (c *MultiCollection) StorageIndex() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.storageCollectionIndex
+}
+
+// SetQueryTimeout sets the time allowed for a query to a single collection, slower collections are skipped by Find.
+func (c *MultiCollection) SetQueryTimeout(d time.Duration) {
+	if d <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.queryTimeout = d
+}
+
+// QueryTimeout returns the time allowed for a query to a single collection.
+func (c *MultiCollection) QueryTimeout() time.Duration {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.queryTimeout
+}
+
+// Collection returns the collection at index.
+func (c *MultiCollection) Collection(index int) (*mongo.Collection, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if index < 0 || len(c.allCollections) <= index {
+		return nil, fmt.Errorf("multicolllection: collection: index %d out of range with length %d", index, len(c.allCollections))
+	}
+
+	if c.allCollections[index] == nil {
+		return nil, fmt.Errorf("multicolllection: collection: collection %d is not connected", index)
+	}
+
+	return c.allCollections[index], nil
+}
+
+// Len returns the number of collections.
+func (c *MultiCollection) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return len(c.allCollections)
+}
@@ -1,10 +1,17 @@
 package mongo
 
 import (
+	"cmp"
+	"container/heap"
 	"context"
+	"errors"
+	"fmt"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/Jisin0/autofilterbot/internal/database"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.uber.org/zap"
@@ -13,72 +20,101 @@ import (
 // Ensure *MultiCursor implements database.Cursor.
 var _ database.Cursor = (*MultiCursor)(nil)
 
+// SourceCursor is a cursor of a single collection merged by MultiCursor, implemented by *mongo.Cursor.
+type SourceCursor interface {
+	Next(ctx context.Context) bool
+	Decode(v interface{}) error
+	Err() error
+	Close(ctx context.Context) error
+}
+
 // MultiCursor orchestrates mongodb queries to multiple collections as a single virtual colllection implementing database.Cursor.
+//
+// Cursors of all collections are merged by the sort order of the query so documents are returned in the same order as they would from a single collection.
+// Skip and limit are applied to the merged documents.
 type MultiCursor struct {
 	mu  sync.Mutex
 	log *zap.Logger
 
-	// current cursor that is being queried and data being fetched.
-	currentCursor *mongo.Cursor
-	// collections that have not been queried yet.
-	remainingCollections []*mongo.Collection
-	// filter to query collections.
-	filter interface{}
-	// find options
-	opts []*options.FindOptions
+	// cursors that have a document loaded ordered by the sort keys.
+	sources cursorHeap
+	// cursor of the current document, it is advanced on the next call to Next.
+	current *cursorSource
+	// time allowed for loading the next batch from a single collection.
+	timeout time.Duration
+	// errors of collections that failed, their remaining documents are skipped.
+	err error
+
+	skip     int64
+	limit    int64
+	returned int64
 }
 
-// Next loads the next document into the current cursor or quries the next collection if available. It returns false if all collections were exhausted.
-func (c *MultiCursor) Next(ctx context.Context) bool {
-	if c.currentCursor == nil { // should never happen in a perfect world
-		c.log.Warn("multicursor: next: current cursor is nil", zap.Any("query", c.filter))
-		return false
+// NewMultiCursor merges the cursors into a single cursor using the sort, skip and limit set in opts.
+// Cursors should be ordered by priority, documents with equal sort keys are returned from cursors with a lower index first.
+// If timeout is not zero, it limits the time spent loading each batch from a single cursor.
+func NewMultiCursor(ctx context.Context, cursors []SourceCursor, opts *options.FindOptions, timeout time.Duration, log *zap.Logger) *MultiCursor {
+	c := &MultiCursor{
+		log:     log,
+		timeout: timeout,
 	}
 
-	c.mu.Lock()
-	defer c.mu.Unlock()
+	var keys []sortKey
 
-	if c.currentCursor.Next(ctx) {
-		return true
-	}
+	if opts != nil {
+		keys = sortKeysFromOption(opts.Sort)
+
+		if opts.Skip != nil {
+			c.skip = *opts.Skip
+		}
 
-	if len(c.remainingCollections) == 0 {
-		return false
+		if opts.Limit != nil {
+			c.limit = *opts.Limit
+		}
 	}
 
-	var (
-		res *mongo.Cursor
-		err error
-	)
+	for i, cursor := range cursors {
+		s := &cursorSource{index: i, cursor: cursor, keys: keys}
 
-	for i, col := range c.remainingCollections {
-		res, err = col.Find(ctx, c.filter, c.opts...) // should this ctx be used? maybe pass ctx to MultiCursor from Find and use the same
-		if err != nil {
-			c.log.Debug("multicursor: next: find operation failed", zap.Error(err))
-			continue
+		if c.advance(ctx, s) {
+			c.sources = append(c.sources, s)
 		}
+	}
 
-		if res.Next(ctx) { // If cursor provides a document update currentCursor and cut remainingCollections
-			if err := c.currentCursor.Close(ctx); err != nil { // close current cursor
-				c.log.Error("multicursor: next: failed to close current cursor", zap.Error(err))
-			}
+	heap.Init(&c.sources)
 
-			c.currentCursor = res
+	return c
+}
 
-			if len(c.remainingCollections) > i+1 {
-				c.remainingCollections = c.remainingCollections[i+1:]
-			} else if len(c.remainingCollections) == i+1 {
-				c.remainingCollections = nil
+// Next loads the next document in the merged order. It returns false if all collections were exhausted or the limit was reached.
+func (c *MultiCursor) Next(ctx context.Context) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for {
+		if c.current != nil {
+			if c.advance(ctx, c.current) {
+				heap.Push(&c.sources, c.current)
 			}
 
-			return true
+			c.current = nil
 		}
-	}
 
-	// all collections are exhausted so empty remainingCollections just in case and return false
-	c.remainingCollections = nil
+		if (c.limit > 0 && c.returned >= c.limit) || len(c.sources) == 0 {
+			return false
+		}
 
-	return false
+		c.current = heap.Pop(&c.sources).(*cursorSource)
+
+		if c.skip > 0 {
+			c.skip--
+			continue
+		}
+
+		c.returned++
+
+		return true
+	}
 }
 
 // Decode unmarshals the current document into the value pointed to by v.
@@ -87,7 +123,19 @@ func (c *MultiCursor) Decode(v interface{}) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	return c.currentCursor.Decode(v)
+	if c.current == nil {
+		return errors.New("multicursor: decode: no document loaded")
+	}
+
+	return c.current.cursor.Decode(v)
+}
+
+// Err returns the errors of collections whose cursors failed, documents after the failure were not returned.
+func (c *MultiCursor) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.err
 }
 
 // Close closes the cursor, releasing any resources associated with it.
@@ -96,5 +144,196 @@ func (c *MultiCursor) Close(ctx context.Context) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	return c.currentCursor.Close(ctx)
+	var allErrors []error
+
+	if c.current != nil {
+		allErrors = append(allErrors, c.current.cursor.Close(ctx))
+		c.current = nil
+	}
+
+	for _, s := range c.sources {
+		allErrors = append(allErrors, s.cursor.Close(ctx))
+	}
+
+	c.sources = nil
+
+	return errors.Join(allErrors...)
+}
+
+// advance loads the next document of the source and reports whether one was found.
+// Exhausted or failing sources are closed.
+func (c *MultiCursor) advance(ctx context.Context, s *cursorSource) bool {
+	nextCtx := ctx
+
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+
+		nextCtx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	if s.cursor.Next(nextCtx) {
+		if err := s.loadKeys(); err != nil {
+			c.err = errors.Join(c.err, fmt.Errorf("collection %d: %w", s.index, err))
+			c.log.Warn("multicursor: next: decode sort keys failed", zap.Error(err), zap.Int("collection", s.index))
+		} else {
+			return true
+		}
+	} else if err := s.cursor.Err(); err != nil {
+		c.err = errors.Join(c.err, fmt.Errorf("collection %d: %w", s.index, err))
+		c.log.Warn("multicursor: next: collection cursor failed", zap.Error(err), zap.Int("collection", s.index))
+	}
+
+	if err := s.cursor.Close(ctx); err != nil {
+		c.log.Debug("multicursor: next: failed to close cursor", zap.Error(err), zap.Int("collection", s.index))
+	}
+
+	return false
+}
+
+// sortKey is a single field in the sort option of a query.
+type sortKey struct {
+	path []string
+	// 1 for ascending and -1 for descending.
+	order int
+}
+
+// sortKeysFromOption parses the sort option of find. Sorting by $meta like textScore is always descending.
+func sortKeysFromOption(sort interface{}) []sortKey {
+	var fields bson.D
+
+	switch s := sort.(type) {
+	case bson.D:
+		fields = s
+	case bson.M:
+		for k, v := range s {
+			fields = append(fields, bson.E{Key: k, Value: v})
+		}
+	}
+
+	keys := make([]sortKey, 0, len(fields))
+
+	for _, f := range fields {
+		order := 1
+
+		switch v := f.Value.(type) {
+		case int:
+			order = cmp.Compare(v, 0)
+		case int32:
+			order = cmp.Compare(v, 0)
+		case int64:
+			order = cmp.Compare(v, 0)
+		case float64:
+			order = cmp.Compare(v, 0)
+		case bson.M, bson.D:
+			order = -1
+		}
+
+		keys = append(keys, sortKey{path: strings.Split(f.Key, "."), order: order})
+	}
+
+	return keys
+}
+
+// cursorSource is a cursor from a single collection with the sort values of it's current document.
+type cursorSource struct {
+	index  int
+	cursor SourceCursor
+	keys   []sortKey
+	values []bson.RawValue
+}
+
+// loadKeys reads the sort values of the current document.
+func (s *cursorSource) loadKeys() error {
+	s.values = s.values[:0]
+
+	if len(s.keys) == 0 {
+		return nil
+	}
+
+	var doc bson.Raw
+
+	if m, ok := s.cursor.(*mongo.Cursor); ok {
+		doc = m.Current
+	} else if err := s.cursor.Decode(&doc); err != nil {
+		return err
+	}
+
+	for _, k := range s.keys {
+		s.values = append(s.values, doc.Lookup(k.path...))
+	}
+
+	return nil
+}
+
+// compare compares the current documents of two sources using the sort keys.
+func (s *cursorSource) compare(other *cursorSource) int {
+	for i, k := range s.keys {
+		if c := compareValues(s.values[i], other.values[i]); c != 0 {
+			return c * k.order
+		}
+	}
+
+	return cmp.Compare(s.index, other.index)
+}
+
+// compareValues compares numbers and strings, missing values are ordered first like in mongodb.
+// Values of other types are considered equal.
+func compareValues(a, b bson.RawValue) int {
+	if x, ok := numericValue(a); ok {
+		if y, ok := numericValue(b); ok {
+			return cmp.Compare(x, y)
+		}
+	}
+
+	if x, ok := a.StringValueOK(); ok {
+		if y, ok := b.StringValueOK(); ok {
+			return strings.Compare(x, y)
+		}
+	}
+
+	aMissing := a.Type == 0 || a.Type == bson.TypeNull
+	bMissing := b.Type == 0 || b.Type == bson.TypeNull
+
+	switch {
+	case aMissing && !bMissing:
+		return -1
+	case !aMissing && bMissing:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func numericValue(v bson.RawValue) (float64, bool) {
+	switch v.Type {
+	case bson.TypeInt32:
+		return float64(v.Int32()), true
+	case bson.TypeInt64:
+		return float64(v.Int64()), true
+	case bson.TypeDouble:
+		return v.Double(), true
+	default:
+		return 0, false
+	}
+}
+
+// cursorHeap implements heap.Interface ordering sources by their current document.
+type cursorHeap []*cursorSource
+
+func (h cursorHeap) Len() int           { return len(h) }
+func (h cursorHeap) Less(i, j int) bool { return h[i].compare(h[j]) < 0 }
+func (h cursorHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+func (h *cursorHeap) Push(x any) {
+	*h = append(*h, x.(*cursorSource))
+}
+
+func (h *cursorHeap) Pop() any {
+	old := *h
+	n := len(old)
+	s := old[n-1]
+	*h = old[:n-1]
+
+	return s
 }
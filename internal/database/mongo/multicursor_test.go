@@ -0,0 +1,185 @@
+package mongo_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Jisin0/autofilterbot/internal/database/mongo"
+	"github.com/Jisin0/autofilterbot/internal/model"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	mongodriver "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// newCursors creates a cursor for each list of documents, documents are identified by their id and time.
+func newCursors(t *testing.T, times ...[]int64) []mongo.SourceCursor {
+	cursors := make([]mongo.SourceCursor, 0, len(times))
+
+	for i, l := range times {
+		docs := make([]interface{}, len(l))
+		for j, n := range l {
+			docs[j] = bson.D{{Key: "_id", Value: string(rune('a'+i)) + string(rune('0'+j))}, {Key: "time", Value: n}}
+		}
+
+		c, err := mongodriver.NewCursorFromDocuments(docs, nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		cursors = append(cursors, c)
+	}
+
+	return cursors
+}
+
+func TestMultiCursor(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     *options.FindOptions
+		times    [][]int64
+		expected []string
+	}{
+		{
+			name:     "descending",
+			opts:     options.Find().SetSort(bson.M{"time": -1}),
+			times:    [][]int64{{9, 5, 1}, {8, 7, 2}, {6}},
+			expected: []string{"a0", "b0", "b1", "c0", "a1", "b2", "a2"},
+		},
+		{
+			name:     "ascending with limit",
+			opts:     options.Find().SetSort(bson.D{{Key: "time", Value: 1}}).SetLimit(3),
+			times:    [][]int64{{1, 5}, {2, 3}},
+			expected: []string{"a0", "b0", "b1"},
+		},
+		{
+			name:     "skip and limit",
+			opts:     options.Find().SetSort(bson.M{"time": -1}).SetSkip(2).SetLimit(2),
+			times:    [][]int64{{9, 5, 1}, {8, 7, 2}},
+			expected: []string{"b1", "a1"},
+		},
+		{
+			name:     "equal keys keep collection order",
+			opts:     options.Find().SetSort(bson.M{"time": -1}),
+			times:    [][]int64{{1}, {1}, {1}},
+			expected: []string{"a0", "b0", "c0"},
+		},
+		{
+			name:     "unsorted",
+			opts:     options.Find(),
+			times:    [][]int64{{1, 2}, {}, {3}},
+			expected: []string{"a0", "a1", "c0"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			c := mongo.NewMultiCursor(ctx, newCursors(t, tc.times...), tc.opts, 0, zap.NewNop())
+
+			var ids []string
+
+			for c.Next(ctx) {
+				var f model.File
+
+				assert.NoError(t, c.Decode(&f))
+
+				ids = append(ids, f.UniqueId)
+			}
+
+			assert.NoError(t, c.Close(ctx))
+			assert.Equal(t, tc.expected, ids)
+		})
+	}
+}
+
+// failingCursor returns its documents and then fails as if the collection timed out.
+type failingCursor struct {
+	docs    []bson.Raw
+	current int
+}
+
+var errCollectionTimeout = errors.New("collection timed out")
+
+func (c *failingCursor) Next(_ context.Context) bool {
+	c.current++
+	return c.current < len(c.docs)
+}
+
+func (c *failingCursor) Decode(v interface{}) error {
+	return bson.Unmarshal(c.docs[c.current], v)
+}
+
+func (c *failingCursor) Err() error {
+	if c.current >= len(c.docs) {
+		return errCollectionTimeout
+	}
+
+	return nil
+}
+
+func (c *failingCursor) Close(_ context.Context) error {
+	return nil
+}
+
+func TestMultiCursorSourceFails(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	failing := &failingCursor{current: -1}
+	for _, doc := range []bson.D{{{Key: "_id", Value: "f0"}, {Key: "time", Value: 8}}, {{Key: "_id", Value: "f1"}, {Key: "time", Value: 4}}} {
+		b, err := bson.Marshal(doc)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		failing.docs = append(failing.docs, b)
+	}
+
+	cursors := newCursors(t, []int64{9, 5, 1})
+	cursors = append(cursors, failing)
+
+	c := mongo.NewMultiCursor(ctx, cursors, options.Find().SetSort(bson.M{"time": -1}), 0, zap.NewNop())
+
+	var ids []string
+
+	for c.Next(ctx) {
+		var f model.File
+
+		assert.NoError(c.Decode(&f))
+
+		ids = append(ids, f.UniqueId)
+	}
+
+	assert.Equal([]string{"a0", "f0", "a1", "f1", "a2"}, ids, "documents before the failure are returned")
+	assert.ErrorIs(c.Err(), errCollectionTimeout)
+	assert.NoError(c.Close(ctx))
+}
+
+func TestMultiCursorSourceFailsFirst(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	cursors := newCursors(t, []int64{9, 5})
+	cursors = append(cursors, &failingCursor{current: -1})
+
+	c := mongo.NewMultiCursor(ctx, cursors, options.Find().SetSort(bson.M{"time": -1}), 0, zap.NewNop())
+	assert.ErrorIs(c.Err(), errCollectionTimeout, "failure while loading the first document should be reported")
+
+	var ids []string
+
+	for c.Next(ctx) {
+		var f model.File
+
+		assert.NoError(c.Decode(&f))
+
+		ids = append(ids, f.UniqueId)
+	}
+
+	assert.Equal([]string{"a0", "a1"}, ids)
+	assert.ErrorIs(c.Err(), errCollectionTimeout)
+	assert.NoError(c.Close(ctx))
+}
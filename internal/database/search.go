@@ -0,0 +1,198 @@
+package database
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Jisin0/autofilterbot/internal/model"
+)
+
+// SearchLimit is the default number of files returned by SearchFiles.
+const SearchLimit = 50
+
+// SearchFilesOpts are optional parameters to SearchFiles.
+type SearchFilesOpts struct {
+	// Mode is the search mode to use, one of the config.SearchMode values. Defaults to regex search.
+	Mode string
+	// Filter optionally narrows down the results.
+	Filter *FileFilter
+	// Skip is the number of files to skip from the start of the results, used to fetch later pages.
+	Skip int
+	// Limit is the maximum number of files to return. Defaults to SearchLimit.
+	Limit int
+	// Channels limits results to files posted in these channels, files from all channels are searched if empty.
+	Channels []int64
+}
+
+// GetFilter returns the filter with the channels added to it, backends should use it instead of Filter.
+func (o SearchFilesOpts) GetFilter() *FileFilter {
+	if len(o.Channels) == 0 {
+		return o.Filter
+	}
+
+	var f FileFilter
+	if o.Filter != nil {
+		f = *o.Filter
+	}
+
+	f.ChatIds = o.Channels
+
+	return &f
+}
+
+// GetLimit returns the limit or SearchLimit if it is not set.
+func (o SearchFilesOpts) GetLimit() int {
+	if o.Limit > 0 {
+		return o.Limit
+	}
+
+	return SearchLimit
+}
+
+// SearchPattern builds the case-insensitive regular expression used to match file names against a sanitized query.
+// Words in the query may be separated by any number of characters in the file name.
+func SearchPattern(query string) string {
+	return PartialSearchPattern(query) + `(\b|[\.\+\-_])`
+}
+
+// PartialSearchPattern is like SearchPattern but the last word of the query may only be the start of a word, used to fallback from ranked search.
+func PartialSearchPattern(query string) string {
+	return `(?i)(\b|[\.\+\-_])` + strings.ReplaceAll(query, " ", `.*[\s\.\+\-_]`)
+}
+
+// wordSplitter splits file names and queries into words.
+var wordSplitter = regexp.MustCompile(`[^\p{L}\p{N}]+`)
+
+// SearchWords returns the unique lowercase words in s.
+func SearchWords(s string) []string {
+	var (
+		words []string
+		seen  = make(map[string]bool)
+	)
+
+	for _, w := range wordSplitter.Split(strings.ToLower(s), -1) {
+		if w == "" || seen[w] {
+			continue
+		}
+
+		seen[w] = true
+
+		words = append(words, w)
+	}
+
+	return words
+}
+
+// AnyWordPattern builds a case-insensitive regular expression that matches file names containing any of the words as a whole word.
+func AnyWordPattern(words []string) string {
+	quoted := make([]string, len(words))
+	for i, w := range words {
+		quoted[i] = regexp.QuoteMeta(w)
+	}
+
+	return `(?i)(^|[^\p{L}\p{N}])(` + strings.Join(quoted, "|") + `)($|[^\p{L}\p{N}])`
+}
+
+// TextScore scores a file name by the words of the query it contains, shorter names score higher for the same number of matches.
+// This mimics the textScore of mongodb for backends without a text index.
+func TextScore(words []string, fileName string) float64 {
+	nameWords := SearchWords(fileName)
+	if len(nameWords) == 0 {
+		return 0
+	}
+
+	var matched int
+
+	for _, w := range words {
+		for _, n := range nameWords {
+			if w == n {
+				matched++
+				break
+			}
+		}
+	}
+
+	if matched == 0 {
+		return 0
+	}
+
+	return float64(matched) + float64(matched)/float64(len(nameWords))
+}
+
+// ScoredFile is a file with it's relevance score from a ranked search.
+type ScoredFile struct {
+	model.File `bson:",inline"`
+	Score      float64 `json:"score" bson:"score"`
+}
+
+// SortScored sorts files by their score with newer files first for equal scores, ids break any remaining ties.
+func SortScored(files []ScoredFile) {
+	sort.SliceStable(files, func(i, j int) bool {
+		if files[i].Score != files[j].Score {
+			return files[i].Score > files[j].Score
+		}
+
+		if files[i].Time != files[j].Time {
+			return files[i].Time > files[j].Time
+		}
+
+		return files[i].UniqueId < files[j].UniqueId
+	})
+}
+
+// RankFiles scores files against the words of a query and returns the files that matched any word, best matches first.
+func RankFiles(words []string, files []model.File) []ScoredFile {
+	scored := make([]ScoredFile, 0, len(files))
+
+	for _, f := range files {
+		if s := TextScore(words, f.FileName); s > 0 {
+			scored = append(scored, ScoredFile{File: f, Score: s})
+		}
+	}
+
+	SortScored(scored)
+
+	return scored
+}
+
+// MergeRanked returns a cursor over the ranked files followed by files from fallback not already included.
+// The first skip files are dropped and upto limit files are returned.
+func MergeRanked(ranked []ScoredFile, fallback []model.File, skip, limit int) Cursor {
+	var (
+		docs = make([]interface{}, 0, skip+limit)
+		seen = make(map[string]bool)
+	)
+
+	limit += skip
+
+	for _, f := range ranked {
+		if len(docs) >= limit {
+			break
+		}
+
+		seen[f.UniqueId] = true
+
+		docs = append(docs, f.File)
+	}
+
+	for _, f := range fallback {
+		if len(docs) >= limit {
+			break
+		}
+
+		if seen[f.UniqueId] {
+			continue
+		}
+
+		seen[f.UniqueId] = true
+
+		docs = append(docs, f)
+	}
+
+	if skip >= len(docs) {
+		return NewSliceCursor(nil)
+	}
+
+	return NewSliceCursor(docs[skip:])
+}
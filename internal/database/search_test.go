@@ -0,0 +1,71 @@
+package database_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/Jisin0/autofilterbot/internal/database"
+	"github.com/Jisin0/autofilterbot/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchWords(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal([]string{"avatar", "the", "way", "of", "water", "2022", "mkv"}, database.SearchWords("Avatar.The.Way.Of.Water.2022.The.mkv"))
+	assert.Empty(database.SearchWords(" ._- "))
+}
+
+func TestAnyWordPattern(t *testing.T) {
+	r := regexp.MustCompile(database.AnyWordPattern([]string{"avatar", "c++"}))
+
+	tests := []struct {
+		name     string
+		expected bool
+	}{
+		{name: "Avatar.2009.mkv", expected: true},
+		{name: "[C++] Tutorial.mp4", expected: true},
+		{name: "Avatars.mkv", expected: false},
+		{name: "Interstellar.mkv", expected: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, r.MatchString(tc.name))
+		})
+	}
+}
+
+func TestRankFiles(t *testing.T) {
+	assert := assert.New(t)
+
+	files := []model.File{
+		{UniqueId: "a", FileName: "Avatar 2009 720p.mkv", Time: 1},
+		{UniqueId: "b", FileName: "Avatar 2009 1080p Extended Edition.mkv", Time: 2},
+		{UniqueId: "c", FileName: "Avatar.The.Way.Of.Water.2022.mkv", Time: 3},
+		{UniqueId: "d", FileName: "Interstellar 2014.mkv", Time: 4},
+	}
+
+	var ids []string
+
+	for _, f := range database.RankFiles(database.SearchWords("avatar 2009"), files) {
+		ids = append(ids, f.UniqueId)
+	}
+
+	assert.Equal([]string{"a", "b", "c"}, ids) // shorter names rank higher for the same number of matched words
+}
+
+func TestSearchFilesOptsGetFilter(t *testing.T) {
+	assert := assert.New(t)
+
+	filter := &database.FileFilter{FileType: model.FileTypeVideo}
+
+	assert.Same(filter, database.SearchFilesOpts{Filter: filter}.GetFilter())
+	assert.Nil(database.SearchFilesOpts{}.GetFilter())
+
+	f := database.SearchFilesOpts{Filter: filter, Channels: []int64{-1001}}.GetFilter()
+	assert.Equal(&database.FileFilter{FileType: model.FileTypeVideo, ChatIds: []int64{-1001}}, f)
+	assert.Nil(filter.ChatIds, "the original filter should not be changed")
+
+	assert.Equal(&database.FileFilter{ChatIds: []int64{-1002}}, database.SearchFilesOpts{Channels: []int64{-1002}}.GetFilter())
+}
@@ -0,0 +1,80 @@
+package sqlite
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Jisin0/autofilterbot/internal/model"
+)
+
+// batchColumns selects all columns of the batches table aliased to the json tags of model.Batch.
+const batchColumns = `id AS _id, chat_id, "start", "end", created_by, created_at, expires_at, password, accesses`
+
+// batchFields are the fields of a batch that can be updated with UpdateBatch.
+var batchFields = map[string]bool{
+	"chat_id":    true,
+	"start":      true,
+	"end":        true,
+	"expires_at": true,
+	"password":   true,
+}
+
+func (c *Client) SaveBatch(b *model.Batch) error {
+	_, err := c.db.NamedExec(`INSERT INTO batches (id, chat_id, "start", "end", created_by, created_at, expires_at, password, accesses)
+	VALUES (:_id, :chat_id, :start, :end, :created_by, :created_at, :expires_at, :password, :accesses)`, b)
+
+	return err
+}
+
+func (c *Client) GetBatch(id string) (*model.Batch, error) {
+	var b model.Batch
+
+	err := c.db.Get(&b, `SELECT `+batchColumns+` FROM batches WHERE id = ?`, id)
+	if err != nil {
+		return nil, isNoRows(err)
+	}
+
+	return &b, nil
+}
+
+func (c *Client) GetBatches() ([]*model.Batch, error) {
+	batches := make([]*model.Batch, 0)
+
+	err := c.db.Select(&batches, `SELECT `+batchColumns+` FROM batches ORDER BY created_at DESC`)
+
+	return batches, err
+}
+
+func (c *Client) UpdateBatch(id string, vals map[string]interface{}) error {
+	if len(vals) == 0 {
+		return nil
+	}
+
+	var (
+		set  = make([]string, 0, len(vals))
+		args = make([]interface{}, 0, len(vals)+1)
+	)
+
+	for k, v := range vals {
+		if !batchFields[k] {
+			return fmt.Errorf("sqlite: unknown batch field %s", k)
+		}
+
+		set = append(set, fmt.Sprintf(`"%s" = ?`, k))
+		args = append(args, v)
+	}
+
+	_, err := c.db.Exec(`UPDATE batches SET `+strings.Join(set, ", ")+` WHERE id = ?`, append(args, id)...)
+
+	return err
+}
+
+func (c *Client) IncrementBatchAccesses(id string) error {
+	_, err := c.db.Exec(`UPDATE batches SET accesses = accesses + 1 WHERE id = ?`, id)
+	return err
+}
+
+func (c *Client) DeleteBatch(id string) error {
+	_, err := c.db.Exec(`DELETE FROM batches WHERE id = ?`, id)
+	return err
+}
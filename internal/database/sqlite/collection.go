@@ -0,0 +1,39 @@
+package sqlite
+
+import (
+	"github.com/Jisin0/autofilterbot/internal/model"
+)
+
+// collectionColumns selects all columns of the collections table aliased to the json tags of model.Collection.
+const collectionColumns = `id AS _id, name, files, created_by, created_at`
+
+func (c *Client) SaveCollection(coll *model.Collection) error {
+	_, err := c.db.NamedExec(`INSERT INTO collections (id, name, files, created_by, created_at) VALUES (:_id, :name, :files, :created_by, :created_at)
+	ON CONFLICT(id) DO UPDATE SET name = excluded.name, files = excluded.files, created_by = excluded.created_by, created_at = excluded.created_at`, coll)
+
+	return err
+}
+
+func (c *Client) GetCollection(id string) (*model.Collection, error) {
+	var coll model.Collection
+
+	err := c.db.Get(&coll, `SELECT `+collectionColumns+` FROM collections WHERE id = ?`, id)
+	if err != nil {
+		return nil, isNoRows(err)
+	}
+
+	return &coll, nil
+}
+
+func (c *Client) GetCollections() ([]*model.Collection, error) {
+	collections := make([]*model.Collection, 0)
+
+	err := c.db.Select(&collections, `SELECT `+collectionColumns+` FROM collections ORDER BY created_at DESC`)
+
+	return collections, err
+}
+
+func (c *Client) DeleteCollection(id string) error {
+	_, err := c.db.Exec(`DELETE FROM collections WHERE id = ?`, id)
+	return err
+}
@@ -0,0 +1,123 @@
+package sqlite
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strconv"
+
+	"github.com/Jisin0/autofilterbot/internal/config"
+)
+
+// Configs are stored as a single json document per bot so fields can be updated by their key like in mongodb.
+
+func (c *Client) GetConfig(botId int64) (*config.Config, error) {
+	r := &config.Config{}
+
+	var data string
+
+	err := c.db.Get(&data, `SELECT data FROM configs WHERE bot_id = ?`, botId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return r, nil
+		}
+
+		return r, err
+	}
+
+	if err := json.Unmarshal([]byte(data), r); err != nil {
+		return r, err
+	}
+
+	return r, nil
+}
+
+func (c *Client) UpdateConfig(botId int64, key string, value interface{}) error {
+	return c.updateConfigDocument(botId, func(doc map[string]json.RawMessage) error {
+		b, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+
+		doc[key] = b
+
+		return nil
+	})
+}
+
+func (c *Client) SaveConfig(botId int64, data *config.Config) error {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.db.Exec(`INSERT INTO configs (bot_id, data) VALUES (?, ?)`, botId, string(b))
+
+	return err
+}
+
+func (c *Client) ResetConfig(botId int64, key string) error {
+	return c.updateConfigDocument(botId, func(doc map[string]json.RawMessage) error {
+		delete(doc, key)
+		return nil
+	})
+}
+
+// updateConfigDocument loads the config document of the bot, applies the update and saves it in a single transaction.
+func (c *Client) updateConfigDocument(botId int64, update func(doc map[string]json.RawMessage) error) error {
+	return c.updateDocument(
+		`SELECT data FROM configs WHERE bot_id = ?`,
+		`INSERT INTO configs (bot_id, data) VALUES (?, ?) ON CONFLICT(bot_id) DO UPDATE SET data = excluded.data`,
+		botId,
+		func(doc map[string]json.RawMessage) error {
+			if err := update(doc); err != nil {
+				return err
+			}
+
+			doc["_id"] = json.RawMessage(strconv.FormatInt(botId, 10))
+
+			return nil
+		},
+	)
+}
+
+// updateDocument loads a json document using selectQuery, applies the update and saves it using upsertQuery in a single transaction.
+// Both queries take the id of the row as the first parameter and upsertQuery takes the document as the second.
+func (c *Client) updateDocument(selectQuery, upsertQuery string, id int64, update func(doc map[string]json.RawMessage) error) error {
+	tx, err := c.db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck // no-op after commit
+
+	var data string
+
+	err = tx.Get(&data, selectQuery, id)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+
+	doc := make(map[string]json.RawMessage)
+
+	if data != "" {
+		if err := json.Unmarshal([]byte(data), &doc); err != nil {
+			return err
+		}
+	}
+
+	if err := update(doc); err != nil {
+		return err
+	}
+
+	b, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(upsertQuery, id, string(b))
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,191 @@
+package sqlite
+
+import (
+	"strings"
+
+	"github.com/Jisin0/autofilterbot/internal/config"
+	"github.com/Jisin0/autofilterbot/internal/database"
+	"github.com/Jisin0/autofilterbot/internal/model"
+)
+
+// fileColumns selects all columns of the files table aliased to the json tags of model.File.
+const fileColumns = `unique_id AS _id, file_id, file_name, file_type, file_size, ext, time, chat_id, file_link, release, downloads`
+
+func (c *Client) SaveFile(f *model.File) error {
+	// Find a file with matching file_id or one that starts with the same file_name and is within a 100 byte range of file_size
+	var n int
+
+	err := c.db.Get(&n, `SELECT COUNT(*) FROM files WHERE file_id = ? OR (substr(file_name, 1, length(?)) = ? AND file_size BETWEEN ? AND ?)`,
+		f.FileId, f.FileName, f.FileName, f.FileSize-100, f.FileSize+100)
+	if err != nil {
+		return err
+	}
+
+	if n != 0 {
+		return database.FileAlreadyExistsError{FileName: f.FileName}
+	}
+
+	_, err = c.db.NamedExec(`INSERT INTO files (unique_id, file_id, file_name, file_type, file_size, ext, time, chat_id, file_link, release, downloads)
+	VALUES (:_id, :file_id, :file_name, :file_type, :file_size, :ext, :time, :chat_id, :file_link, :release, :downloads)`, f)
+	if isConstraintErr(err) {
+		return database.FileAlreadyExistsError{FileName: f.FileName}
+	}
+
+	return err
+}
+
+func (c *Client) SaveFiles(files ...*model.File) []error {
+	var errs []error
+
+	for _, f := range files {
+		if err := c.SaveFile(f); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errs
+}
+
+func (c *Client) GetFile(fileId string) (*model.File, error) {
+	var f model.File
+
+	err := c.db.Get(&f, `SELECT `+fileColumns+` FROM files WHERE unique_id = ?`, fileId)
+	if err != nil {
+		return nil, isNoRows(err)
+	}
+
+	return &f, nil
+}
+
+func (c *Client) DeleteFile(fileId string) error {
+	_, err := c.db.Exec(`DELETE FROM files WHERE unique_id = ?`, fileId)
+	return err
+}
+
+func (c *Client) IncrementDownloads(fileId string) error {
+	_, err := c.db.Exec(`UPDATE files SET downloads = downloads + 1 WHERE unique_id = ?`, fileId)
+	return err
+}
+
+func (c *Client) GetAllFiles() (database.Cursor, error) {
+	rows, err := c.db.Queryx(`SELECT ` + fileColumns + ` FROM files`)
+	if err != nil {
+		return nil, err
+	}
+
+	return &cursor{rows: rows}, nil
+}
+
+// rankedCandidates is the number of newest files matching any word that are scored in a ranked search.
+const rankedCandidates = 1000
+
+func (c *Client) SearchFiles(query string, opts ...database.SearchFilesOpts) (database.Cursor, error) {
+	var o database.SearchFilesOpts
+	if len(opts) != 0 {
+		o = opts[0]
+	}
+
+	if o.Mode == config.SearchModeText {
+		return c.rankedSearch(query, o)
+	}
+
+	where, args := filterClause(o.GetFilter())
+
+	rows, err := c.db.Queryx(`SELECT `+fileColumns+` FROM files WHERE file_name REGEXP ?`+where+` ORDER BY time DESC, unique_id LIMIT ? OFFSET ?`, append([]interface{}{database.SearchPattern(query)}, append(args, o.GetLimit(), o.Skip)...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &cursor{rows: rows}, nil
+}
+
+// rankedSearch scores files containing any word of the query like a text index would, topped up by partial word matches.
+func (c *Client) rankedSearch(query string, o database.SearchFilesOpts) (database.Cursor, error) {
+	words := database.SearchWords(query)
+	if len(words) == 0 {
+		return database.NewSliceCursor(nil), nil
+	}
+
+	var (
+		where, args = filterClause(o.GetFilter())
+		// files of both searches upto the end of the requested page are needed to merge them
+		limit = o.Skip + o.GetLimit()
+	)
+
+	var candidates []model.File
+
+	err := c.db.Select(&candidates, `SELECT `+fileColumns+` FROM files WHERE file_name REGEXP ?`+where+` ORDER BY time DESC, unique_id LIMIT ?`, append([]interface{}{database.AnyWordPattern(words)}, append(args, max(rankedCandidates, limit))...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	ranked := database.RankFiles(words, candidates)
+
+	var fallback []model.File
+
+	if len(ranked) < limit {
+		err := c.db.Select(&fallback, `SELECT `+fileColumns+` FROM files WHERE file_name REGEXP ?`+where+` ORDER BY time DESC, unique_id LIMIT ?`, append([]interface{}{database.PartialSearchPattern(query)}, append(args, limit)...)...)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return database.MergeRanked(ranked, fallback, o.Skip, o.GetLimit()), nil
+}
+
+// filterClause translates the filter into conditions that can be appended to a WHERE clause along with their arguments.
+func filterClause(f *database.FileFilter) (string, []interface{}) {
+	if f.IsEmpty() {
+		return "", nil
+	}
+
+	var (
+		conds []string
+		args  []interface{}
+	)
+
+	if f.FileType != "" {
+		conds = append(conds, `file_type = ?`)
+		args = append(args, f.FileType)
+	}
+
+	if f.MinSize != 0 {
+		conds = append(conds, `file_size >= ?`)
+		args = append(args, f.MinSize)
+	}
+
+	if f.MaxSize != 0 {
+		conds = append(conds, `file_size <= ?`)
+		args = append(args, f.MaxSize)
+	}
+
+	if len(f.Extensions) != 0 {
+		conds = append(conds, `(ext IN (?`+strings.Repeat(`, ?`, len(f.Extensions)-1)+`) OR file_name REGEXP ?)`)
+		for _, e := range f.Extensions {
+			args = append(args, e)
+		}
+
+		args = append(args, database.ExtensionPattern(f.Extensions))
+	}
+
+	if len(f.ChatIds) != 0 {
+		conds = append(conds, `chat_id IN (?`+strings.Repeat(`, ?`, len(f.ChatIds)-1)+`)`)
+		for _, id := range f.ChatIds {
+			args = append(args, id)
+		}
+	}
+
+	include, exclude := f.NamePatterns()
+
+	for _, p := range include {
+		conds = append(conds, `file_name REGEXP ?`)
+		args = append(args, p)
+	}
+
+	for _, p := range exclude {
+		conds = append(conds, `NOT file_name REGEXP ?`)
+		args = append(args, p)
+	}
+
+	return " AND " + strings.Join(conds, " AND "), args
+}
@@ -0,0 +1,26 @@
+package sqlite
+
+import "github.com/Jisin0/autofilterbot/internal/model"
+
+func (c *Client) SaveUserJoinRequest(userId, chatId int64) error {
+	_, err := c.db.Exec(`INSERT OR IGNORE INTO join_requests (user_id, chat_id) VALUES (?, ?)`, userId, chatId)
+	return err
+}
+
+func (c *Client) DeleteUserJoinRequest(userId, chatId int64) error {
+	_, err := c.db.Exec(`DELETE FROM join_requests WHERE user_id = ? AND chat_id = ?`, userId, chatId)
+	return err
+}
+
+// GetUserJoinRequests fetches a user's join requests from the database by id.
+// A user without any join requests is returned with an empty list.
+func (c *Client) GetUserJoinRequests(userId int64) (*model.User, error) {
+	u := model.User{UserId: userId}
+
+	err := c.db.Select(&u.JoinRequests, `SELECT chat_id FROM join_requests WHERE user_id = ? ORDER BY rowid`, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &u, nil
+}
@@ -0,0 +1,98 @@
+package sqlite
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	"github.com/Jisin0/autofilterbot/internal/config"
+	"github.com/Jisin0/autofilterbot/internal/model"
+)
+
+func (c *Client) SaveGroup(g *model.Group) error {
+	_, err := c.db.Exec(
+		`INSERT INTO groups (id, title, members, added_by, added_at, status) VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET title = excluded.title, members = excluded.members, added_by = excluded.added_by, added_at = excluded.added_at, status = excluded.status`,
+		g.ID, g.Title, g.MemberCount, g.AddedBy, g.AddedAt, g.Status,
+	)
+
+	return err
+}
+
+func (c *Client) GetGroup(groupId int64) (*model.Group, error) {
+	var g model.Group
+
+	err := c.db.Get(&g, `SELECT id AS _id, title, members, added_by, added_at, status FROM groups WHERE id = ?`, groupId)
+	if err != nil {
+		return nil, isNoRows(err)
+	}
+
+	return &g, nil
+}
+
+func (c *Client) GetGroups() ([]*model.Group, error) {
+	groups := make([]*model.Group, 0)
+
+	// groups that only have settings were never saved
+	err := c.db.Select(&groups, `SELECT id AS _id, title, members, added_by, added_at, status FROM groups WHERE status != '' ORDER BY added_at DESC`)
+
+	return groups, err
+}
+
+func (c *Client) UpdateGroupStatus(groupId int64, status string) error {
+	_, err := c.db.Exec(`UPDATE groups SET status = ? WHERE id = ?`, status, groupId)
+	return err
+}
+
+// Group settings are stored as a json document in the config column like bot configs.
+
+func (c *Client) GetGroupConfig(groupId int64) (*config.GroupConfig, error) {
+	r := &config.GroupConfig{}
+
+	var data string
+
+	err := c.db.Get(&data, `SELECT config FROM groups WHERE id = ?`, groupId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return r, nil
+		}
+
+		return r, err
+	}
+
+	if err := json.Unmarshal([]byte(data), r); err != nil {
+		return r, err
+	}
+
+	return r, nil
+}
+
+func (c *Client) UpdateGroupConfig(groupId int64, key string, value interface{}) error {
+	return c.updateGroupConfigDocument(groupId, func(doc map[string]json.RawMessage) error {
+		b, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+
+		doc[key] = b
+
+		return nil
+	})
+}
+
+func (c *Client) ResetGroupConfig(groupId int64, key string) error {
+	return c.updateGroupConfigDocument(groupId, func(doc map[string]json.RawMessage) error {
+		delete(doc, key)
+		return nil
+	})
+}
+
+// updateGroupConfigDocument loads the settings of the group, applies the update and saves it in a single transaction.
+func (c *Client) updateGroupConfigDocument(groupId int64, update func(doc map[string]json.RawMessage) error) error {
+	return c.updateDocument(
+		`SELECT config FROM groups WHERE id = ?`,
+		`INSERT INTO groups (id, config) VALUES (?, ?) ON CONFLICT(id) DO UPDATE SET config = excluded.config`,
+		groupId,
+		update,
+	)
+}
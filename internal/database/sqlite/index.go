@@ -0,0 +1,91 @@
+package sqlite
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Jisin0/autofilterbot/internal/model"
+)
+
+// operationColumns selects all columns of the operations table aliased to the json tags of model.Index.
+const operationColumns = `id AS _id, "start", "end", "current", channel, saved, failed, is_paused, pmessage_chat`
+
+// operationFields are the fields of an operation that can be updated with UpdateIndexOperation.
+var operationFields = map[string]bool{
+	"start":         true,
+	"end":           true,
+	"current":       true,
+	"channel":       true,
+	"saved":         true,
+	"failed":        true,
+	"is_paused":     true,
+	"pmessage_chat": true,
+}
+
+// NewIndexOperation inserts a new index operation into the database.
+func (c *Client) NewIndexOperation(i *model.Index) error {
+	_, err := c.db.NamedExec(`INSERT INTO operations (id, "start", "end", "current", channel, saved, failed, is_paused, pmessage_chat)
+	VALUES (:_id, :start, :end, :current, :channel, :saved, :failed, :is_paused, :pmessage_chat)`, i)
+
+	return err
+}
+
+// UpdateIndexOperation updates an index operation.
+// Returns a bool indication wether a match was found and errors.
+func (c *Client) UpdateIndexOperation(pid string, vals map[string]interface{}) (bool, error) {
+	if len(vals) == 0 {
+		return false, nil
+	}
+
+	var (
+		set  = make([]string, 0, len(vals))
+		args = make([]interface{}, 0, len(vals)+1)
+	)
+
+	for k, v := range vals {
+		if !operationFields[k] {
+			return false, fmt.Errorf("sqlite: unknown operation field %s", k)
+		}
+
+		set = append(set, fmt.Sprintf(`"%s" = ?`, k))
+		args = append(args, v)
+	}
+
+	args = append(args, pid)
+
+	r, err := c.db.Exec(`UPDATE operations SET `+strings.Join(set, ", ")+` WHERE id = ?`, args...)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := r.RowsAffected()
+
+	return n != 0, err
+}
+
+// GetIndexOperation fetches an index operation by it's id.
+func (c *Client) GetIndexOperation(pid string) (*model.Index, error) {
+	var i model.Index
+
+	err := c.db.Get(&i, `SELECT `+operationColumns+` FROM operations WHERE id = ?`, pid)
+	if err != nil {
+		return nil, isNoRows(err)
+	}
+
+	return &i, nil
+}
+
+// GetAllIndexOperations fetches all active index operations.
+func (c *Client) GetActiveIndexOperations() ([]*model.Index, error) {
+	ops := make([]*model.Index, 0)
+
+	err := c.db.Select(&ops, `SELECT `+operationColumns+` FROM operations WHERE is_paused = 0`)
+
+	return ops, err
+}
+
+// DeleteOperation deletes an active operation by id.
+func (c *Client) DeleteOperation(pid string) error {
+	_, err := c.db.Exec(`DELETE FROM operations WHERE id = ?`, pid)
+	return err
+}
@@ -0,0 +1,253 @@
+// Package sqlite implements database.Database using an embedded sqlite database.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/Jisin0/autofilterbot/internal/database"
+	"github.com/Jisin0/autofilterbot/internal/model"
+	"github.com/jmoiron/sqlx"
+	"github.com/jmoiron/sqlx/reflectx"
+	"github.com/mattn/go-sqlite3"
+	"go.uber.org/zap"
+)
+
+// Ensure *Client implements database.Database
+var _ database.Database = (*Client)(nil)
+
+const (
+	// URLScheme is the scheme of a DATABASE_URL that should be opened using sqlite.
+	URLScheme = "sqlite://"
+
+	// driverName is the sqlite3 driver registered with a regexp function.
+	driverName = "sqlite3_autofilter"
+)
+
+func init() {
+	sql.Register(driverName, &sqlite3.SQLiteDriver{
+		ConnectHook: func(conn *sqlite3.SQLiteConn) error {
+			return conn.RegisterFunc("regexp", matchRegex, true)
+		},
+	})
+}
+
+const schema = `
+CREATE TABLE IF NOT EXISTS users (
+	id INTEGER PRIMARY KEY
+);
+
+CREATE TABLE IF NOT EXISTS join_requests (
+	user_id INTEGER NOT NULL,
+	chat_id INTEGER NOT NULL,
+	PRIMARY KEY (user_id, chat_id)
+);
+
+CREATE TABLE IF NOT EXISTS files (
+	unique_id TEXT PRIMARY KEY,
+	file_id TEXT NOT NULL UNIQUE,
+	file_name TEXT NOT NULL,
+	file_type TEXT NOT NULL,
+	file_size INTEGER NOT NULL,
+	ext TEXT NOT NULL DEFAULT '',
+	time INTEGER NOT NULL DEFAULT 0,
+	chat_id INTEGER NOT NULL DEFAULT 0,
+	file_link TEXT NOT NULL DEFAULT '',
+	release TEXT,
+	downloads INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS files_time ON files (time DESC);
+CREATE INDEX IF NOT EXISTS files_name_size ON files (file_name, file_size);
+
+CREATE TABLE IF NOT EXISTS configs (
+	bot_id INTEGER PRIMARY KEY,
+	data TEXT NOT NULL DEFAULT '{}'
+);
+
+CREATE TABLE IF NOT EXISTS groups (
+	id INTEGER PRIMARY KEY,
+	config TEXT NOT NULL DEFAULT '{}',
+	title TEXT NOT NULL DEFAULT '',
+	members INTEGER NOT NULL DEFAULT 0,
+	added_by INTEGER NOT NULL DEFAULT 0,
+	added_at INTEGER NOT NULL DEFAULT 0,
+	status TEXT NOT NULL DEFAULT ''
+);
+
+CREATE TABLE IF NOT EXISTS batches (
+	id TEXT PRIMARY KEY,
+	chat_id INTEGER NOT NULL,
+	"start" INTEGER NOT NULL,
+	"end" INTEGER NOT NULL,
+	created_by INTEGER NOT NULL DEFAULT 0,
+	created_at INTEGER NOT NULL DEFAULT 0,
+	expires_at INTEGER NOT NULL DEFAULT 0,
+	password TEXT NOT NULL DEFAULT '',
+	accesses INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE TABLE IF NOT EXISTS collections (
+	id TEXT PRIMARY KEY,
+	name TEXT NOT NULL,
+	files TEXT NOT NULL DEFAULT '[]',
+	created_by INTEGER NOT NULL DEFAULT 0,
+	created_at INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE TABLE IF NOT EXISTS operations (
+	id TEXT PRIMARY KEY,
+	"start" INTEGER NOT NULL,
+	"end" INTEGER NOT NULL,
+	"current" INTEGER NOT NULL,
+	channel INTEGER NOT NULL,
+	saved INTEGER NOT NULL DEFAULT 0,
+	failed INTEGER NOT NULL DEFAULT 0,
+	is_paused INTEGER NOT NULL DEFAULT 0,
+	pmessage_chat INTEGER NOT NULL DEFAULT 0
+);
+`
+
+// migrations are run after the schema is created to update tables created by older versions.
+// Errors caused by a migration that was already applied are ignored.
+var migrations = []string{
+	`ALTER TABLE files ADD COLUMN ext TEXT NOT NULL DEFAULT ''`,
+	`ALTER TABLE files ADD COLUMN release TEXT`,
+	`ALTER TABLE files ADD COLUMN downloads INTEGER NOT NULL DEFAULT 0`,
+	`ALTER TABLE groups ADD COLUMN config TEXT NOT NULL DEFAULT '{}'`,
+	`ALTER TABLE groups ADD COLUMN title TEXT NOT NULL DEFAULT ''`,
+	`ALTER TABLE groups ADD COLUMN members INTEGER NOT NULL DEFAULT 0`,
+	`ALTER TABLE groups ADD COLUMN added_by INTEGER NOT NULL DEFAULT 0`,
+	`ALTER TABLE groups ADD COLUMN added_at INTEGER NOT NULL DEFAULT 0`,
+	`ALTER TABLE groups ADD COLUMN status TEXT NOT NULL DEFAULT ''`,
+}
+
+// Client implements database.Database using sqlite.
+type Client struct {
+	db  *sqlx.DB
+	log *zap.Logger
+}
+
+// NewClient opens or creates the sqlite database at path and creates any missing tables.
+//
+// - path: path to the database file, a DATABASE_URL starting with sqlite:// is also accepted.
+func NewClient(path string, log *zap.Logger) (*Client, error) {
+	path = strings.TrimPrefix(path, URLScheme)
+
+	db, err := sqlx.Open(driverName, "file:"+path+"?_journal_mode=WAL&_busy_timeout=5000")
+	if err != nil {
+		return nil, err
+	}
+
+	// columns are mapped to the json tags of models so they can be scanned directly
+	db.Mapper = reflectx.NewMapperFunc("json", strings.ToLower)
+
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	for _, m := range migrations {
+		if _, err := db.Exec(m); err != nil && !strings.Contains(err.Error(), "duplicate column name") {
+			db.Close()
+			return nil, err
+		}
+	}
+
+	return &Client{db: db, log: log}, nil
+}
+
+func (c *Client) Shutdown() error {
+	return c.db.Close()
+}
+
+func (c *Client) Stats() (*model.Stats, error) {
+	var s model.Stats
+
+	if err := c.db.Get(&s.Users, `SELECT COUNT(*) FROM users`); err != nil {
+		return nil, err
+	}
+
+	if err := c.db.Get(&s.Groups, `SELECT COUNT(*) FROM groups`); err != nil {
+		return nil, err
+	}
+
+	var files int64
+
+	if err := c.db.Get(&files, `SELECT COUNT(*) FROM files`); err != nil {
+		return nil, err
+	}
+
+	s.Files = files
+
+	if err := c.db.Get(&s.Downloads, `SELECT COALESCE(SUM(downloads), 0) FROM files`); err != nil {
+		return nil, err
+	}
+
+	return &s, nil
+}
+
+func (c *Client) GetName() string {
+	return "SQLite"
+}
+
+// regexCache stores compiled patterns used by the regexp sql function.
+var regexCache sync.Map
+
+// matchRegex implements the sqlite REGEXP operator using go's regexp package.
+func matchRegex(pattern, s string) (bool, error) {
+	if r, ok := regexCache.Load(pattern); ok {
+		return r.(*regexp.Regexp).MatchString(s), nil
+	}
+
+	r, err := regexp.Compile(pattern)
+	if err != nil {
+		return false, err
+	}
+
+	regexCache.Store(pattern, r)
+
+	return r.MatchString(s), nil
+}
+
+// isNoRows converts sql.ErrNoRows into database.ErrNoDocuments.
+func isNoRows(err error) error {
+	if err == sql.ErrNoRows {
+		return database.ErrNoDocuments
+	}
+
+	return err
+}
+
+// isConstraintErr reports whether the error is a unique or primary key constraint violation.
+func isConstraintErr(err error) bool {
+	e, ok := err.(sqlite3.Error)
+	return ok && e.Code == sqlite3.ErrConstraint
+}
+
+// cursor wraps sqlx.Rows to implement database.Cursor.
+type cursor struct {
+	rows *sqlx.Rows
+}
+
+// Ensure *cursor implements database.Cursor.
+var _ database.Cursor = (*cursor)(nil)
+
+func (c *cursor) Next(_ context.Context) bool {
+	return c.rows.Next()
+}
+
+func (c *cursor) Decode(v interface{}) error {
+	return c.rows.StructScan(v)
+}
+
+func (c *cursor) Err() error {
+	return c.rows.Err()
+}
+
+func (c *cursor) Close(_ context.Context) error {
+	return c.rows.Close()
+}
@@ -0,0 +1,42 @@
+package sqlite_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/Jisin0/autofilterbot/internal/database"
+	"github.com/Jisin0/autofilterbot/internal/database/databasetest"
+	"github.com/Jisin0/autofilterbot/internal/database/sqlite"
+	"github.com/Jisin0/autofilterbot/internal/model"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func newClient(t *testing.T) *sqlite.Client {
+	t.Helper()
+
+	c, err := sqlite.NewClient(sqlite.URLScheme+filepath.Join(t.TempDir(), "test.db"), zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { c.Shutdown() })
+
+	return c
+}
+
+func TestConformance(t *testing.T) {
+	databasetest.Run(t, func(t *testing.T) database.Database {
+		return newClient(t)
+	})
+}
+
+func TestUpdateIndexOperationUnknownField(t *testing.T) {
+	assert := assert.New(t)
+	c := newClient(t)
+
+	assert.NoError(c.NewIndexOperation(&model.Index{ID: "abc"}))
+
+	_, err := c.UpdateIndexOperation("abc", map[string]interface{}{`current" = 1; DROP TABLE files; --`: 1})
+	assert.Error(err)
+}
@@ -0,0 +1,40 @@
+package sqlite
+
+import (
+	"github.com/Jisin0/autofilterbot/internal/database"
+	"github.com/Jisin0/autofilterbot/internal/model"
+)
+
+// SaveUser inserts the user id into the users table if it does not exist.
+func (c *Client) SaveUser(userId int64) error {
+	_, err := c.db.Exec(`INSERT OR IGNORE INTO users (id) VALUES (?)`, userId)
+	return err
+}
+
+// GetUser fetches a user from the database by id.
+func (c *Client) GetUser(userId int64) (*model.User, error) {
+	var u model.User
+
+	err := c.db.Get(&u, `SELECT id AS _id FROM users WHERE id = ?`, userId)
+	if err != nil {
+		return nil, isNoRows(err)
+	}
+
+	return &u, nil
+}
+
+// DeleteUser deletes a user by their id.
+func (c *Client) DeleteUser(userId int64) error {
+	_, err := c.db.Exec(`DELETE FROM users WHERE id = ?`, userId)
+	return err
+}
+
+// GetAllUsers return a cursor to loop over all users.
+func (c *Client) GetAllUsers() (database.Cursor, error) {
+	rows, err := c.db.Queryx(`SELECT id AS _id FROM users`)
+	if err != nil {
+		return nil, err
+	}
+
+	return &cursor{rows: rows}, nil
+}
@@ -5,10 +5,10 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/Jisin0/autofilterbot/internal/autofilter"
 	"github.com/Jisin0/autofilterbot/internal/button"
 	"github.com/Jisin0/autofilterbot/internal/config"
 	"github.com/Jisin0/autofilterbot/internal/database"
-	"github.com/Jisin0/autofilterbot/internal/database/mongo"
 	"github.com/Jisin0/autofilterbot/internal/format"
 	"github.com/Jisin0/autofilterbot/internal/model"
 	"github.com/PaulSonOfLars/gotgbot/v2"
@@ -79,7 +79,7 @@ func HasJoinRequest(db database.Database, chatId, userId int64) (bool, error) {
 // - userId: id of user to check.
 //
 // Returns slice of channels user is not a member. Error returned will be any API call or DB query failure.
-func GetNotMemberOrRequest(bot *gotgbot.Bot, db *mongo.Client, f []model.Channel, userId int64) ([]model.Channel, error) {
+func GetNotMemberOrRequest(bot *gotgbot.Bot, db database.Database, f []model.Channel, userId int64) ([]model.Channel, error) {
 	var (
 		user      *model.User
 		allErrors []error
@@ -98,6 +98,10 @@ func GetNotMemberOrRequest(bot *gotgbot.Bot, db *mongo.Client, f []model.Channel
 
 		if user == nil {
 			user, err = db.GetUserJoinRequests(userId)
+			if database.IsNoDocumentsError(err) {
+				user, err = &model.User{UserId: userId}, nil
+			}
+
 			if err != nil {
 				allErrors = append(allErrors, err)
 				continue // or break to prevent further db queries?
@@ -124,7 +128,7 @@ func GetNotMemberOrRequest(bot *gotgbot.Bot, db *mongo.Client, f []model.Channel
 }
 
 type appPreview interface {
-	GetDB() *mongo.Client
+	GetDB() database.Database
 	GetConfig() *config.Config
 	GetLog() *zap.Logger
 	BasicMessageValues(ctx *ext.Context, extraValues ...map[string]any) map[string]string
@@ -137,6 +141,12 @@ type appPreview interface {
 //
 // NOTE: true will be returned incase of an error.
 func CheckFsub(app appPreview, bot *gotgbot.Bot, ctx *ext.Context) (bool, error) {
+	return CheckChannels(app, bot, ctx, app.GetConfig().GetFsubChannels())
+}
+
+// CheckChannels is like CheckFsub but checks the given channels instead of the channels from the bot config,
+// like the channels of the group from which files were requested.
+func CheckChannels(app appPreview, bot *gotgbot.Bot, ctx *ext.Context, channels []model.Channel) (bool, error) {
 	var (
 		userID int64
 		chatID int64
@@ -154,7 +164,7 @@ func CheckFsub(app appPreview, bot *gotgbot.Bot, ctx *ext.Context) (bool, error)
 		chatID = ctx.InlineQuery.From.Id
 	}
 
-	notJoined, err := GetNotMemberOrRequest(bot, app.GetDB(), app.GetConfig().GetFsubChannels(), userID)
+	notJoined, err := GetNotMemberOrRequest(bot, app.GetDB(), channels, userID)
 	if err != nil {
 		return true, pkgerrors.Wrap(err, "fsub: ")
 	}
@@ -186,7 +196,13 @@ func CheckFsub(app appPreview, bot *gotgbot.Bot, ctx *ext.Context) (bool, error)
 
 	switch {
 	case ctx.Message != nil:
-		retryButton.Url = fmt.Sprintf("https://t.me/%s?start=%s", bot.Username, ctx.Args()[1])
+		// commands are retried with their start data, other messages are search queries
+		data := autofilter.SearchStartData(ctx.Message.Text)
+		if args := ctx.Args(); strings.HasPrefix(ctx.Message.Text, "/") && len(args) > 1 {
+			data = args[1]
+		}
+
+		retryButton.Url = fmt.Sprintf("https://t.me/%s?start=%s", bot.Username, data)
 	case ctx.CallbackQuery != nil:
 		retryButton.CallbackData = ctx.CallbackQuery.Data
 	case ctx.InlineQuery != nil:
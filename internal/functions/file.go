@@ -4,6 +4,7 @@ import (
 	"errors"
 
 	"github.com/Jisin0/autofilterbot/internal/model"
+	"github.com/Jisin0/autofilterbot/internal/release"
 	"github.com/PaulSonOfLars/gotgbot/v2"
 )
 
@@ -43,6 +44,8 @@ func FileFromMessage(m *gotgbot.Message) *model.File {
 		return nil
 	}
 
+	ext := FileExtension(fileName)
+	meta := release.Parse(fileName) // must run before symbols that separate tags are removed
 	fileName = RemoveSymbols(RemoveExtension(fileName))
 
 	return &model.File{
@@ -51,8 +54,10 @@ func FileFromMessage(m *gotgbot.Message) *model.File {
 		FileName:    fileName,
 		FileType:    fileType,
 		FileSize:    fileSize,
+		Extension:   ext,
 		Time:        m.Date,
 		ChatId:      m.Chat.Id,
 		MessageLink: m.GetLink(),
+		Release:     meta,
 	}
 }
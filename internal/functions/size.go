@@ -1,11 +1,16 @@
 package functions
 
-import "fmt"
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
 
 const (
 	kiloByte float64 = 1 << 10 // kilobyte in bytes
 	megaByte float64 = 1 << 20 // megabyte in bytes
 	gigaByte float64 = 1 << 30 // gigabyte in bytes
+	teraByte float64 = 1 << 40 // terabyte in bytes
 )
 
 // FileSizeToString converts file size in bytes to a user friendly string.
@@ -23,3 +28,33 @@ func FileSizeToString(n int64) string {
 		return fmt.Sprintf("%.0f B", num)
 	}
 }
+
+// ParseFileSize parses a human readable size like 700MB or 1.5gb into bytes. A number without a unit is read as bytes.
+func ParseFileSize(s string) (int64, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+
+	multiplier := 1.0
+
+	for _, u := range []struct {
+		suffix string
+		size   float64
+	}{{"TB", teraByte}, {"GB", gigaByte}, {"MB", megaByte}, {"KB", kiloByte}, {"T", teraByte}, {"G", gigaByte}, {"M", megaByte}, {"K", kiloByte}, {"B", 1}} {
+		if strings.HasSuffix(s, u.suffix) {
+			s = strings.TrimSpace(strings.TrimSuffix(s, u.suffix))
+			multiplier = u.size
+
+			break
+		}
+	}
+
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid file size: %w", err)
+	}
+
+	if n < 0 {
+		return 0, fmt.Errorf("invalid file size: %s is negative", s)
+	}
+
+	return int64(n * multiplier), nil
+}
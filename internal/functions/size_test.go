@@ -36,3 +36,33 @@ func TestFileSizeToString(t *testing.T) {
 		})
 	}
 }
+
+func TestParseFileSize(t *testing.T) {
+	table := []struct {
+		input          string
+		expectedOutput int64
+		expectError    bool
+	}{
+		{input: "512", expectedOutput: 512},
+		{input: "700MB", expectedOutput: 700 << 20},
+		{input: "1.5gb", expectedOutput: 3 << 29},
+		{input: "2 GB", expectedOutput: 2 << 30},
+		{input: "10k", expectedOutput: 10 << 10},
+		{input: "1TB", expectedOutput: 1 << 40},
+		{input: "big", expectError: true},
+		{input: "-1GB", expectError: true},
+	}
+
+	for _, item := range table {
+		t.Run(item.input, func(t *testing.T) {
+			n, err := functions.ParseFileSize(item.input)
+			if item.expectError {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, item.expectedOutput, n)
+		})
+	}
+}
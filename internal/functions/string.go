@@ -29,6 +29,16 @@ func RemoveExtension(input string) string {
 	return input
 }
 
+// FileExtension returns the lower case extension of a file name without the dot, using the same rules as RemoveExtension.
+func FileExtension(input string) string {
+	index := strings.LastIndex(input, ".")
+	if index == -1 || (len(input)-index) > 4 || index == len(input)-1 {
+		return ""
+	}
+
+	return strings.ToLower(input[index+1:])
+}
+
 const (
 	charset    = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
 	lenCharSet = int64(len(charset))
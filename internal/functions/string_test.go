@@ -62,3 +62,35 @@ func TestRemoveExtension(t *testing.T) {
 		})
 	}
 }
+
+func TestFileExtension(t *testing.T) {
+	assert := assert.New(t)
+
+	table := []struct {
+		input          string
+		expectedOutput string
+	}{
+		{
+			input:          "Just a file name.MKV",
+			expectedOutput: "mkv",
+		},
+		{
+			input:          "No extension",
+			expectedOutput: "",
+		},
+		{
+			input:          "Version 1.0 Release Notes",
+			expectedOutput: "",
+		},
+		{
+			input:          "trailing dot.",
+			expectedOutput: "",
+		},
+	}
+
+	for _, item := range table {
+		t.Run(item.input, func(t *testing.T) {
+			assert.Equal(item.expectedOutput, functions.FileExtension(item.input))
+		})
+	}
+}
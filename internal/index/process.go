@@ -7,6 +7,7 @@ import (
 	"github.com/Jisin0/autofilterbot/internal/database"
 	"github.com/Jisin0/autofilterbot/internal/functions"
 	"github.com/Jisin0/autofilterbot/internal/model"
+	"github.com/Jisin0/autofilterbot/internal/release"
 	"github.com/Jisin0/autofilterbot/pkg/fileid"
 	"github.com/amarnathcjd/gogram/telegram"
 	"go.uber.org/zap"
@@ -100,13 +101,24 @@ func (o *Operation) MessageProcessor(ctx context.Context, c chan []telegram.Mess
 					continue
 				}
 
+				// same as the file_unique_id of bot api so files posted in channels are detected as duplicates
+				uniqueID, err := fileid.EncodeUniqueID(f)
+				if err != nil {
+					o.log.Warn("encode unique id failed", zap.String("pid", o.ID), zap.Int32("msg_id", msg.ID), zap.Any("file", f))
+					o.Failed++
+					continue
+				}
+
 				file := model.File{
-					UniqueId: functions.RandString(15),
-					FileId:   fileID,
-					FileName: fileName,
-					FileType: fileType,
-					FileSize: int64(doc.Size),
-					Time:     int64(msg.Date),
+					UniqueId:  uniqueID,
+					FileId:    fileID,
+					FileName:  fileName,
+					FileType:  fileType,
+					FileSize:  int64(doc.Size),
+					Time:      int64(msg.Date),
+					ChatId:    o.ChannelID,
+					Extension: functions.FileExtension(fileName),
+					Release:   release.Parse(fileName),
 				}
 
 				err = o.db.SaveFile(&file)
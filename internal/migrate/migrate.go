@@ -0,0 +1,279 @@
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Jisin0/autofilterbot/internal/database"
+	"github.com/Jisin0/autofilterbot/internal/model"
+	"github.com/PaulSonOfLars/gotgbot/v2"
+	"go.uber.org/zap"
+)
+
+const (
+	batchSize = 100
+
+	progressUpdateSeconds = 10 // number of seconds after which progress msg should be updated
+)
+
+// Database is a database that can store migrations and move files.
+type Database interface {
+	database.Database
+	database.Migrator
+}
+
+// Manager allows for managing active migrations conveniently.
+// Must be initialised using NewManager at app startup.
+type Manager struct {
+	mu         sync.Mutex
+	operations map[string]*Operation
+}
+
+// NewManager intialises a new migration manager.
+func NewManager() *Manager {
+	return &Manager{
+		operations: make(map[string]*Operation),
+	}
+}
+
+// CancelOperation stops the active operation and deletes it from active operations map.
+// NOTE: does not delete from database or set status to paused.
+func (m *Manager) CancelOperation(id string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	o, ok := m.operations[id]
+	if !ok {
+		return false
+	}
+
+	o.cancelFunc()
+	delete(m.operations, id)
+
+	return true
+}
+
+// RunOperation starts the migration in the background.
+func (m *Manager) RunOperation(ctx context.Context, o *Operation) {
+	m.mu.Lock()
+	m.operations[o.ID] = o
+	m.mu.Unlock()
+
+	go func() {
+		o.run(ctx)
+
+		m.mu.Lock()
+		if m.operations[o.ID] == o {
+			delete(m.operations, o.ID)
+		}
+		m.mu.Unlock()
+	}()
+}
+
+// Operation runs a single migration.
+type Operation struct {
+	mu sync.Mutex
+
+	*model.Migration
+
+	db  Database
+	log *zap.Logger
+	bot *gotgbot.Bot
+
+	cancelFunc context.CancelFunc
+}
+
+// NewOperation creates a new migration operation and the context to run it with.
+func (m *Manager) NewOperation(ctx context.Context, mig *model.Migration, db Database, log *zap.Logger, b *gotgbot.Bot) (context.Context, *Operation) {
+	ctx2, cancel := context.WithCancel(ctx)
+
+	return ctx2, &Operation{
+		Migration:  mig,
+		db:         db,
+		log:        log,
+		bot:        b,
+		cancelFunc: cancel,
+	}
+}
+
+// run moves files step by step until all steps are completed or ctx is cancelled.
+func (o *Operation) run(ctx context.Context) {
+	progressM, err := o.bot.SendMessage(o.ProgressMessageChatID, fmt.Sprintf("Starting migration <code>%s</code> ...", o.ID), &gotgbot.SendMessageOpts{ParseMode: gotgbot.ParseModeHTML})
+	if err != nil {
+		o.log.Error("migrate: failed to send progress message", zap.Error(err), zap.String("pid", o.ID), zap.Int64("chat_id", o.ProgressMessageChatID))
+		return
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+
+	// updates progress msg and syncs db in the background
+	go func() {
+		ticker := time.NewTicker(progressUpdateSeconds * time.Second)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				o.pushToDB()
+				o.editProgress(progressM, "<b>Migration in Progress ⚡️</b>", o.PauseButton(), o.CancelButton())
+			}
+		}
+	}()
+
+	err = o.moveFiles(ctx)
+
+	switch {
+	case ctx.Err() != nil:
+		// paused either by the user or application quitting
+		o.pushToDB()
+		o.editProgress(progressM, "<b>Migration Paused ▶️</b>", o.ResumeButton(), o.CancelButton())
+	case err != nil:
+		o.log.Error("migrate: operation stopped", zap.Error(err), zap.String("pid", o.ID))
+
+		o.pushToDB()
+
+		if _, err := o.db.UpdateMigration(o.ID, map[string]interface{}{"is_paused": true}); err != nil {
+			o.log.Warn("migrate: failed to pause operation", zap.Error(err), zap.String("pid", o.ID))
+		}
+
+		o.editProgress(progressM, fmt.Sprintf("🛑 <b>Migration Stopped:</b> <code>%s</code>", err.Error()), o.ResumeButton(), o.CancelButton())
+	default:
+		o.editProgress(progressM, "<b>Migration Completed 🎉</b>")
+
+		if err := o.db.DeleteOperation(o.ID); err != nil {
+			o.log.Warn("migrate: delete operation failed", zap.Error(err), zap.String("pid", o.ID))
+		}
+	}
+}
+
+// moveFiles runs the remaining steps, it returns nil when all steps are completed.
+func (o *Operation) moveFiles(ctx context.Context) error {
+	for o.Step < len(o.Steps) {
+		step := o.Steps[o.Step]
+
+		for step.Count == 0 || o.StepMoved < step.Count {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			limit := batchSize
+			if step.Count != 0 {
+				limit = int(min(int64(batchSize), step.Count-o.StepMoved))
+			}
+
+			ids, err := o.db.FileIDs(ctx, step.From, o.LastID, limit)
+			if err != nil {
+				return fmt.Errorf("fetch files from storage %d: %w", step.From, err)
+			}
+
+			if len(ids) == 0 {
+				break
+			}
+
+			for _, id := range ids {
+				// the current move is finished even if the operation is paused
+				err := o.db.MoveFile(context.WithoutCancel(ctx), id, step.From, step.To)
+				if err != nil {
+					// the operation stops before the file so it is moved again when resumed, moving a file twice is safe
+					o.mu.Lock()
+					o.Failed++
+					o.mu.Unlock()
+
+					return fmt.Errorf("move file %s from storage %d: %w", id, step.From, err)
+				}
+
+				o.mu.Lock()
+				o.Moved++
+				o.StepMoved++
+				o.LastID = id
+				o.mu.Unlock()
+			}
+
+			o.pushToDB()
+		}
+
+		o.mu.Lock()
+		o.Step++
+		o.StepMoved = 0
+		o.LastID = ""
+		o.mu.Unlock()
+
+		o.pushToDB()
+	}
+
+	return nil
+}
+
+// pushToDB saves the progress of the operation so it can be resumed.
+func (o *Operation) pushToDB() {
+	o.mu.Lock()
+	update := map[string]interface{}{
+		"step":       o.Step,
+		"step_moved": o.StepMoved,
+		"last_id":    o.LastID,
+		"moved":      o.Moved,
+		"failed":     o.Failed,
+	}
+	o.mu.Unlock()
+
+	_, err := o.db.UpdateMigration(o.ID, update)
+	if err != nil {
+		o.log.Error("migrate: failed to update db values", zap.Error(err), zap.String("pid", o.ID))
+	}
+}
+
+// editProgress updates the progress message with the current progress followed by status.
+func (o *Operation) editProgress(m *gotgbot.Message, status string, buttons ...gotgbot.InlineKeyboardButton) {
+	opts := &gotgbot.EditMessageTextOpts{ParseMode: gotgbot.ParseModeHTML}
+	if len(buttons) != 0 {
+		opts.ReplyMarkup = gotgbot.InlineKeyboardMarkup{InlineKeyboard: [][]gotgbot.InlineKeyboardButton{buttons}}
+	}
+
+	o.mu.Lock()
+	text := Progress(o.Migration) + "\n" + status
+	o.mu.Unlock()
+
+	_, _, err := m.EditText(o.bot, text, opts)
+	if err != nil {
+		o.log.Debug("migrate: failed to update progress message", zap.Error(err), zap.String("pid", o.ID))
+	}
+}
+
+// Progress builds a short summary of the steps and progress of a migration.
+func Progress(m *model.Migration) string {
+	var b strings.Builder
+
+	for i, s := range m.Steps {
+		count := "all files"
+		if s.Count != 0 {
+			count = fmt.Sprintf("%d files", s.Count)
+		}
+
+		mark := "▫️"
+
+		switch {
+		case i < m.Step:
+			mark = "✅"
+		case i == m.Step:
+			mark = "▶️"
+		}
+
+		fmt.Fprintf(&b, "%s <b>%d ➜ %d</b> : %s\n", mark, s.From, s.To, count)
+	}
+
+	fmt.Fprintf(&b, "\n<b>Moved :</b>  %d", m.Moved)
+
+	if m.Total != 0 {
+		fmt.Fprintf(&b, " / %d (%.2f%%)", m.Total, float64(m.Moved)/float64(m.Total)*100)
+	}
+
+	fmt.Fprintf(&b, "\n<b>Failed :</b> %d\n<b>PID :</b> <code>%s</code>\n<b>Last Update :</b> %s\n", m.Failed, m.ID, time.Now().Format("Jan 02 15:04:05 MST"))
+
+	return b.String()
+}
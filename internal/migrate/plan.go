@@ -0,0 +1,112 @@
+/*
+Package migrate moves files between the storages of a database, for example when one mongodb cluster is running out of space.
+
+Files are always copied to the target before being deleted from the source and the source is read in order of file id,
+so an interrupted migration can be resumed from the last id without leaving any file behind.
+*/
+package migrate
+
+import (
+	"sort"
+
+	"github.com/Jisin0/autofilterbot/internal/database"
+	"github.com/Jisin0/autofilterbot/internal/model"
+)
+
+// Rebalance plans the moves needed so every storage holds an equal share of files.
+// If bySize is true storages are balanced by the size of stored files instead of their number.
+func Rebalance(stats []database.StorageStats, bySize bool) []model.MigrationStep {
+	if len(stats) < 2 {
+		return nil
+	}
+
+	type usage struct {
+		index  int
+		amount int64
+	}
+
+	var (
+		total   int64
+		amounts = make([]int64, len(stats))
+	)
+
+	for i, s := range stats {
+		amounts[i] = s.Files
+		if bySize {
+			amounts[i] = s.Size
+		}
+
+		total += amounts[i]
+	}
+
+	share := total / int64(len(stats))
+
+	var surplus, deficit []usage
+
+	for i, a := range amounts {
+		switch {
+		case a > share:
+			surplus = append(surplus, usage{index: i, amount: a - share})
+		case a < share:
+			deficit = append(deficit, usage{index: i, amount: share - a})
+		}
+	}
+
+	// fullest storages are emptied into the emptiest ones first
+	sort.SliceStable(surplus, func(i, j int) bool { return surplus[i].amount > surplus[j].amount })
+	sort.SliceStable(deficit, func(i, j int) bool { return deficit[i].amount > deficit[j].amount })
+
+	var steps []model.MigrationStep
+
+	for i, j := 0, 0; i < len(surplus) && j < len(deficit); {
+		amount := min(surplus[i].amount, deficit[j].amount)
+
+		count := amount
+		if bySize {
+			count = amount / averageSize(stats[surplus[i].index])
+		}
+
+		if count > 0 {
+			steps = append(steps, model.MigrationStep{From: surplus[i].index, To: deficit[j].index, Count: count})
+		}
+
+		surplus[i].amount -= amount
+		deficit[j].amount -= amount
+
+		if surplus[i].amount == 0 {
+			i++
+		}
+
+		if deficit[j].amount == 0 {
+			j++
+		}
+	}
+
+	return steps
+}
+
+// TotalFiles returns the number of files that will be moved by the steps.
+// Steps that move all files are counted using the stats.
+func TotalFiles(steps []model.MigrationStep, stats []database.StorageStats) int64 {
+	var total int64
+
+	for _, s := range steps {
+		switch {
+		case s.Count != 0:
+			total += s.Count
+		case s.From < len(stats):
+			total += stats[s.From].Files
+		}
+	}
+
+	return total
+}
+
+// averageSize returns the average size of a file in the storage, atleast 1.
+func averageSize(s database.StorageStats) int64 {
+	if s.Files == 0 || s.Size < s.Files {
+		return 1
+	}
+
+	return s.Size / s.Files
+}
@@ -0,0 +1,59 @@
+package migrate_test
+
+import (
+	"testing"
+
+	"github.com/Jisin0/autofilterbot/internal/database"
+	"github.com/Jisin0/autofilterbot/internal/migrate"
+	"github.com/Jisin0/autofilterbot/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRebalance(t *testing.T) {
+	tests := []struct {
+		name     string
+		stats    []database.StorageStats
+		bySize   bool
+		expected []model.MigrationStep
+	}{
+		{
+			name:     "single storage",
+			stats:    []database.StorageStats{{Files: 100}},
+			expected: nil,
+		},
+		{
+			name:     "balanced",
+			stats:    []database.StorageStats{{Files: 100}, {Files: 100}},
+			expected: nil,
+		},
+		{
+			name:     "one full",
+			stats:    []database.StorageStats{{Files: 900}, {Files: 0}, {Files: 0}},
+			expected: []model.MigrationStep{{From: 0, To: 1, Count: 300}, {From: 0, To: 2, Count: 300}},
+		},
+		{
+			name:     "two full",
+			stats:    []database.StorageStats{{Files: 500}, {Files: 400}, {Files: 0}},
+			expected: []model.MigrationStep{{From: 0, To: 2, Count: 200}, {From: 1, To: 2, Count: 100}},
+		},
+		{
+			name:     "by size",
+			stats:    []database.StorageStats{{Files: 100, Size: 1000}, {Files: 100, Size: 3000}},
+			bySize:   true,
+			expected: []model.MigrationStep{{From: 1, To: 0, Count: 33}},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, migrate.Rebalance(tc.stats, tc.bySize))
+		})
+	}
+}
+
+func TestTotalFiles(t *testing.T) {
+	stats := []database.StorageStats{{Files: 50}, {Files: 20}}
+	steps := []model.MigrationStep{{From: 0, To: 1}, {From: 1, To: 0, Count: 5}}
+
+	assert.Equal(t, int64(55), migrate.TotalFiles(steps, stats))
+}
@@ -0,0 +1,66 @@
+package model
+
+import (
+	"errors"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// BatchMaxPasswordLength is the maximum length of the password of a batch in bytes.
+const BatchMaxPasswordLength = 72
+
+// ErrPasswordTooLong is returned if the password of a batch is longer than BatchMaxPasswordLength.
+var ErrPasswordTooLong = errors.New("model: password is too long")
+
+// Batch is a range of channel posts shared using a link with a short id.
+type Batch struct {
+	// Short random id of the batch used in its link.
+	ID string `json:"_id" bson:"_id"`
+	// Id of the channel the posts are copied from.
+	ChatId int64 `json:"chat_id" bson:"chat_id"`
+	// Id of the first post in the batch.
+	StartMessageId int64 `json:"start" bson:"start"`
+	// Id of the last post in the batch.
+	EndMessageId int64 `json:"end" bson:"end"`
+	// Id of the admin who created the batch.
+	CreatedBy int64 `json:"created_by" bson:"created_by"`
+	// Unix time at which the batch was created.
+	CreatedAt int64 `json:"created_at" bson:"created_at"`
+	// Unix time after which the link stops working, the link never expires if zero.
+	ExpiresAt int64 `json:"expires_at" bson:"expires_at"`
+	// Bcrypt hash of the password users must send to get the batch, empty if the batch has no password.
+	Password string `json:"password" bson:"password"`
+	// Number of times the batch was sent to users.
+	Accesses int64 `json:"accesses" bson:"accesses"`
+}
+
+// IsExpired reports whether the link of the batch has expired.
+func (b *Batch) IsExpired() bool {
+	return b.ExpiresAt != 0 && time.Now().Unix() > b.ExpiresAt
+}
+
+// SetPassword saves the hash of the password users must send to get the batch.
+func (b *Batch) SetPassword(password string) error {
+	if len(password) > BatchMaxPasswordLength {
+		return ErrPasswordTooLong
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	b.Password = string(hash)
+
+	return nil
+}
+
+// CheckPassword reports whether password is the password of the batch, true if the batch has no password.
+func (b *Batch) CheckPassword(password string) bool {
+	if b.Password == "" {
+		return true
+	}
+
+	return bcrypt.CompareHashAndPassword([]byte(b.Password), []byte(password)) == nil
+}
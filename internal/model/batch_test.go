@@ -0,0 +1,50 @@
+package model_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Jisin0/autofilterbot/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatchIsExpired(t *testing.T) {
+	now := time.Now().Unix()
+
+	table := []struct {
+		name      string
+		expiresAt int64
+		expected  bool
+	}{
+		{"never expires", 0, false},
+		{"expires later", now + 3600, false},
+		{"expired", now - 3600, true},
+	}
+
+	for _, tc := range table {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, (&model.Batch{ExpiresAt: tc.expiresAt}).IsExpired())
+		})
+	}
+}
+
+func TestBatchPassword(t *testing.T) {
+	assert := assert.New(t)
+
+	b := &model.Batch{ID: "abc123"}
+	assert.True(b.CheckPassword("anything"), "batch without a password")
+
+	assert.NoError(b.SetPassword("hunter2"))
+	assert.NotContains(b.Password, "hunter2")
+	assert.True(b.CheckPassword("hunter2"))
+	assert.False(b.CheckPassword("hunter3"))
+	assert.False(b.CheckPassword(""))
+
+	other := &model.Batch{ID: "abc123"}
+	assert.NoError(other.SetPassword("hunter2"))
+	assert.NotEqual(b.Password, other.Password, "hashes should be salted")
+
+	assert.ErrorIs(b.SetPassword(strings.Repeat("a", model.BatchMaxPasswordLength+1)), model.ErrPasswordTooLong)
+	assert.True(b.CheckPassword("hunter2"), "password should not change if it's too long")
+}
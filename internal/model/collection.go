@@ -0,0 +1,81 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"slices"
+)
+
+const (
+	// CollectionDataPrefix is the prefix of start data that sends a collection, followed by the id of the collection.
+	CollectionDataPrefix = "c_"
+	// CollectionMaxFiles is the maximum number of files in a single collection.
+	CollectionMaxFiles = 50
+)
+
+// Collection is a named list of files from any channel shared using a single link.
+type Collection struct {
+	// Short random id of the collection used in its link.
+	ID string `json:"_id" bson:"_id"`
+	// Name of the collection shown to admins and users.
+	Name string `json:"name" bson:"name"`
+	// Unique ids of the files in the order they're sent.
+	Files CollectionFiles `json:"files" bson:"files"`
+	// Id of the admin who created the collection.
+	CreatedBy int64 `json:"created_by" bson:"created_by"`
+	// Unix time at which the collection was created.
+	CreatedAt int64 `json:"created_at" bson:"created_at"`
+}
+
+// StartData returns the start data of the link that sends the collection.
+func (c *Collection) StartData() string {
+	return CollectionDataPrefix + c.ID
+}
+
+// AddFiles appends files to the end of the collection and returns the number of files added.
+// Files already in the collection are skipped and no files are added once it's full.
+func (c *Collection) AddFiles(uniqueIds ...string) int {
+	var added int
+
+	for _, uid := range uniqueIds {
+		if len(c.Files) >= CollectionMaxFiles {
+			break
+		}
+
+		if slices.Contains(c.Files, uid) {
+			continue
+		}
+
+		c.Files = append(c.Files, uid)
+		added++
+	}
+
+	return added
+}
+
+// CollectionFiles are the unique ids of the files in a collection.
+type CollectionFiles []string
+
+// Value implements driver.Valuer so the files can be stored as json in sql databases.
+func (f CollectionFiles) Value() (driver.Value, error) {
+	if f == nil {
+		f = CollectionFiles{}
+	}
+
+	b, err := json.Marshal([]string(f))
+
+	return string(b), err
+}
+
+// Scan implements sql.Scanner to read files stored as json.
+func (f *CollectionFiles) Scan(src any) error {
+	switch v := src.(type) {
+	case string:
+		return json.Unmarshal([]byte(v), f)
+	case []byte:
+		return json.Unmarshal(v, f)
+	default:
+		return fmt.Errorf("collection files: unsupported type %T", src)
+	}
+}
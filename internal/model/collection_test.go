@@ -0,0 +1,63 @@
+package model_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Jisin0/autofilterbot/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollectionStartData(t *testing.T) {
+	assert.Equal(t, "c_abc123", (&model.Collection{ID: "abc123"}).StartData())
+}
+
+func TestCollectionAddFiles(t *testing.T) {
+	assert := assert.New(t)
+
+	c := &model.Collection{}
+
+	assert.Equal(2, c.AddFiles("a", "b"))
+	assert.Equal(1, c.AddFiles("b", "c", "c"), "duplicates should be skipped")
+	assert.Equal(model.CollectionFiles{"a", "b", "c"}, c.Files, "order should be kept")
+
+	for i := len(c.Files); i < model.CollectionMaxFiles; i++ {
+		c.AddFiles(fmt.Sprintf("f%d", i))
+	}
+
+	assert.Len(c.Files, model.CollectionMaxFiles)
+	assert.Zero(c.AddFiles("z"), "full collection")
+	assert.Len(c.Files, model.CollectionMaxFiles)
+
+	c = &model.Collection{}
+	ids := make([]string, model.CollectionMaxFiles+10)
+
+	for i := range ids {
+		ids[i] = fmt.Sprintf("f%d", i)
+	}
+
+	assert.Equal(model.CollectionMaxFiles, c.AddFiles(ids...), "files past the limit are dropped")
+	assert.Equal(model.CollectionFiles(ids[:model.CollectionMaxFiles]), c.Files)
+}
+
+func TestCollectionFilesSQL(t *testing.T) {
+	assert := assert.New(t)
+
+	v, err := model.CollectionFiles(nil).Value()
+	assert.NoError(err)
+	assert.Equal("[]", v)
+
+	v, err = model.CollectionFiles{"a", "b"}.Value()
+	assert.NoError(err)
+	assert.Equal(`["a","b"]`, v)
+
+	var f model.CollectionFiles
+
+	assert.NoError(f.Scan(`["a","b"]`))
+	assert.Equal(model.CollectionFiles{"a", "b"}, f)
+
+	assert.NoError(f.Scan([]byte(`["c"]`)))
+	assert.Equal(model.CollectionFiles{"c"}, f)
+
+	assert.Error(f.Scan(1))
+}
@@ -25,12 +25,18 @@ type File struct {
 	FileType string `json:"file_type" bson:"file_type"`
 	// Size of the file in bytes.
 	FileSize int64 `json:"file_size" bson:"file_size"`
+	// Lower case extension of the original file name without the dot.
+	Extension string `json:"ext,omitempty" bson:"ext,omitempty"`
 	// Unix timestamp of time when file was saved.
 	Time int64 `json:"time,omitempty" bson:"time,omitempty"`
 	// Id of the chat/channel where the file is posted.
 	ChatId int64 `json:"chat_id,omitempty" bson:"chat_id,omitempty"`
 	// Link to the original message containing the file.
 	MessageLink string `json:"file_link,omitempty" bson:"file_link,omitempty"`
+	// Metadata parsed from the original file name.
+	Release *Release `json:"release,omitempty" bson:"release,omitempty"`
+	// Number of times the file was downloaded.
+	Downloads int64 `json:"downloads,omitempty" bson:"downloads,omitempty"`
 }
 
 type SendFileOpts struct {
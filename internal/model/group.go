@@ -0,0 +1,62 @@
+package model
+
+const (
+	// GroupStatusPending is set for groups waiting to be approved by an admin.
+	GroupStatusPending = "pending"
+	// GroupStatusApproved is set for groups in which the bot works.
+	GroupStatusApproved = "approved"
+	// GroupStatusLeft is set for groups the bot was removed from or left.
+	GroupStatusLeft = "left"
+	// GroupStatusBanned is set for groups the bot leaves whenever it is added.
+	GroupStatusBanned = "banned"
+)
+
+// Group is a group chat the bot was added to.
+type Group struct {
+	// Telegram id of the group.
+	ID int64 `json:"_id" bson:"_id"`
+	// Title of the group when the bot was added.
+	Title string `json:"title" bson:"title"`
+	// Number of members in the group when the bot was added.
+	MemberCount int `json:"members" bson:"members"`
+	// Id of the user who added the bot.
+	AddedBy int64 `json:"added_by" bson:"added_by"`
+	// Unix time at which the bot was added.
+	AddedAt int64 `json:"added_at" bson:"added_at"`
+	// Status of the group, one of the GroupStatus values.
+	Status string `json:"status" bson:"status"`
+}
+
+// JoinStatus returns the status of a group the bot was added to, prev is the saved group or nil if the group is new.
+// Banned groups stay banned and groups that were not approved before are pending if approval is required.
+func JoinStatus(prev *Group, approval bool) string {
+	switch {
+	case prev != nil && prev.Status == GroupStatusBanned:
+		return GroupStatusBanned
+	case approval && (prev == nil || prev.Status != GroupStatusApproved):
+		return GroupStatusPending
+	default:
+		return GroupStatusApproved
+	}
+}
+
+// LeftStatus returns the status of the group after the bot leaves it, banned groups stay banned.
+func (g *Group) LeftStatus() string {
+	if g.Status == GroupStatusBanned {
+		return GroupStatusBanned
+	}
+
+	return GroupStatusLeft
+}
+
+// IsAllowed reports whether the bot works in the group, pending groups are allowed if approval is not required.
+func (g *Group) IsAllowed(approval bool) bool {
+	switch g.Status {
+	case GroupStatusBanned:
+		return false
+	case GroupStatusPending:
+		return !approval
+	default:
+		return true
+	}
+}
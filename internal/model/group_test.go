@@ -0,0 +1,63 @@
+package model_test
+
+import (
+	"testing"
+
+	"github.com/Jisin0/autofilterbot/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJoinStatus(t *testing.T) {
+	table := []struct {
+		name     string
+		prev     *model.Group
+		approval bool
+		expected string
+	}{
+		{"new group", nil, false, model.GroupStatusApproved},
+		{"new group with approval", nil, true, model.GroupStatusPending},
+		{"approved group with approval", &model.Group{Status: model.GroupStatusApproved}, true, model.GroupStatusApproved},
+		{"left group with approval", &model.Group{Status: model.GroupStatusLeft}, true, model.GroupStatusPending},
+		{"left group", &model.Group{Status: model.GroupStatusLeft}, false, model.GroupStatusApproved},
+		{"pending group", &model.Group{Status: model.GroupStatusPending}, true, model.GroupStatusPending},
+		{"banned group", &model.Group{Status: model.GroupStatusBanned}, false, model.GroupStatusBanned},
+		{"banned group with approval", &model.Group{Status: model.GroupStatusBanned}, true, model.GroupStatusBanned},
+	}
+
+	for _, tc := range table {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, model.JoinStatus(tc.prev, tc.approval))
+		})
+	}
+}
+
+func TestGroupLeftStatus(t *testing.T) {
+	table := []struct {
+		status   string
+		expected string
+	}{
+		{model.GroupStatusPending, model.GroupStatusLeft},
+		{model.GroupStatusApproved, model.GroupStatusLeft},
+		{model.GroupStatusLeft, model.GroupStatusLeft},
+		{model.GroupStatusBanned, model.GroupStatusBanned},
+	}
+
+	for _, tc := range table {
+		t.Run(tc.status, func(t *testing.T) {
+			assert.Equal(t, tc.expected, (&model.Group{Status: tc.status}).LeftStatus())
+		})
+	}
+}
+
+func TestGroupIsAllowed(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, approval := range []bool{false, true} {
+		assert.True((&model.Group{Status: model.GroupStatusApproved}).IsAllowed(approval))
+		assert.True((&model.Group{Status: model.GroupStatusLeft}).IsAllowed(approval))
+		assert.False((&model.Group{Status: model.GroupStatusBanned}).IsAllowed(approval))
+	}
+
+	assert.True((&model.Group{Status: model.GroupStatusPending}).IsAllowed(false), "pending groups are allowed without approval")
+	assert.False((&model.Group{Status: model.GroupStatusPending}).IsAllowed(true))
+}
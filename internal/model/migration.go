@@ -0,0 +1,84 @@
+package model
+
+import (
+	"github.com/Jisin0/autofilterbot/pkg/callbackdata"
+	"github.com/PaulSonOfLars/gotgbot/v2"
+)
+
+// OperationKindMigration is the kind of migration documents in the operations collection, index operations have no kind.
+const OperationKindMigration = "migration"
+
+// Migration is data about an operation that moves files between storages.
+type Migration struct {
+	// Unique id of operation.
+	ID string `json:"_id" bson:"_id"`
+	// Always OperationKindMigration, used to tell migrations apart from other operations.
+	Kind string `json:"kind" bson:"kind"`
+	// Moves to be made in order.
+	Steps []MigrationStep `json:"steps" bson:"steps"`
+	// Index of the step currently being run.
+	Step int `json:"step,omitempty" bson:"step,omitempty"`
+	// Number of files moved in the current step.
+	StepMoved int64 `json:"step_moved,omitempty" bson:"step_moved,omitempty"`
+	// Id of the last file moved in the current step, files are moved in order of their id.
+	LastID string `json:"last_id,omitempty" bson:"last_id,omitempty"`
+	// Estimated number of files to be moved, used to report progress.
+	Total int64 `json:"total,omitempty" bson:"total,omitempty"`
+	// Number of files successfully moved.
+	Moved int64 `json:"moved,omitempty" bson:"moved,omitempty"`
+	// Number of times moving a file failed, the operation stops at a failed file and retries it when resumed.
+	Failed int64 `json:"failed,omitempty" bson:"failed,omitempty"`
+	// Indicates whether the operation is paused.
+	IsPaused bool `json:"is_paused" bson:"is_paused"`
+
+	// Id of chat where the operation was started.
+	ProgressMessageChatID int64 `json:"pmessage_chat,omitempty" bson:"pmessage_chat,omitempty"`
+}
+
+// MigrationStep is a single move of files from one storage to another.
+type MigrationStep struct {
+	// Index of the storage to move files from.
+	From int `json:"from" bson:"from"`
+	// Index of the storage to move files to.
+	To int `json:"to" bson:"to"`
+	// Number of files to move, 0 moves all files.
+	Count int64 `json:"count,omitempty" bson:"count,omitempty"`
+}
+
+const (
+	MigrationCharStart  = "s"
+	MigrationCharPause  = "p"
+	MigrationCharCancel = "c"
+)
+
+// PauseButton returns a keyboard button that can be used to pause the operation.
+func (o *Migration) PauseButton() gotgbot.InlineKeyboardButton {
+	return gotgbot.InlineKeyboardButton{
+		Text:         "Pause ⏹️",
+		CallbackData: callbackdata.New().AddPath("migrate").AddArg(o.ID).AddArg(MigrationCharPause).ToString(),
+	}
+}
+
+// StartButton returns a keyboard button that can be used to start the operation.
+func (o *Migration) StartButton() gotgbot.InlineKeyboardButton {
+	return gotgbot.InlineKeyboardButton{
+		Text:         "Start ⚡",
+		CallbackData: callbackdata.New().AddPath("migrate").AddArg(o.ID).AddArg(MigrationCharStart).ToString(),
+	}
+}
+
+// ResumeButton does the same as the start button but sets the text to resume.
+func (o *Migration) ResumeButton() gotgbot.InlineKeyboardButton {
+	return gotgbot.InlineKeyboardButton{
+		Text:         "Resume ⏸️",
+		CallbackData: callbackdata.New().AddPath("migrate").AddArg(o.ID).AddArg(MigrationCharStart).ToString(),
+	}
+}
+
+// CancelButton returns a button that aborts the operation, files already moved are not moved back.
+func (o *Migration) CancelButton() gotgbot.InlineKeyboardButton {
+	return gotgbot.InlineKeyboardButton{
+		Text:         "Cancel ❌",
+		CallbackData: callbackdata.New().AddPath("migrate").AddArg(o.ID).AddArg(MigrationCharCancel).ToString(),
+	}
+}
@@ -18,4 +18,6 @@ type Stats struct {
 	Users  int64
 	Groups int64
 	Files  interface{} // allows for flexibility, custom types must implement fmt.Stringer
+	// Total downloads of all files.
+	Downloads int64
 }
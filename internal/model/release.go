@@ -0,0 +1,47 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// Release is metadata of a movie or series release parsed from the name of a file.
+type Release struct {
+	// Title of the movie or series without any release tags.
+	Title string `json:"title,omitempty" bson:"title,omitempty"`
+	// Year of release.
+	Year int `json:"year,omitempty" bson:"year,omitempty"`
+	// Season number of a series.
+	Season int `json:"season,omitempty" bson:"season,omitempty"`
+	// Episode number of a series.
+	Episode int `json:"episode,omitempty" bson:"episode,omitempty"`
+	// Vertical resolution one of 480p, 720p, 1080p or 2160p.
+	Resolution string `json:"resolution,omitempty" bson:"resolution,omitempty"`
+	// Source of the release like BluRay, WEB-DL or HDTV.
+	Source string `json:"source,omitempty" bson:"source,omitempty"`
+	// Video codec like x264 or x265.
+	Codec string `json:"codec,omitempty" bson:"codec,omitempty"`
+	// Audio languages in the order they appear.
+	Languages []string `json:"languages,omitempty" bson:"languages,omitempty"`
+	// Part number of a movie split into multiple files.
+	Part int `json:"part,omitempty" bson:"part,omitempty"`
+}
+
+// Value implements driver.Valuer so the release can be stored as json in sql databases.
+func (r Release) Value() (driver.Value, error) {
+	b, err := json.Marshal(r)
+	return string(b), err
+}
+
+// Scan implements sql.Scanner to read a release stored as json.
+func (r *Release) Scan(src any) error {
+	switch v := src.(type) {
+	case string:
+		return json.Unmarshal([]byte(v), r)
+	case []byte:
+		return json.Unmarshal(v, r)
+	default:
+		return fmt.Errorf("release: unsupported type %T", src)
+	}
+}
@@ -0,0 +1,244 @@
+/*
+Package release parses metadata like the title, year, season, episode and quality of a movie or series from the name of a file.
+*/
+package release
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/Jisin0/autofilterbot/internal/model"
+)
+
+var (
+	// prefixRegex matches channel usernames, websites and bracketed tags that release groups add before the title.
+	prefixRegex = regexp.MustCompile(`^\s*(?:(?:@\w+|\[[^\]]*\]|www\.\S+?\.\w{2,4})\s*[-_:|~]*\s*)+`)
+	// separatorRegex matches characters used instead of spaces in file names.
+	separatorRegex = regexp.MustCompile(`[._\[\](){}+,|~]+`)
+	// extensionRegex matches the extension of media files, other endings like .x264 are tags.
+	extensionRegex = regexp.MustCompile(`(?i)\.(?:mkv|mp4|m4v|avi|mov|webm|flv|wmv|ts|mp3|m4a|flac|wav|aac|ogg|opus|zip|rar|7z|pdf|srt)$`)
+
+	yearRegex          = regexp.MustCompile(`\b((?:19|20)\d{2})\b`)
+	seasonEpisodeRegex = regexp.MustCompile(`(?i)\bs(\d{1,2})[\s-]*e(\d{1,3})\b`)
+	crossEpisodeRegex  = regexp.MustCompile(`(?i)\b(\d{1,2})x(\d{2,3})\b`)
+	seasonRegex        = regexp.MustCompile(`(?i)\b(?:s|season[\s-]*)(\d{1,2})\b`)
+	episodeRegex       = regexp.MustCompile(`(?i)\b(?:e|ep[\s-]*|episode[\s-]*)(\d{1,3})\b`)
+	partRegex          = regexp.MustCompile(`(?i)\b(?:part|pt|cd|disc|disk)[\s-]*(\d{1,2})\b`)
+	resolutionRegex    = regexp.MustCompile(`(?i)\b(?:(480|720|1080|2160)[pi]|(4k|uhd|fhd))\b`)
+)
+
+// tag is a release tag matched by a regex and it's normalized value.
+type tag struct {
+	regex *regexp.Regexp
+	value string
+	// weak tags are common words that are only matched after the title.
+	weak bool
+}
+
+var sources = []tag{
+	{regex: regexp.MustCompile(`(?i)\b(?:blu[\s-]?ray|bd[\s-]?rip|br[\s-]?rip|bd[\s-]?remux|remux)\b`), value: "BluRay"},
+	{regex: regexp.MustCompile(`(?i)\bweb[\s-]?dl\b`), value: "WEB-DL"},
+	{regex: regexp.MustCompile(`(?i)\bweb[\s-]?rip\b`), value: "WEBRip"},
+	{regex: regexp.MustCompile(`(?i)\bhd[\s-]?rip\b`), value: "HDRip"},
+	{regex: regexp.MustCompile(`(?i)\bhdtv\b`), value: "HDTV"},
+	{regex: regexp.MustCompile(`(?i)\bpre[\s-]?dvd\b`), value: "PreDVD"},
+	{regex: regexp.MustCompile(`(?i)\bdvd[\s-]?scr\b`), value: "DVDScr"},
+	{regex: regexp.MustCompile(`(?i)\bdvd[\s-]?rip\b`), value: "DVDRip"},
+	{regex: regexp.MustCompile(`(?i)\b(?:hd[\s-]?cam|cam[\s-]?rip)\b`), value: "CAM"},
+	{regex: regexp.MustCompile(`(?i)\b(?:hd[\s-]?ts|telesync|hd[\s-]?tc)\b`), value: "TS"},
+	{regex: regexp.MustCompile(`(?i)\bdvd\b`), value: "DVDRip", weak: true},
+	{regex: regexp.MustCompile(`(?i)\bcam\b`), value: "CAM", weak: true},
+	{regex: regexp.MustCompile(`(?i)\bweb\b`), value: "WEB-DL", weak: true},
+}
+
+var codecs = []tag{
+	{regex: regexp.MustCompile(`(?i)\b(?:x[\s-]?264|h[\s-]?264|avc)\b`), value: "x264"},
+	{regex: regexp.MustCompile(`(?i)\b(?:x[\s-]?265|h[\s-]?265|hevc)\b`), value: "x265"},
+	{regex: regexp.MustCompile(`(?i)\bav1\b`), value: "AV1"},
+	{regex: regexp.MustCompile(`(?i)\bxvid\b`), value: "XviD"},
+	{regex: regexp.MustCompile(`(?i)\bvp9\b`), value: "VP9"},
+}
+
+// languageAliases are the lower case names and short codes used for each language.
+var languageAliases = map[string][]string{
+	"English":    {"english", "eng"},
+	"Hindi":      {"hindi", "hin"},
+	"Tamil":      {"tamil", "tam"},
+	"Telugu":     {"telugu", "tel"},
+	"Malayalam":  {"malayalam", "mal"},
+	"Kannada":    {"kannada", "kan"},
+	"Bengali":    {"bengali", "bangla", "ben"},
+	"Marathi":    {"marathi"},
+	"Punjabi":    {"punjabi"},
+	"Gujarati":   {"gujarati", "guj"},
+	"Urdu":       {"urdu"},
+	"Japanese":   {"japanese", "jap", "jpn"},
+	"Korean":     {"korean", "kor"},
+	"Chinese":    {"chinese", "mandarin", "chi"},
+	"Spanish":    {"spanish", "spa", "esp"},
+	"French":     {"french", "fre"},
+	"German":     {"german", "ger"},
+	"Italian":    {"italian", "ita"},
+	"Russian":    {"russian", "rus"},
+	"Arabic":     {"arabic", "ara"},
+	"Turkish":    {"turkish", "tur"},
+	"Portuguese": {"portuguese", "por"},
+	"Thai":       {"thai"},
+	"Indonesian": {"indonesian"},
+}
+
+// languages maps each alias to the name of the language.
+var languages = make(map[string]string)
+
+func init() {
+	for name, aliases := range languageAliases {
+		for _, a := range aliases {
+			languages[a] = name
+		}
+	}
+}
+
+// Parse extracts release metadata from a file name, nil is returned if the name only contains a title.
+func Parse(name string) *model.Release {
+	name = extensionRegex.ReplaceAllString(strings.TrimSpace(name), "")
+	name = prefixRegex.ReplaceAllString(name, "")
+	name = strings.Join(strings.Fields(separatorRegex.ReplaceAllString(name, " ")), " ")
+
+	var (
+		r        model.Release
+		titleEnd = len(name)
+	)
+
+	// marks the start of a strong tag as the end of the title
+	mark := func(loc []int) {
+		if loc != nil && loc[0] < titleEnd {
+			titleEnd = loc[0]
+		}
+	}
+
+	if m := seasonEpisodeRegex.FindStringSubmatchIndex(name); m != nil {
+		r.Season, r.Episode = atoi(name, m[2:4]), atoi(name, m[4:6])
+		mark(m)
+	} else if m := crossEpisodeRegex.FindStringSubmatchIndex(name); m != nil {
+		r.Season, r.Episode = atoi(name, m[2:4]), atoi(name, m[4:6])
+		mark(m)
+	} else {
+		if m := seasonRegex.FindStringSubmatchIndex(name); m != nil {
+			r.Season = atoi(name, m[2:4])
+			mark(m)
+		}
+
+		if m := episodeRegex.FindStringSubmatchIndex(name); m != nil {
+			r.Episode = atoi(name, m[2:4])
+			mark(m)
+		}
+	}
+
+	if m := resolutionRegex.FindStringSubmatchIndex(name); m != nil {
+		if m[2] != -1 {
+			r.Resolution = name[m[2]:m[3]] + "p"
+		} else if strings.EqualFold(name[m[4]:m[5]], "fhd") {
+			r.Resolution = "1080p"
+		} else {
+			r.Resolution = "2160p"
+		}
+
+		mark(m)
+	}
+
+	r.Source = matchTag(name, sources, mark)
+	r.Codec = matchTag(name, codecs, mark)
+
+	// the last year before other tags is used so that years in titles like 2012 are kept
+	maxYear := time.Now().Year() + 1
+
+	var yearLoc []int
+
+	for _, m := range yearRegex.FindAllStringSubmatchIndex(name, -1) {
+		year := atoi(name, m[2:4])
+		if m[0] == 0 || m[0] > titleEnd || year > maxYear {
+			continue
+		}
+
+		r.Year, yearLoc = year, m
+	}
+
+	// a part directly followed by the year is part of the title
+	if m := partRegex.FindStringSubmatchIndex(name); m != nil && (yearLoc == nil || m[1] > yearLoc[0] || strings.TrimSpace(name[m[1]:yearLoc[0]]) != "") {
+		r.Part = atoi(name, m[2:4])
+		mark(m)
+	}
+
+	mark(yearLoc)
+
+	// weak tags are only matched after the title
+	rest := name[titleEnd:]
+
+	if r.Source == "" {
+		r.Source = matchTag(rest, sources, nil)
+	}
+
+	for _, word := range strings.FieldsFunc(strings.ToLower(rest), func(c rune) bool { return !unicode.IsLetter(c) }) {
+		if l, ok := languages[word]; ok && !contains(r.Languages, l) {
+			r.Languages = append(r.Languages, l)
+		}
+	}
+
+	if r.Year == 0 && r.Season == 0 && r.Episode == 0 && r.Resolution == "" && r.Source == "" && r.Codec == "" && len(r.Languages) == 0 && r.Part == 0 {
+		return nil
+	}
+
+	r.Title = cleanTitle(name[:titleEnd])
+
+	return &r
+}
+
+// matchTag returns the value of the first tag found in s, weak tags are skipped if mark is not nil.
+func matchTag(s string, tags []tag, mark func([]int)) string {
+	for _, t := range tags {
+		if mark != nil && t.weak {
+			continue
+		}
+
+		if loc := t.regex.FindStringIndex(s); loc != nil {
+			if mark != nil {
+				mark(loc)
+			}
+
+			return t.value
+		}
+	}
+
+	return ""
+}
+
+// cleanTitle removes symbols other than apostrophes and ampersands from a title.
+func cleanTitle(s string) string {
+	s = strings.Map(func(c rune) rune {
+		if unicode.IsLetter(c) || unicode.IsDigit(c) || c == '\'' || c == '&' {
+			return c
+		}
+
+		return ' '
+	}, s)
+
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func atoi(s string, loc []int) int {
+	n, _ := strconv.Atoi(s[loc[0]:loc[1]])
+	return n
+}
+
+func contains(l []string, s string) bool {
+	for _, v := range l {
+		if v == s {
+			return true
+		}
+	}
+
+	return false
+}
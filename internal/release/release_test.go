@@ -0,0 +1,75 @@
+package release_test
+
+import (
+	"testing"
+
+	"github.com/Jisin0/autofilterbot/internal/model"
+	"github.com/Jisin0/autofilterbot/internal/release"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected *model.Release
+	}{
+		{
+			input:    "Avatar.The.Way.Of.Water.2022.1080p.BluRay.x264.mkv",
+			expected: &model.Release{Title: "Avatar The Way Of Water", Year: 2022, Resolution: "1080p", Source: "BluRay", Codec: "x264"},
+		},
+		{
+			input:    "@MoviesChannel - Interstellar (2014) 720p WEB-DL [Tam + Tel + Hin] HEVC.mkv",
+			expected: &model.Release{Title: "Interstellar", Year: 2014, Resolution: "720p", Source: "WEB-DL", Codec: "x265", Languages: []string{"Tamil", "Telugu", "Hindi"}},
+		},
+		{
+			input:    "Breaking_Bad_S05E14_Ozymandias_480p_HDTV.mp4",
+			expected: &model.Release{Title: "Breaking Bad", Season: 5, Episode: 14, Resolution: "480p", Source: "HDTV"},
+		},
+		{
+			input:    "[www.site.com] Dark Season 2 Episode 3 German 2160p.mkv",
+			expected: &model.Release{Title: "Dark", Season: 2, Episode: 3, Resolution: "2160p", Languages: []string{"German"}},
+		},
+		{
+			input:    "The Office 3x07 WEBRip",
+			expected: &model.Release{Title: "The Office", Season: 3, Episode: 7, Source: "WEBRip"},
+		},
+		{
+			input:    "2012 (2009) 4K UHD H.265 Dual Audio English Hindi",
+			expected: &model.Release{Title: "2012", Year: 2009, Resolution: "2160p", Codec: "x265", Languages: []string{"English", "Hindi"}},
+		},
+		{
+			input:    "Blade Runner 2049 (2017) HDRip",
+			expected: &model.Release{Title: "Blade Runner 2049", Year: 2017, Source: "HDRip"},
+		},
+		{
+			input:    "Harry Potter and the Deathly Hallows Part 2 2011 1080p",
+			expected: &model.Release{Title: "Harry Potter and the Deathly Hallows Part 2", Year: 2011, Resolution: "1080p"},
+		},
+		{
+			input:    "Lawrence of Arabia 1962 CD2 DVD XviD.avi",
+			expected: &model.Release{Title: "Lawrence of Arabia", Year: 1962, Codec: "XviD", Source: "DVDRip", Part: 2},
+		},
+		{
+			input:    "Charlotte's Web 2006 WEB",
+			expected: &model.Release{Title: "Charlotte's Web", Year: 2006, Source: "WEB-DL"},
+		},
+		{
+			input:    "Kingdom S01 Complete Korean",
+			expected: &model.Release{Title: "Kingdom", Season: 1, Languages: []string{"Korean"}},
+		},
+		{
+			input:    "My Holiday Photos.zip",
+			expected: nil,
+		},
+		{
+			input:    "",
+			expected: nil,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.input, func(t *testing.T) {
+			assert.Equal(t, tc.expected, release.Parse(tc.input))
+		})
+	}
+}
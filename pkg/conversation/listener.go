@@ -59,6 +59,20 @@ func (ls *ListenerArray) FindMatchAndDelete(m *gotgbot.Message) (*Listener, bool
 	return nil, false
 }
 
+// HasMatch reports whether any listener is waiting for the message.
+func (ls *ListenerArray) HasMatch(m *gotgbot.Message) bool {
+	ls.mu.RLock()
+	defer ls.mu.RUnlock()
+
+	for _, l := range ls.list {
+		if l.filter(m) {
+			return true
+		}
+	}
+
+	return false
+}
+
 // Delete deletes listener at index i.
 func (ls *ListenerArray) Delete(i int) {
 	ls.mu.Lock()
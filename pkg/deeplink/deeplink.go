@@ -0,0 +1,106 @@
+/*
+Package deeplink signs the start data of deep links so that links can only be created by the bot.
+
+Signed start data is the url safe base64 encoding of the payload, an optional expiry time and a truncated HMAC-SHA256 of both, prefixed with Prefix.
+*/
+package deeplink
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"strings"
+	"time"
+)
+
+// Prefix is the first character of signed start data, it never appears in standard base64 so signed data can be told apart from old links.
+const Prefix = "-"
+
+const (
+	// macSize is the number of bytes of the HMAC kept in the link, start data is limited to 64 characters.
+	macSize = 8
+	// flagExpiry is set in the header if the link has an expiry time.
+	flagExpiry byte = 1 << 0
+)
+
+var (
+	// ErrInvalid is returned if start data was not signed with the key or is malformed.
+	ErrInvalid = errors.New("deeplink: invalid link")
+	// ErrExpired is returned if the link has expired.
+	ErrExpired = errors.New("deeplink: link expired")
+)
+
+// Signer signs and verifies start data using a secret key.
+type Signer struct {
+	key []byte
+}
+
+// NewSigner creates a new Signer with the secret key.
+func NewSigner(key []byte) *Signer {
+	return &Signer{key: key}
+}
+
+// Sign returns start data containing the payload that stops working after expiry, the link never expires if expiry is zero.
+func (s *Signer) Sign(payload []byte, expiry time.Time) string {
+	b := make([]byte, 1, 1+4+len(payload)+macSize)
+
+	if !expiry.IsZero() {
+		b[0] |= flagExpiry
+		b = binary.BigEndian.AppendUint32(b, uint32(expiry.Unix()))
+	}
+
+	b = append(b, payload...)
+	b = append(b, s.mac(b)...)
+
+	return Prefix + base64.RawURLEncoding.EncodeToString(b)
+}
+
+// Verify returns the payload of start data created by Sign.
+// ErrInvalid is returned if the data was not signed by the key and ErrExpired if the link has expired.
+func (s *Signer) Verify(data string) ([]byte, error) {
+	data, ok := strings.CutPrefix(data, Prefix)
+	if !ok {
+		return nil, ErrInvalid
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(data)
+	if err != nil || len(b) < 1+macSize {
+		return nil, ErrInvalid
+	}
+
+	b, sum := b[:len(b)-macSize], b[len(b)-macSize:]
+	if !hmac.Equal(sum, s.mac(b)) {
+		return nil, ErrInvalid
+	}
+
+	header, b := b[0], b[1:]
+
+	if header&flagExpiry != 0 {
+		if len(b) < 4 {
+			return nil, ErrInvalid
+		}
+
+		if time.Now().Unix() > int64(binary.BigEndian.Uint32(b)) {
+			return nil, ErrExpired
+		}
+
+		b = b[4:]
+	}
+
+	return b, nil
+}
+
+// mac returns the truncated HMAC of b.
+func (s *Signer) mac(b []byte) []byte {
+	h := hmac.New(sha256.New, s.key)
+	h.Write(b)
+
+	return h.Sum(nil)[:macSize]
+}
+
+// IsSigned reports whether start data was created by Sign, the signature is not verified.
+func IsSigned(data string) bool {
+	return strings.HasPrefix(data, Prefix)
+}
@@ -0,0 +1,62 @@
+package deeplink_test
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/Jisin0/autofilterbot/pkg/deeplink"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSigner(t *testing.T) {
+	var (
+		s       = deeplink.NewSigner([]byte("secret"))
+		payload = []byte("f\x00AgADxQ4AAm5vMVY")
+	)
+
+	tests := []struct {
+		name    string
+		data    string
+		payload []byte
+		err     error
+	}{
+		{name: "no expiry", data: s.Sign(payload, time.Time{}), payload: payload},
+		{name: "not expired", data: s.Sign(payload, time.Now().Add(time.Hour)), payload: payload},
+		{name: "expired", data: s.Sign(payload, time.Now().Add(-time.Minute)), err: deeplink.ErrExpired},
+		{name: "other key", data: deeplink.NewSigner([]byte("other")).Sign(payload, time.Time{}), err: deeplink.ErrInvalid},
+		{name: "tampered", data: tamper(s.Sign(payload, time.Time{})), err: deeplink.ErrInvalid},
+		{name: "unsigned", data: "ZnxBZ0FEeFE0QUFtNXZNVll8LTEwMDEyMzQ1Njc4OTB8MA==", err: deeplink.ErrInvalid},
+		{name: "too short", data: deeplink.Prefix + "AAAA", err: deeplink.ErrInvalid},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			b, err := s.Verify(tc.data)
+			assert.ErrorIs(t, err, tc.err)
+			assert.Equal(t, tc.payload, b)
+		})
+	}
+}
+
+func TestSignValidStartData(t *testing.T) {
+	data := deeplink.NewSigner([]byte("secret")).Sign([]byte("f\x01\xff\xff\x8bAgADxQ4AAm5vMVYabcdef"), time.Now())
+
+	assert.True(t, deeplink.IsSigned(data))
+	assert.LessOrEqual(t, len(data), 64)
+	assert.Regexp(t, regexp.MustCompile(`^[A-Za-z0-9_-]+$`), data)
+}
+
+// tamper changes a character in the middle of data.
+func tamper(data string) string {
+	b := []byte(data)
+
+	i := len(b) / 2
+	if b[i] == 'A' {
+		b[i] = 'B'
+	} else {
+		b[i] = 'A'
+	}
+
+	return string(b)
+}
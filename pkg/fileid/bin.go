@@ -1,6 +1,10 @@
 package fileid
 
-import "encoding/binary"
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+)
 
 const (
 	// Word represents 4-byte sequence.
@@ -96,3 +100,66 @@ func encodeBytes(b, v []byte) []byte {
 
 	return b
 }
+
+// PutInt32 serializes signed 32-bit integer.
+func (b *Buffer) PutInt32(v int32) {
+	b.PutUint32(uint32(v))
+}
+
+// Uint32 decodes unsigned 32-bit integer from Buffer.
+func (b *Buffer) Uint32() (uint32, error) {
+	if len(b.Buf) < Word {
+		return 0, io.ErrUnexpectedEOF
+	}
+
+	v := binary.LittleEndian.Uint32(b.Buf)
+	b.Buf = b.Buf[Word:]
+
+	return v, nil
+}
+
+// Int32 decodes signed 32-bit integer from Buffer.
+func (b *Buffer) Int32() (int32, error) {
+	v, err := b.Uint32()
+	return int32(v), err
+}
+
+// Long decodes 64-bit signed integer from Buffer.
+func (b *Buffer) Long() (int64, error) {
+	if len(b.Buf) < Word*2 {
+		return 0, io.ErrUnexpectedEOF
+	}
+
+	v := binary.LittleEndian.Uint64(b.Buf)
+	b.Buf = b.Buf[Word*2:]
+
+	return int64(v), nil
+}
+
+// Bytes decodes byte string from Buffer.
+func (b *Buffer) Bytes() ([]byte, error) {
+	if len(b.Buf) == 0 {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	l, headerLen := int(b.Buf[0]), 1
+	if l == firstLongStringByte {
+		if len(b.Buf) < 4 {
+			return nil, io.ErrUnexpectedEOF
+		}
+
+		l, headerLen = int(b.Buf[1])|int(b.Buf[2])<<8|int(b.Buf[3])<<16, 4
+	} else if l > firstLongStringByte {
+		return nil, errors.New("invalid string length")
+	}
+
+	n := nearestPaddedValueLength(headerLen + l)
+	if len(b.Buf) < n {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	v := append([]byte(nil), b.Buf[headerLen:headerLen+l]...)
+	b.Buf = b.Buf[n:]
+
+	return v, nil
+}
@@ -0,0 +1,221 @@
+package fileid
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	// subVersionAddPhotoSizeSource is the first sub version where photos have a size source.
+	subVersionAddPhotoSizeSource = 22
+	// subVersionRemovePhotoVolumeAndLocalID is the first sub version where photos only have a size source.
+	subVersionRemovePhotoVolumeAndLocalID = 32
+)
+
+func base64Decode(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+}
+
+func rleDecode(s []byte) (r []byte) {
+	var zero bool
+
+	for _, cur := range s {
+		if zero {
+			r = append(r, make([]byte, cur)...)
+			zero = false
+
+			continue
+		}
+
+		if cur == 0 {
+			zero = true
+			continue
+		}
+
+		r = append(r, cur)
+	}
+
+	return r
+}
+
+// DecodeFileID parses a bot api file_id, file ids of all persistent versions are supported except the map version.
+// Legacy photo sizes are converted to their latest equivalent so the id can be encoded again using EncodeFileID.
+func DecodeFileID(s string) (FileID, error) {
+	var id FileID
+
+	data, err := base64Decode(s)
+	if err != nil {
+		return id, fmt.Errorf("fileid: invalid base64: %w", err)
+	}
+
+	data = rleDecode(data)
+	if len(data) == 0 {
+		return id, errors.New("fileid: empty file id")
+	}
+
+	var subVersion int
+
+	switch version := data[len(data)-1]; version {
+	case persistentIDVersionOld:
+		data = data[:len(data)-1]
+	case persistentIDVersion:
+		if len(data) < 2 {
+			return id, errors.New("fileid: missing sub version")
+		}
+
+		subVersion = int(data[len(data)-2])
+		data = data[:len(data)-2]
+	case persistentIDVersionMap:
+		return id, errors.New("fileid: map file ids are not supported")
+	default:
+		return id, fmt.Errorf("fileid: unknown version %d", version)
+	}
+
+	b := &Buffer{Buf: data}
+
+	if err := id.decodeFileID(b, subVersion); err != nil {
+		return id, fmt.Errorf("fileid: %w", err)
+	}
+
+	if len(b.Buf) != 0 {
+		return id, fmt.Errorf("fileid: %d unexpected trailing bytes", len(b.Buf))
+	}
+
+	return id, nil
+}
+
+func (f *FileID) decodeFileID(b *Buffer, subVersion int) error {
+	typeID, err := b.Uint32()
+	if err != nil {
+		return err
+	}
+
+	hasWebLocation := typeID&webLocationFlag != 0
+	hasReference := typeID&fileReferenceFlag != 0
+
+	typeID &^= webLocationFlag | fileReferenceFlag
+	if typeID >= uint32(lastType) {
+		return fmt.Errorf("unknown type %d", typeID)
+	}
+
+	f.Type = Type(typeID)
+
+	dc, err := b.Int32()
+	if err != nil {
+		return err
+	}
+
+	f.DC = int(dc)
+
+	if hasReference {
+		if f.FileReference, err = b.Bytes(); err != nil {
+			return err
+		}
+	}
+
+	if hasWebLocation {
+		url, err := b.Bytes()
+		if err != nil {
+			return err
+		}
+
+		f.URL = string(url)
+		f.AccessHash, err = b.Long()
+
+		return err
+	}
+
+	if f.ID, err = b.Long(); err != nil {
+		return err
+	}
+
+	if f.AccessHash, err = b.Long(); err != nil {
+		return err
+	}
+
+	if !isPhotoType(f.Type) {
+		return nil
+	}
+
+	if subVersion >= subVersionRemovePhotoVolumeAndLocalID {
+		return f.PhotoSizeSource.decode(b)
+	}
+
+	return f.decodeLegacyPhotoSize(b, subVersion)
+}
+
+// decodeLegacyPhotoSize decodes photos sizes of file ids that have a volume and local id.
+func (f *FileID) decodeLegacyPhotoSize(b *Buffer, subVersion int) error {
+	volumeID, err := b.Long()
+	if err != nil {
+		return err
+	}
+
+	s := &f.PhotoSizeSource
+
+	if subVersion >= subVersionAddPhotoSizeSource {
+		if err := s.decode(b); err != nil {
+			return err
+		}
+	} else {
+		if s.Secret, err = b.Long(); err != nil {
+			return err
+		}
+	}
+
+	if s.LocalID, err = b.Int32(); err != nil {
+		return err
+	}
+
+	s.VolumeID = volumeID
+
+	switch s.Type {
+	case PhotoSizeSourceLegacy:
+		s.Type = PhotoSizeSourceFullLegacy
+	case PhotoSizeSourceDialogPhotoSmall:
+		s.Type = PhotoSizeSourceDialogPhotoSmallLegacy
+	case PhotoSizeSourceDialogPhotoBig:
+		s.Type = PhotoSizeSourceDialogPhotoBigLegacy
+	case PhotoSizeSourceStickerSetThumbnail:
+		s.Type = PhotoSizeSourceStickerSetThumbnailLegacy
+	case PhotoSizeSourceThumbnail:
+		s.VolumeID, s.LocalID = 0, 0 // thumbnails are found using the file they belong to
+	default:
+		return fmt.Errorf("photo size source %d is not valid before sub version %d", s.Type, subVersionRemovePhotoVolumeAndLocalID)
+	}
+
+	return nil
+}
+
+// ValidateFileID reports whether s is a file id that can be used to send a file, an error describing the problem is returned otherwise.
+func ValidateFileID(s string) error {
+	id, err := DecodeFileID(s)
+	if err != nil {
+		return err
+	}
+
+	return id.Validate()
+}
+
+// Validate checks that the fields of a decoded file id are within the ranges used by telegram.
+func (f FileID) Validate() error {
+	if f.URL != "" {
+		return nil
+	}
+
+	if f.DC < 1 || f.DC > 5 {
+		return fmt.Errorf("fileid: invalid dc %d", f.DC)
+	}
+
+	if f.ID == 0 {
+		return errors.New("fileid: missing id")
+	}
+
+	if isPhotoType(f.Type) && f.PhotoSizeSource.Type == PhotoSizeSourceThumbnail && f.PhotoSizeSource.FileType >= lastType {
+		return fmt.Errorf("fileid: invalid thumbnail file type %d", f.PhotoSizeSource.FileType)
+	}
+
+	return nil
+}
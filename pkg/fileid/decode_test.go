@@ -0,0 +1,136 @@
+package fileid_test
+
+import (
+	"testing"
+
+	"github.com/Jisin0/autofilterbot/pkg/fileid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeFileID(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected fileid.FileID
+	}{
+		{
+			name:  "document",
+			input: "BQACAgQAAw4CAAIKj2XgvRxbodni4wABNgkYRl4k-UrmAWcw_____x4E",
+			expected: fileid.FileID{
+				Type:          fileid.Document,
+				DC:            4,
+				ID:            5402389215329126710,
+				AccessHash:    -3482910234,
+				FileReference: []byte{0x02, 0x00, 0x00, 0x0a, 0x8f, 0x65, 0xe0, 0xbd, 0x1c, 0x5b, 0xa1, 0xd9, 0xe2, 0xe3},
+			},
+		},
+		{
+			name:     "legacy python bot id",
+			input:    "BAADBQADAQAHAgAHFgQ",
+			expected: fileid.FileID{Type: fileid.Video, DC: 5, ID: 1, AccessHash: 2},
+		},
+		{
+			name:  "photo thumbnail",
+			input: "AgACAgIAAwMBAgMKwKVEhZBRVrsfAAa7DQMABQEAAwIAA3gAA00AAx4E",
+			expected: fileid.FileID{
+				Type:            fileid.Photo,
+				DC:              2,
+				ID:              6219911462432129034,
+				AccessHash:      8123,
+				FileReference:   []byte{1, 2, 3},
+				PhotoSizeSource: fileid.PhotoSizeSource{Type: fileid.PhotoSizeSourceThumbnail, FileType: fileid.Photo, ThumbnailType: 'x'},
+			},
+		},
+		{
+			name:  "chat photo",
+			input: "AQADAQADKgAHKwAHhAMABgMAAy7txOEW____NwAHDAADHgQ",
+			expected: fileid.FileID{
+				Type:       fileid.ProfilePhoto,
+				DC:         1,
+				ID:         42,
+				AccessHash: 43,
+				PhotoSizeSource: fileid.PhotoSizeSource{
+					Type:             fileid.PhotoSizeSourceDialogPhotoBigLegacy,
+					DialogID:         -1001234567890,
+					DialogAccessHash: 55,
+					VolumeID:         900,
+					LocalID:          12,
+				},
+			},
+		},
+		{
+			name:  "version 2 photo",
+			input: "AgADAwADCgAHCwAHDAAHDQAHDgADAg",
+			expected: fileid.FileID{
+				Type:            fileid.Photo,
+				DC:              3,
+				ID:              10,
+				AccessHash:      11,
+				PhotoSizeSource: fileid.PhotoSizeSource{Type: fileid.PhotoSizeSourceFullLegacy, VolumeID: 12, Secret: 13, LocalID: 14},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			id, err := fileid.DecodeFileID(tc.input)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, id)
+			assert.NoError(t, id.Validate())
+
+			// decoded ids should survive being encoded in the latest version
+			s, err := fileid.EncodeFileID(id)
+			assert.NoError(t, err)
+
+			again, err := fileid.DecodeFileID(s)
+			assert.NoError(t, err)
+			assert.Equal(t, id, again)
+		})
+	}
+}
+
+func TestEncodeDecodeFileID(t *testing.T) {
+	ids := []fileid.FileID{
+		{Type: fileid.Audio, DC: 1, ID: -1, AccessHash: 1 << 40},
+		{Type: fileid.Document, DC: 5, ID: 7, AccessHash: 8, FileReference: make([]byte, 300)},
+		{Type: fileid.Thumbnail, DC: 2, ID: 3, AccessHash: 4, PhotoSizeSource: fileid.PhotoSizeSource{Type: fileid.PhotoSizeSourceStickerSetThumbnailVersion, StickerSetID: 9, StickerSetAccessHash: 10, StickerSetVersion: 11}},
+		{Type: fileid.Photo, DC: 4, URL: "https://example.com/a.jpg", AccessHash: 12},
+	}
+
+	for _, id := range ids {
+		s, err := fileid.EncodeFileID(id)
+		assert.NoError(t, err)
+
+		decoded, err := fileid.DecodeFileID(s)
+		assert.NoError(t, err)
+		assert.Equal(t, id, decoded)
+	}
+}
+
+func TestValidateFileID(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		valid bool
+	}{
+		{name: "valid", input: "BQACAgQAAw4CAAIKj2XgvRxbodni4wABNgkYRl4k-UrmAWcw_____x4E", valid: true},
+		{name: "padded", input: "BAADBQADAQAHAgAHFgQ=", valid: true},
+		{name: "empty", input: ""},
+		{name: "not base64", input: "BQAC*gQA"},
+		{name: "truncated", input: "BQACAgQAAw4CAAIKj2XgvRxbodni4wABNgkYRl4k"},
+		{name: "unknown version", input: "BAADBQADAQAHAgAHFgU"},
+		{name: "random id", input: "AgADa"},
+		{name: "invalid dc", input: "BAADCQADAQAHAgAHFgQ"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := fileid.ValidateFileID(tc.input)
+			if tc.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
@@ -13,6 +13,8 @@ type FileID struct {
 	AccessHash    int64
 	FileReference []byte
 	URL           string
+	// Size of the photo, only set for photos and thumbnails.
+	PhotoSizeSource PhotoSizeSource
 }
 
 const (
@@ -33,6 +35,12 @@ func rleEncode(s []byte) (r []byte) {
 	for _, cur := range s {
 		if cur == 0 {
 			count++
+
+			if count == 255 { // the count is a single byte so longer runs are split
+				r = append(r, 0, count)
+				count = 0
+			}
+
 			continue
 		}
 
@@ -69,10 +77,15 @@ func (f *FileID) encodeLatestFileID(b *Buffer) {
 	}
 	if hasWebLocation {
 		b.PutString(f.URL)
-		return
+		b.PutLong(f.AccessHash)
+	} else {
+		b.PutLong(f.ID)
+		b.PutLong(f.AccessHash)
+
+		if isPhotoType(f.Type) {
+			f.PhotoSizeSource.encode(b)
+		}
 	}
-	b.PutLong(f.ID)
-	b.PutLong(f.AccessHash)
 
 	b.Buf = append(b.Buf, latestSubVersion)
 }
@@ -0,0 +1,164 @@
+package fileid
+
+import "fmt"
+
+// PhotoSizeSourceType is the kind of photo size a photo file id refers to.
+type PhotoSizeSourceType int32
+
+const (
+	// PhotoSizeSourceLegacy is a photo size of a file id created before sources were added.
+	PhotoSizeSourceLegacy PhotoSizeSourceType = iota
+	// PhotoSizeSourceThumbnail is a thumbnail of a photo or document.
+	PhotoSizeSourceThumbnail
+	// PhotoSizeSourceDialogPhotoSmall is the small profile photo of a chat.
+	PhotoSizeSourceDialogPhotoSmall
+	// PhotoSizeSourceDialogPhotoBig is the big profile photo of a chat.
+	PhotoSizeSourceDialogPhotoBig
+	// PhotoSizeSourceStickerSetThumbnail is the thumbnail of a sticker set.
+	PhotoSizeSourceStickerSetThumbnail
+	// PhotoSizeSourceFullLegacy is a legacy photo size that includes the volume and local id.
+	PhotoSizeSourceFullLegacy
+	// PhotoSizeSourceDialogPhotoSmallLegacy is the small profile photo of a chat with it's volume and local id.
+	PhotoSizeSourceDialogPhotoSmallLegacy
+	// PhotoSizeSourceDialogPhotoBigLegacy is the big profile photo of a chat with it's volume and local id.
+	PhotoSizeSourceDialogPhotoBigLegacy
+	// PhotoSizeSourceStickerSetThumbnailLegacy is the thumbnail of a sticker set with it's volume and local id.
+	PhotoSizeSourceStickerSetThumbnailLegacy
+	// PhotoSizeSourceStickerSetThumbnailVersion is a versioned thumbnail of a sticker set.
+	PhotoSizeSourceStickerSetThumbnailVersion
+	lastPhotoSizeSource
+)
+
+// PhotoSizeSource describes which size of a photo a file id refers to, only fields used by the source type are set.
+type PhotoSizeSource struct {
+	Type PhotoSizeSourceType
+	// Secret of legacy photos.
+	Secret int64
+	// Type of the file the thumbnail belongs to.
+	FileType Type
+	// Size of the thumbnail like 's', 'm' or 'x'.
+	ThumbnailType int32
+	// Id and access hash of the chat for profile photos.
+	DialogID         int64
+	DialogAccessHash int64
+	// Id and access hash of the sticker set for sticker set thumbnails.
+	StickerSetID         int64
+	StickerSetAccessHash int64
+	// Version of a sticker set thumbnail.
+	StickerSetVersion int32
+	// Volume and local id of legacy photos.
+	VolumeID int64
+	LocalID  int32
+}
+
+// isPhotoType reports whether file ids of the type contain a photo size source.
+func isPhotoType(t Type) bool {
+	switch t {
+	case Thumbnail, ProfilePhoto, Photo, EncryptedThumbnail, Wallpaper:
+		return true
+	default:
+		return false
+	}
+}
+
+func (s *PhotoSizeSource) encode(b *Buffer) {
+	b.PutInt32(int32(s.Type))
+
+	switch s.Type {
+	case PhotoSizeSourceLegacy:
+		b.PutLong(s.Secret)
+	case PhotoSizeSourceThumbnail:
+		b.PutUint32(uint32(s.FileType))
+		b.PutInt32(s.ThumbnailType)
+	case PhotoSizeSourceDialogPhotoSmall, PhotoSizeSourceDialogPhotoBig:
+		b.PutLong(s.DialogID)
+		b.PutLong(s.DialogAccessHash)
+	case PhotoSizeSourceStickerSetThumbnail:
+		b.PutLong(s.StickerSetID)
+		b.PutLong(s.StickerSetAccessHash)
+	case PhotoSizeSourceFullLegacy:
+		b.PutLong(s.VolumeID)
+		b.PutLong(s.Secret)
+		b.PutInt32(s.LocalID)
+	case PhotoSizeSourceDialogPhotoSmallLegacy, PhotoSizeSourceDialogPhotoBigLegacy:
+		b.PutLong(s.DialogID)
+		b.PutLong(s.DialogAccessHash)
+		b.PutLong(s.VolumeID)
+		b.PutInt32(s.LocalID)
+	case PhotoSizeSourceStickerSetThumbnailLegacy:
+		b.PutLong(s.StickerSetID)
+		b.PutLong(s.StickerSetAccessHash)
+		b.PutLong(s.VolumeID)
+		b.PutInt32(s.LocalID)
+	case PhotoSizeSourceStickerSetThumbnailVersion:
+		b.PutLong(s.StickerSetID)
+		b.PutLong(s.StickerSetAccessHash)
+		b.PutInt32(s.StickerSetVersion)
+	}
+}
+
+func (s *PhotoSizeSource) decode(b *Buffer) (err error) {
+	t, err := b.Int32()
+	if err != nil {
+		return err
+	}
+
+	if t < 0 || PhotoSizeSourceType(t) >= lastPhotoSizeSource {
+		return fmt.Errorf("unknown photo size source %d", t)
+	}
+
+	s.Type = PhotoSizeSourceType(t)
+
+	// errors are collected so each field doesn't need to be checked
+	long := func() int64 {
+		var v int64
+		if err == nil {
+			v, err = b.Long()
+		}
+
+		return v
+	}
+
+	i32 := func() int32 {
+		var v int32
+		if err == nil {
+			v, err = b.Int32()
+		}
+
+		return v
+	}
+
+	switch s.Type {
+	case PhotoSizeSourceLegacy:
+		s.Secret = long()
+	case PhotoSizeSourceThumbnail:
+		s.FileType = Type(i32())
+		s.ThumbnailType = i32()
+	case PhotoSizeSourceDialogPhotoSmall, PhotoSizeSourceDialogPhotoBig:
+		s.DialogID = long()
+		s.DialogAccessHash = long()
+	case PhotoSizeSourceStickerSetThumbnail:
+		s.StickerSetID = long()
+		s.StickerSetAccessHash = long()
+	case PhotoSizeSourceFullLegacy:
+		s.VolumeID = long()
+		s.Secret = long()
+		s.LocalID = i32()
+	case PhotoSizeSourceDialogPhotoSmallLegacy, PhotoSizeSourceDialogPhotoBigLegacy:
+		s.DialogID = long()
+		s.DialogAccessHash = long()
+		s.VolumeID = long()
+		s.LocalID = i32()
+	case PhotoSizeSourceStickerSetThumbnailLegacy:
+		s.StickerSetID = long()
+		s.StickerSetAccessHash = long()
+		s.VolumeID = long()
+		s.LocalID = i32()
+	case PhotoSizeSourceStickerSetThumbnailVersion:
+		s.StickerSetID = long()
+		s.StickerSetAccessHash = long()
+		s.StickerSetVersion = i32()
+	}
+
+	return err
+}
@@ -0,0 +1,60 @@
+package fileid
+
+import "fmt"
+
+// UniqueType represents the type of a file_unique_id, several file types share the same unique type.
+type UniqueType int
+
+const (
+	// UniqueWeb is the unique type of files with a web location.
+	UniqueWeb UniqueType = iota
+	// UniquePhoto is the unique type of photos and thumbnails.
+	UniquePhoto
+	// UniqueDocument is the unique type of documents, videos, audio, voice messages, stickers and animations.
+	UniqueDocument
+	// UniqueSecure is the unique type of telegram passport files.
+	UniqueSecure
+	// UniqueEncrypted is the unique type of files from secret chats.
+	UniqueEncrypted
+	// UniqueTemp is the unique type of temporary files.
+	UniqueTemp
+)
+
+// UniqueType returns the unique type of the file type, ok is false for photos and unknown types.
+func (t Type) UniqueType() (u UniqueType, ok bool) {
+	switch t {
+	case Voice, Video, Document, Sticker, Audio, Animation, VideoNote, DocumentAsFile, Background:
+		return UniqueDocument, true
+	case Secure, SecureRaw:
+		return UniqueSecure, true
+	case Encrypted:
+		return UniqueEncrypted, true
+	case Temp:
+		return UniqueTemp, true
+	default:
+		return 0, false
+	}
+}
+
+// EncodeUniqueID returns the file_unique_id of the file which is the same for a file regardless of the bot or user that fetched it.
+// Photos are not supported since their unique id depends on the photo size.
+func EncodeUniqueID(id FileID) (string, error) {
+	var buf Buffer
+
+	if id.URL != "" {
+		buf.PutUint32(uint32(UniqueWeb))
+		buf.PutString(id.URL)
+
+		return base64Encode(rleEncode(buf.Buf)), nil
+	}
+
+	u, ok := id.Type.UniqueType()
+	if !ok {
+		return "", fmt.Errorf("fileid: unique id of type %d is not supported", id.Type)
+	}
+
+	buf.PutUint32(uint32(u))
+	buf.PutLong(id.ID)
+
+	return base64Encode(rleEncode(buf.Buf)), nil
+}
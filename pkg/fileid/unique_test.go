@@ -0,0 +1,63 @@
+package fileid_test
+
+import (
+	"testing"
+
+	"github.com/Jisin0/autofilterbot/pkg/fileid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodeUniqueID(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    fileid.FileID
+		expected string
+	}{
+		{
+			name:     "document",
+			input:    fileid.FileID{Type: fileid.Document, DC: 4, ID: 5402389215329126710, AccessHash: -3482910234, FileReference: []byte{1, 2, 3}},
+			expected: "AgADNgkYRl4k-Uo",
+		},
+		{
+			name:     "video",
+			input:    fileid.FileID{Type: fileid.Video, DC: 5, ID: 6219911462432129034, AccessHash: 8123},
+			expected: "AgADCsClRIWQUVY",
+		},
+		{
+			name:     "audio",
+			input:    fileid.FileID{Type: fileid.Audio, DC: 1, ID: 1},
+			expected: "AgADAQAH",
+		},
+		{
+			name:     "voice",
+			input:    fileid.FileID{Type: fileid.Voice, DC: 2, ID: 5402389215329126710},
+			expected: "AgADNgkYRl4k-Uo",
+		},
+		{
+			name:     "trailing zeros",
+			input:    fileid.FileID{Type: fileid.Document, ID: -4611686018427387904},
+			expected: "AgAKwA",
+		},
+		{
+			name:     "encrypted",
+			input:    fileid.FileID{Type: fileid.Encrypted, ID: 1234567890123},
+			expected: "BAADywT7cR8BAAI",
+		},
+		{
+			name:     "web",
+			input:    fileid.FileID{Type: fileid.Photo, URL: "https://example.com/a.jpg"},
+			expected: "AAQZaHR0cHM6Ly9leGFtcGxlLmNvbS9hLmpwZwAC",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			id, err := fileid.EncodeUniqueID(tc.input)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, id)
+		})
+	}
+
+	_, err := fileid.EncodeUniqueID(fileid.FileID{Type: fileid.Photo, ID: 1})
+	assert.Error(t, err, "photos are not supported")
+}
@@ -0,0 +1,211 @@
+/*
+Package fuzzy implements a vocabulary of words that can be searched for the closest matches to misspelled words.
+
+Candidates are found using a trigram index and then ranked by their edit distance and frequency.
+*/
+package fuzzy
+
+import (
+	"sort"
+	"sync"
+)
+
+// Vocabulary is a set of words and their frequencies, safe for concurrent use.
+type Vocabulary struct {
+	mu sync.RWMutex
+
+	// words maps each word to the number of times it was added.
+	words map[string]int
+	// trigrams maps a trigram to all words containing it.
+	trigrams map[string][]string
+}
+
+// NewVocabulary creates a new empty vocabulary.
+func NewVocabulary() *Vocabulary {
+	return &Vocabulary{
+		words:    make(map[string]int),
+		trigrams: make(map[string][]string),
+	}
+}
+
+// Add adds words to the vocabulary, words should already be normalized (lower case etc.).
+func (v *Vocabulary) Add(words ...string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	for _, w := range words {
+		if w == "" {
+			continue
+		}
+
+		if v.words[w] == 0 {
+			for _, t := range trigrams(w) {
+				v.trigrams[t] = append(v.trigrams[t], w)
+			}
+		}
+
+		v.words[w]++
+	}
+}
+
+// Replace replaces all words in the vocabulary with the words in other, other should not be used afterwards.
+func (v *Vocabulary) Replace(other *Vocabulary) {
+	other.mu.RLock()
+	words, trigrams := other.words, other.trigrams
+	other.mu.RUnlock()
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.words, v.trigrams = words, trigrams
+}
+
+// Contains reports whether the word is in the vocabulary.
+func (v *Vocabulary) Contains(word string) bool {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	return v.words[word] != 0
+}
+
+// Len returns the number of unique words in the vocabulary.
+func (v *Vocabulary) Len() int {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	return len(v.words)
+}
+
+// Closest returns upto n words closest to word, closest first.
+// Only words within MaxDistance of the word are returned, the word itself is never returned.
+func (v *Vocabulary) Closest(word string, n int) []string {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	type match struct {
+		word     string
+		distance int
+		shared   int
+		count    int
+	}
+
+	// count trigrams shared with each candidate
+	shared := make(map[string]int)
+
+	for _, t := range trigrams(word) {
+		for _, w := range v.trigrams[t] {
+			shared[w]++
+		}
+	}
+
+	maxDistance := MaxDistance(word)
+	matches := make([]match, 0)
+
+	for w, s := range shared {
+		if w == word || abs(len([]rune(w))-len([]rune(word))) > maxDistance {
+			continue
+		}
+
+		d := Distance(word, w)
+		if d > maxDistance {
+			continue
+		}
+
+		matches = append(matches, match{word: w, distance: d, shared: s, count: v.words[w]})
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+
+		switch {
+		case a.distance != b.distance:
+			return a.distance < b.distance
+		case a.shared != b.shared:
+			return a.shared > b.shared
+		case a.count != b.count:
+			return a.count > b.count
+		default:
+			return a.word < b.word
+		}
+	})
+
+	if len(matches) > n {
+		matches = matches[:n]
+	}
+
+	result := make([]string, len(matches))
+	for i, m := range matches {
+		result[i] = m.word
+	}
+
+	return result
+}
+
+// MaxDistance returns the maximum edit distance tolerated for a word based on it's length.
+func MaxDistance(word string) int {
+	switch l := len([]rune(word)); {
+	case l < 3:
+		return 0
+	case l < 6:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// trigrams returns the trigrams of the word padded with spaces so short words and word boundaries are represented.
+func trigrams(word string) []string {
+	r := []rune(" " + word + " ")
+	if len(r) < 3 {
+		return nil
+	}
+
+	t := make([]string, 0, len(r)-2)
+
+	for i := 0; i+3 <= len(r); i++ {
+		t = append(t, string(r[i:i+3]))
+	}
+
+	return t
+}
+
+// Distance returns the edit distance between a and b where insertions, deletions, substitutions and transpositions of adjacent characters all cost 1.
+func Distance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+
+	// d[i][j] is the distance between the first i runes of a and first j runes of b
+	d := make([][]int, len(ra)+1)
+	for i := range d {
+		d[i] = make([]int, len(rb)+1)
+		d[i][0] = i
+	}
+
+	for j := range d[0] {
+		d[0][j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+
+	return d[len(ra)][len(rb)]
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+
+	return n
+}
@@ -0,0 +1,57 @@
+package fuzzy_test
+
+import (
+	"testing"
+
+	"github.com/Jisin0/autofilterbot/pkg/fuzzy"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDistance(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{a: "avatar", b: "avatar", expected: 0},
+		{a: "avatr", b: "avatar", expected: 1},
+		{a: "avtaar", b: "avatar", expected: 1},
+		{a: "intrstelar", b: "interstellar", expected: 2},
+		{a: "", b: "abc", expected: 3},
+		{a: "kitten", b: "sitting", expected: 3},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.a+"_"+tc.b, func(t *testing.T) {
+			assert.Equal(t, tc.expected, fuzzy.Distance(tc.a, tc.b))
+		})
+	}
+}
+
+func TestClosest(t *testing.T) {
+	assert := assert.New(t)
+
+	v := fuzzy.NewVocabulary()
+	v.Add("avatar", "avatar", "avatars", "interstellar", "inception", "water", "way", "of", "the")
+
+	assert.True(v.Contains("avatar"))
+	assert.False(v.Contains("avatr"))
+	assert.Equal(8, v.Len())
+
+	tests := []struct {
+		word     string
+		expected []string
+	}{
+		{word: "avatr", expected: []string{"avatar"}},
+		{word: "avtaar", expected: []string{"avatar", "avatars"}},
+		{word: "intersteller", expected: []string{"interstellar"}},
+		{word: "watr", expected: []string{"water"}},
+		{word: "xyzzy", expected: []string{}},
+		{word: "te", expected: []string{}}, // short words are not corrected
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.word, func(t *testing.T) {
+			assert.Equal(tc.expected, v.Closest(tc.word, 3))
+		})
+	}
+}
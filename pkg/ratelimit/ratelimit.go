@@ -0,0 +1,92 @@
+/*
+Package ratelimit implements a sliding window rate limiter to limit how often a user can make requests.
+*/
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Limiter counts requests of each key in a sliding window, safe for concurrent use.
+type Limiter struct {
+	mu     sync.Mutex
+	window time.Duration
+
+	// requests maps a key to the times of its requests in the current window, oldest first.
+	requests map[int64][]time.Time
+}
+
+// New creates a new Limiter that counts requests made in the last window.
+func New(window time.Duration) *Limiter {
+	return &Limiter{
+		window:   window,
+		requests: make(map[int64][]time.Time),
+	}
+}
+
+// Allow reports whether key can make a request if at most limit requests are allowed in a window, the request is counted if it's allowed.
+// If the request is not allowed, the time to wait before the next request is returned.
+func (l *Limiter) Allow(key int64, limit int) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+
+	requests := l.requests[key]
+
+	// drop requests that are out of the window
+	i := 0
+	for i < len(requests) && now.Sub(requests[i]) >= l.window {
+		i++
+	}
+
+	requests = requests[i:]
+
+	if len(requests) >= limit {
+		l.requests[key] = requests
+		return false, l.window - now.Sub(requests[len(requests)-limit])
+	}
+
+	l.requests[key] = append(requests, now)
+
+	return true, 0
+}
+
+// Cleanup removes keys without any requests in the current window, it should be called periodically to free memory.
+func (l *Limiter) Cleanup() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+
+	for key, requests := range l.requests {
+		if len(requests) == 0 || now.Sub(requests[len(requests)-1]) >= l.window {
+			delete(l.requests, key)
+		}
+	}
+}
+
+// Run calls Cleanup every interval until ctx is done.
+func (l *Limiter) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			l.Cleanup()
+		}
+	}
+}
+
+// Len returns the number of keys being tracked.
+func (l *Limiter) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return len(l.requests)
+}
@@ -0,0 +1,40 @@
+package ratelimit_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Jisin0/autofilterbot/pkg/ratelimit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimiter(t *testing.T) {
+	assert := assert.New(t)
+
+	const window = 100 * time.Millisecond
+
+	l := ratelimit.New(window)
+
+	for i := 0; i < 3; i++ {
+		ok, _ := l.Allow(1, 3)
+		assert.True(ok, "request %d", i)
+	}
+
+	ok, wait := l.Allow(1, 3)
+	assert.False(ok)
+	assert.True(wait > 0 && wait <= window, "wait %s", wait)
+
+	// other keys have their own limit
+	ok, _ = l.Allow(2, 3)
+	assert.True(ok)
+
+	time.Sleep(wait)
+
+	ok, _ = l.Allow(1, 3)
+	assert.True(ok, "request after window")
+
+	time.Sleep(window)
+
+	l.Cleanup()
+	assert.Equal(0, l.Len())
+}